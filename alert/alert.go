@@ -0,0 +1,92 @@
+// Package alert routes the events of the client watchers to on-call
+// destinations: http webhooks, email, shell commands and local files
+package alert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Severity is the urgency of an alert
+type Severity int
+
+const (
+	// Info is used for recoveries and other events needing no action
+	Info Severity = iota
+	// Warn is used for accounts nearing liquidation
+	Warn
+	// Critical is used for accounts that can be liquidated, and escalated warnings
+	Critical
+)
+
+// String returns the lowercase name of the severity
+func (s Severity) String() string {
+	switch s {
+	case Warn:
+		return "warn"
+	case Critical:
+		return "critical"
+	default:
+		return "info"
+	}
+}
+
+// MarshalText encodes the severity as its name
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity from its name
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// ParseSeverity returns the severity with the given name, an empty name is Info
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(name) {
+	case "", "info":
+		return Info, nil
+	case "warn", "warning":
+		return Warn, nil
+	case "critical":
+		return Critical, nil
+	default:
+		return Info, fmt.Errorf("unknown severity %q", name)
+	}
+}
+
+// Alert is a single notification sent to the sinks
+type Alert struct {
+	// Key identifies what the alert is about, ex. an account address, and is
+	// used for deduplication and escalation
+	Key      string   `json:"key"`
+	Severity Severity `json:"severity"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	// Source names the watcher that raised the alert
+	Source string            `json:"source,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+	Time   time.Time         `json:"time"`
+	// Escalated is set on warn alerts raised to critical by the router
+	Escalated bool `json:"escalated,omitempty"`
+}
+
+// Sink is used to deliver alerts to a destination
+type Sink interface {
+	Send(ctx context.Context, alert Alert) error
+}
+
+// SinkFunc adapts a function to a Sink
+type SinkFunc func(ctx context.Context, alert Alert) error
+
+// Send calls f
+func (f SinkFunc) Send(ctx context.Context, alert Alert) error {
+	return f(ctx, alert)
+}
@@ -0,0 +1,56 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/musinit/go-defi/v2/config"
+)
+
+// DefaultCommandTimeout is how long a command sink waits for its program
+const DefaultCommandTimeout = time.Second * 30
+
+// CommandSink is used to run a program for every alert
+type CommandSink struct {
+	cfg config.Command
+}
+
+// NewCommandSink returns a sink running the given command
+func NewCommandSink(cfg config.Command) (*CommandSink, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("command path is empty")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultCommandTimeout
+	}
+	return &CommandSink{cfg: cfg}, nil
+}
+
+// Send runs the command with the alert as json on its standard input, and
+// ALERT_KEY, ALERT_SEVERITY, ALERT_TITLE and ALERT_MESSAGE in its environment
+func (s *CommandSink) Send(ctx context.Context, alert Alert) error {
+	input, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, s.cfg.Path, s.cfg.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"ALERT_KEY="+alert.Key,
+		"ALERT_SEVERITY="+alert.Severity.String(),
+		"ALERT_TITLE="+alert.Title,
+		"ALERT_MESSAGE="+alert.Message,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", s.cfg.Path, err, bytes.TrimSpace(out))
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/musinit/go-defi/v2/client"
+)
+
+// stateSeverity maps a health state to the severity of its alert
+func stateSeverity(state client.HealthState) Severity {
+	switch state {
+	case client.HealthRisk:
+		return Critical
+	case client.HealthWarn:
+		return Warn
+	default:
+		return Info
+	}
+}
+
+// FromHealthEvent returns the alert of a PortfolioWatcher event, failed reads
+// are warnings
+func FromHealthEvent(event client.HealthEvent) Alert {
+	alert := Alert{
+		Key:    strings.ToLower(event.Address),
+		Source: event.Source,
+		Time:   event.Time,
+		Fields: map[string]string{
+			"address": event.Address,
+			"health":  event.Health.String(),
+			"state":   event.State.String(),
+		},
+	}
+	if event.Err != nil {
+		alert.Severity = Warn
+		alert.Title = fmt.Sprintf("failed to read account %s", event.Address)
+		alert.Message = event.Err.Error()
+		return alert
+	}
+	alert.Severity = stateSeverity(event.State)
+	alert.Title = fmt.Sprintf("account %s is %s", event.Address, event.State)
+	alert.Message = fmt.Sprintf("account %s health went from %s to %s", event.Address, event.PreviousHealth, event.Health)
+	alert.Fields["previous_health"] = event.PreviousHealth.String()
+	alert.Fields["previous_state"] = event.PreviousState.String()
+	return alert
+}
+
+// FromLiquidityEvent returns the alert of a LiquidityMonitor event, accounts
+// with a shortfall are critical and failed reads are warnings
+func FromLiquidityEvent(event client.LiquidityEvent) Alert {
+	alert := Alert{
+		Source: "chain",
+		Time:   event.Time,
+		Fields: map[string]string{"block": fmt.Sprint(event.Block)},
+	}
+	if event.Err != nil {
+		alert.Key = "liquidity-monitor"
+		alert.Severity = Warn
+		alert.Title = fmt.Sprintf("failed to read block %d", event.Block)
+		alert.Message = event.Err.Error()
+		return alert
+	}
+	address := strings.ToLower(event.Account.Hex())
+	alert.Key = address
+	alert.Fields["address"] = address
+	alert.Fields["liquidity"] = event.Liquidity.String()
+	alert.Fields["shortfall"] = event.Shortfall.String()
+	if event.Underwater() {
+		alert.Severity = Critical
+		alert.Title = fmt.Sprintf("account %s can be liquidated", address)
+	} else {
+		alert.Title = fmt.Sprintf("account %s liquidity changed", address)
+	}
+	alert.Message = event.String()
+	return alert
+}
+
+// FromAaveEvent returns the alert of an AaveWatcher event, accounts low on
+// borrows are at least warnings and failed reads are warnings
+func FromAaveEvent(event client.AaveEvent) Alert {
+	address := strings.ToLower(event.Account.Hex())
+	alert := Alert{
+		Key:    "aave/" + event.Market + "/" + address,
+		Source: "aave " + event.Market,
+		Time:   event.Time,
+		Fields: map[string]string{"address": address, "market": event.Market},
+	}
+	if event.Err != nil {
+		alert.Severity = Warn
+		alert.Title = fmt.Sprintf("failed to read aave account %s", address)
+		alert.Message = event.Err.Error()
+		return alert
+	}
+	alert.Severity = stateSeverity(event.State)
+	if event.LowBorrows && alert.Severity < Warn {
+		alert.Severity = Warn
+	}
+	alert.Title = fmt.Sprintf("aave account %s is %s", address, event.State)
+	alert.Message = event.String()
+	alert.Fields["health"] = event.Data.Health().String()
+	alert.Fields["available_borrows"] = event.Data.AvailableBorrowsValue().String()
+	alert.Fields["state"] = event.State.String()
+	return alert
+}
+
+// Forward sends the alert of every event read from events until the channel
+// is closed or ctx is done. Errors of the sinks are passed to onError, which
+// may be nil
+func Forward[E any](ctx context.Context, router *Router, events <-chan E, convert func(E) Alert, onError func(error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := router.Send(ctx, convert(event)); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
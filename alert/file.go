@@ -0,0 +1,45 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/musinit/go-defi/v2/config"
+)
+
+// FileSink is used to append alerts as json lines to a local file
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSink returns a sink appending to the given file, which is created
+// on the first alert if needed
+func NewFileSink(cfg config.File) (*FileSink, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("file path is empty")
+	}
+	return &FileSink{path: cfg.Path}, nil
+}
+
+// Send appends the alert to the file
+func (s *FileSink) Send(ctx context.Context, alert Alert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
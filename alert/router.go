@@ -0,0 +1,261 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/musinit/go-defi/v2/config"
+)
+
+// Route sends the alerts accepted by its filters to a sink
+type Route struct {
+	Name        string
+	Sink        Sink
+	MinSeverity Severity
+	// RateLimit and Dedup are as described in config.AlertSink
+	RateLimit config.RateLimit
+	Dedup     time.Duration
+}
+
+// RouteStats counts the alerts of a route
+type RouteStats struct {
+	Sent        uint64
+	Failed      uint64
+	Deduped     uint64
+	RateLimited uint64
+}
+
+// route is a Route with its filter state
+type route struct {
+	Route
+	stats RouteStats
+	sent  []time.Time
+	last  map[string]time.Time
+}
+
+// Router is used to send alerts to every route accepting them, applying
+// deduplication and rate limits per route, and escalating warnings that
+// persist to critical
+type Router struct {
+	escalation config.Escalation
+	now        func() time.Time
+
+	mu     sync.Mutex
+	routes []*route
+	warns  map[string]*warning
+	closed bool
+}
+
+// warning tracks an alert key at warn, for escalation
+type warning struct {
+	count     int
+	escalated bool
+	timer     *time.Timer
+}
+
+// NewRouter returns a router sending to the given routes
+func NewRouter(escalation config.Escalation, routes ...Route) *Router {
+	r := &Router{escalation: escalation, now: time.Now, warns: make(map[string]*warning)}
+	for _, rt := range routes {
+		r.routes = append(r.routes, &route{Route: rt, last: make(map[string]time.Time)})
+	}
+	return r
+}
+
+// NewRouterFromConfig returns a router sending to the configured sinks
+func NewRouterFromConfig(cfg config.Alerts) (*Router, error) {
+	routes := make([]Route, 0, len(cfg.Sinks))
+	for i, sinkCfg := range cfg.Sinks {
+		sink, err := NewSink(sinkCfg)
+		if err != nil {
+			return nil, fmt.Errorf("sink %d %s: %w", i, sinkCfg.Name, err)
+		}
+		severity, err := ParseSeverity(sinkCfg.MinSeverity)
+		if err != nil {
+			return nil, fmt.Errorf("sink %d %s: %w", i, sinkCfg.Name, err)
+		}
+		if sinkCfg.RateLimit.Events > 0 && sinkCfg.RateLimit.Interval <= 0 {
+			return nil, fmt.Errorf("sink %d %s: rate limit interval must be positive", i, sinkCfg.Name)
+		}
+		name := sinkCfg.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-%d", sinkCfg.Type, i)
+		}
+		routes = append(routes, Route{
+			Name:        name,
+			Sink:        sink,
+			MinSeverity: severity,
+			RateLimit:   sinkCfg.RateLimit,
+			Dedup:       sinkCfg.Dedup,
+		})
+	}
+	return NewRouter(cfg.Escalation, routes...), nil
+}
+
+// NewSink returns the sink described by cfg
+func NewSink(cfg config.AlertSink) (Sink, error) {
+	switch cfg.Type {
+	case "webhook":
+		return NewWebhookSink(cfg.Webhook)
+	case "smtp":
+		return NewSMTPSink(cfg.SMTP)
+	case "command":
+		return NewCommandSink(cfg.Command)
+	case "file":
+		return NewFileSink(cfg.File)
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+}
+
+// Send escalates the alert if needed and delivers it to every route that
+// accepts it. Errors of the sinks are returned together, a failing sink does
+// not prevent the others from receiving the alert
+func (r *Router) Send(ctx context.Context, alert Alert) error {
+	if alert.Time.IsZero() {
+		alert.Time = r.now()
+	}
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	alert = r.escalate(alert)
+	var targets []*route
+	for _, rt := range r.routes {
+		if rt.accept(alert, r.now()) {
+			targets = append(targets, rt)
+		}
+	}
+	r.mu.Unlock()
+	return r.deliver(ctx, alert, targets)
+}
+
+func (r *Router) deliver(ctx context.Context, alert Alert, targets []*route) error {
+	var errs []string
+	for _, rt := range targets {
+		err := rt.Sink.Send(ctx, alert)
+		r.mu.Lock()
+		if err != nil {
+			rt.stats.Failed++
+			errs = append(errs, fmt.Sprintf("%s: %s", rt.Name, err))
+		} else {
+			rt.stats.Sent++
+		}
+		r.mu.Unlock()
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send alert: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// escalate tracks warnings per key and raises them to critical once they
+// persist past the escalation count, r.mu must be held
+func (r *Router) escalate(alert Alert) Alert {
+	w, ok := r.warns[alert.Key]
+	if alert.Severity != Warn {
+		// anything but a warning resolves it
+		if ok {
+			if w.timer != nil {
+				w.timer.Stop()
+			}
+			delete(r.warns, alert.Key)
+		}
+		return alert
+	}
+	if !ok {
+		w = &warning{}
+		r.warns[alert.Key] = w
+		if r.escalation.After > 0 {
+			escalated := alert
+			w.timer = time.AfterFunc(r.escalation.After, func() { r.escalateAfter(escalated) })
+		}
+	}
+	w.count++
+	if w.escalated || (r.escalation.Count > 0 && w.count >= r.escalation.Count) {
+		w.escalated = true
+		alert.Severity, alert.Escalated = Critical, true
+	}
+	return alert
+}
+
+// escalateAfter sends a critical copy of a warning not resolved in time
+func (r *Router) escalateAfter(alert Alert) {
+	r.mu.Lock()
+	w, ok := r.warns[alert.Key]
+	if !ok || w.escalated || r.closed {
+		r.mu.Unlock()
+		return
+	}
+	w.escalated = true
+	alert.Severity, alert.Escalated, alert.Time = Critical, true, r.now()
+	var targets []*route
+	for _, rt := range r.routes {
+		if rt.accept(alert, r.now()) {
+			targets = append(targets, rt)
+		}
+	}
+	r.mu.Unlock()
+	r.deliver(context.Background(), alert, targets)
+}
+
+// accept applies the filters of the route, r.mu must be held
+func (rt *route) accept(alert Alert, now time.Time) bool {
+	if alert.Severity < rt.MinSeverity {
+		return false
+	}
+	if rt.Dedup > 0 {
+		key := alert.Key + "/" + alert.Severity.String()
+		if last, ok := rt.last[key]; ok && now.Sub(last) < rt.Dedup {
+			rt.stats.Deduped++
+			return false
+		}
+	}
+	if limit := rt.RateLimit; limit.Events > 0 {
+		// drop the sends that left the window
+		kept := rt.sent[:0]
+		for _, t := range rt.sent {
+			if now.Sub(t) < limit.Interval {
+				kept = append(kept, t)
+			}
+		}
+		rt.sent = kept
+		if len(rt.sent) >= limit.Events {
+			rt.stats.RateLimited++
+			return false
+		}
+		rt.sent = append(rt.sent, now)
+	}
+	// only alerts that passed every filter start a dedup window
+	if rt.Dedup > 0 {
+		rt.last[alert.Key+"/"+alert.Severity.String()] = now
+	}
+	return true
+}
+
+// Stats returns the counters of every route, keyed by name
+func (r *Router) Stats() map[string]RouteStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make(map[string]RouteStats, len(r.routes))
+	for _, rt := range r.routes {
+		stats[rt.Name] = rt.stats
+	}
+	return stats
+}
+
+// Close stops pending escalations, alerts sent afterwards are ignored
+func (r *Router) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	for _, w := range r.warns {
+		if w.timer != nil {
+			w.timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/client"
+	"github.com/musinit/go-defi/v2/config"
+	"github.com/musinit/go-defi/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySink records the alerts sent to it
+type memorySink struct {
+	mu     sync.Mutex
+	alerts []Alert
+	err    error
+}
+
+func (m *memorySink) Send(ctx context.Context, alert Alert) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.alerts = append(m.alerts, alert)
+	return nil
+}
+
+func (m *memorySink) sent() []Alert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Alert(nil), m.alerts...)
+}
+
+// clock is a settable time source
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func Test_Severity(t *testing.T) {
+	for _, name := range []string{"info", "warn", "critical"} {
+		severity, err := ParseSeverity(name)
+		require.NoError(t, err)
+		assert.Equal(t, name, severity.String())
+	}
+	_, err := ParseSeverity("panic")
+	assert.Error(t, err)
+}
+
+func Test_Router_Filters(t *testing.T) {
+	all, critical, limited := &memorySink{}, &memorySink{}, &memorySink{}
+	r := NewRouter(config.Escalation{},
+		Route{Name: "all", Sink: all, Dedup: time.Minute},
+		Route{Name: "critical", Sink: critical, MinSeverity: Critical},
+		Route{Name: "limited", Sink: limited, RateLimit: config.RateLimit{Events: 2, Interval: time.Minute}},
+	)
+	clk := &clock{now: time.Unix(1000, 0)}
+	r.now = clk.Now
+	ctx := context.Background()
+
+	require.NoError(t, r.Send(ctx, Alert{Key: "a", Severity: Warn}))
+	require.NoError(t, r.Send(ctx, Alert{Key: "a", Severity: Warn}))
+	require.NoError(t, r.Send(ctx, Alert{Key: "a", Severity: Critical}))
+	assert.Len(t, all.sent(), 2, "the repeated warning is deduplicated")
+	assert.Len(t, critical.sent(), 1)
+	assert.Len(t, limited.sent(), 2, "the third alert is over the limit")
+
+	clk.advance(time.Minute)
+	require.NoError(t, r.Send(ctx, Alert{Key: "a", Severity: Warn}))
+	assert.Len(t, all.sent(), 3)
+	assert.Len(t, limited.sent(), 3)
+	assert.Equal(t, clk.Now(), limited.sent()[2].Time)
+
+	stats := r.Stats()
+	assert.Equal(t, uint64(1), stats["all"].Deduped)
+	assert.Equal(t, uint64(1), stats["limited"].RateLimited)
+	assert.Equal(t, uint64(3), stats["limited"].Sent)
+
+	critical.err = errors.New("down")
+	err := r.Send(ctx, Alert{Key: "b", Severity: Critical})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "critical: down")
+	assert.Len(t, all.sent(), 4, "a failing sink does not block the others")
+	assert.Equal(t, uint64(1), r.Stats()["critical"].Failed)
+}
+
+func Test_Router_DedupAndRateLimit(t *testing.T) {
+	sink := &memorySink{}
+	r := NewRouter(config.Escalation{},
+		Route{Name: "both", Sink: sink, Dedup: time.Hour, RateLimit: config.RateLimit{Events: 1, Interval: time.Minute}},
+	)
+	clk := &clock{now: time.Unix(1000, 0)}
+	r.now = clk.Now
+	ctx := context.Background()
+
+	require.NoError(t, r.Send(ctx, Alert{Key: "a", Severity: Warn}))
+	require.NoError(t, r.Send(ctx, Alert{Key: "b", Severity: Warn}))
+	assert.Len(t, sink.sent(), 1, "b is over the limit")
+
+	// a rate limited alert does not start a dedup window
+	clk.advance(time.Minute)
+	require.NoError(t, r.Send(ctx, Alert{Key: "b", Severity: Warn}))
+	require.Len(t, sink.sent(), 2)
+	assert.Equal(t, "b", sink.sent()[1].Key)
+
+	clk.advance(time.Minute)
+	require.NoError(t, r.Send(ctx, Alert{Key: "a", Severity: Warn}))
+	assert.Len(t, sink.sent(), 2, "a is still deduplicated")
+
+	stats := r.Stats()
+	assert.Equal(t, uint64(1), stats["both"].RateLimited)
+	assert.Equal(t, uint64(1), stats["both"].Deduped)
+}
+
+func Test_Router_Escalation(t *testing.T) {
+	sink := &memorySink{}
+	r := NewRouter(config.Escalation{Count: 3}, Route{Name: "sink", Sink: sink})
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, r.Send(ctx, Alert{Key: "a", Severity: Warn}))
+	}
+	alerts := sink.sent()
+	assert.Equal(t, Warn, alerts[1].Severity)
+	assert.Equal(t, Critical, alerts[2].Severity)
+	assert.True(t, alerts[2].Escalated)
+
+	// a recovery resolves the warning
+	require.NoError(t, r.Send(ctx, Alert{Key: "a", Severity: Info}))
+	require.NoError(t, r.Send(ctx, Alert{Key: "a", Severity: Warn}))
+	assert.Equal(t, Warn, sink.sent()[4].Severity)
+
+	timed := &memorySink{}
+	r = NewRouter(config.Escalation{After: time.Millisecond * 20}, Route{Name: "sink", Sink: timed, MinSeverity: Critical})
+	defer r.Close()
+	require.NoError(t, r.Send(ctx, Alert{Key: "b", Severity: Warn, Title: "nearing liquidation"}))
+	require.NoError(t, r.Send(ctx, Alert{Key: "c", Severity: Warn}))
+	require.NoError(t, r.Send(ctx, Alert{Key: "c", Severity: Info}))
+	require.Eventually(t, func() bool { return len(timed.sent()) > 0 }, time.Second*5, time.Millisecond)
+	time.Sleep(time.Millisecond * 50)
+	alerts = timed.sent()
+	require.Len(t, alerts, 1, "only the unresolved warning is escalated")
+	assert.Equal(t, "b", alerts[0].Key)
+	assert.Equal(t, "nearing liquidation", alerts[0].Title)
+	assert.True(t, alerts[0].Escalated)
+}
+
+func Test_FromEvents(t *testing.T) {
+	alert := FromHealthEvent(client.HealthEvent{
+		Address:        "0xABC",
+		Health:         models.MustParseDecimal("0.9"),
+		PreviousHealth: models.MustParseDecimal("1.1"),
+		State:          client.HealthRisk,
+		PreviousState:  client.HealthWarn,
+		Source:         "chain",
+	})
+	assert.Equal(t, Critical, alert.Severity)
+	assert.Equal(t, "0xabc", alert.Key)
+	assert.Equal(t, "0.9", alert.Fields["health"])
+	assert.Equal(t, Warn, FromHealthEvent(client.HealthEvent{Err: errors.New("down")}).Severity)
+
+	account := common.HexToAddress("0x1")
+	liquidity := FromLiquidityEvent(client.LiquidityEvent{Block: 5, Account: account, Liquidity: models.NewDecimal(0).Int(), Shortfall: models.NewDecimal(7).Int()})
+	assert.Equal(t, Critical, liquidity.Severity)
+	assert.Equal(t, "7", liquidity.Fields["shortfall"])
+
+	aave := FromAaveEvent(client.AaveEvent{
+		Market:     "polygon",
+		Account:    account,
+		State:      client.HealthSafe,
+		LowBorrows: true,
+		Data: &client.AaveAccountData{
+			TotalDebt:        models.NewDecimal(1).Int(),
+			HealthFactor:     models.MustParseDecimal("1.5").Shift(18).Int(),
+			AvailableBorrows: models.NewDecimal(5e8).Int(),
+			BaseDecimals:     8,
+		},
+	})
+	assert.Equal(t, Warn, aave.Severity)
+	assert.Equal(t, "1.5", aave.Fields["health"])
+	assert.Equal(t, "5", aave.Fields["available_borrows"])
+
+	events := make(chan client.HealthEvent, 1)
+	events <- client.HealthEvent{Address: "0xabc", State: client.HealthWarn}
+	close(events)
+	sink := &memorySink{}
+	Forward(context.Background(), NewRouter(config.Escalation{}, Route{Sink: sink}), events, FromHealthEvent, nil)
+	require.Len(t, sink.sent(), 1)
+	assert.Equal(t, Warn, sink.sent()[0].Severity)
+}
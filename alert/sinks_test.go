@@ -0,0 +1,256 @@
+package alert
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/musinit/go-defi/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+var testAlert = Alert{
+	Key:      "0xabc",
+	Severity: Critical,
+	Title:    "account 0xabc is risk",
+	Message:  "health went from \"1.1\" to 0.9",
+	Fields:   map[string]string{"health": "0.9"},
+	Time:     time.Unix(1000, 0).UTC(),
+}
+
+func Test_WebhookSink(t *testing.T) {
+	bodies := make(chan []byte, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("x-token"))
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies <- body
+		if strings.Contains(string(body), "fail") {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	sink, err := NewWebhookSink(config.Webhook{URL: srv.URL, Headers: map[string]string{"x-token": "secret"}})
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(ctx, testAlert))
+	var decoded Alert
+	require.NoError(t, json.Unmarshal(<-bodies, &decoded))
+	assert.Equal(t, testAlert, decoded)
+
+	sink, err = NewWebhookSink(config.Webhook{
+		URL:      srv.URL,
+		Headers:  map[string]string{"x-token": "secret"},
+		Template: `{"text": {{json (printf "[%s] %s" .Severity .Message)}}}`,
+	})
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(ctx, testAlert))
+	var payload map[string]string
+	require.NoError(t, json.Unmarshal(<-bodies, &payload))
+	assert.Equal(t, `[critical] health went from "1.1" to 0.9`, payload["text"])
+
+	failing := testAlert
+	failing.Message = "fail"
+	err = sink.Send(ctx, failing)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+
+	_, err = NewWebhookSink(config.Webhook{})
+	assert.Error(t, err)
+	_, err = NewWebhookSink(config.Webhook{URL: srv.URL, Template: "{{"})
+	assert.Error(t, err)
+}
+
+// smtpServer is a minimal smtp server accepting every message, and sending
+// the data of each on messages
+func smtpServer(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	messages := make(chan string, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+				reply("220 localhost")
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+					case "DATA":
+						reply("354 send data")
+						var data strings.Builder
+						for {
+							line, err := reader.ReadString('\n')
+							if err != nil || line == ".\r\n" {
+								break
+							}
+							data.WriteString(line)
+						}
+						messages <- data.String()
+						reply("250 ok")
+					case "QUIT":
+						reply("221 bye")
+						return
+					default:
+						reply("250 ok")
+					}
+				}
+			}(conn)
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func Test_SMTPSink(t *testing.T) {
+	addr, messages := smtpServer(t)
+	sink, err := NewSMTPSink(config.SMTP{Addr: addr, From: "alerts@example.com", To: []string{"oncall@example.com"}})
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(context.Background(), testAlert))
+	msg := <-messages
+	assert.Contains(t, msg, "Subject: [critical] account 0xabc is risk\r\n")
+	assert.Contains(t, msg, "To: oncall@example.com\r\n")
+	assert.Contains(t, msg, "health: 0.9\r\n")
+
+	// line breaks in the title can not add headers
+	injected := testAlert
+	injected.Title = "account 0xabc\r\nBcc: attacker@example.com"
+	require.NoError(t, sink.Send(context.Background(), injected))
+	msg = <-messages
+	assert.Contains(t, msg, "Subject: [critical] account 0xabc Bcc: attacker@example.com\r\n")
+	assert.NotContains(t, msg, "\r\nBcc:")
+
+	_, err = NewSMTPSink(config.SMTP{Addr: addr})
+	assert.Error(t, err)
+}
+
+func Test_SMTPSink_Timeout(t *testing.T) {
+	// a server accepting connections without ever greeting
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+	cfg := config.SMTP{Addr: listener.Addr().String(), From: "alerts@example.com", To: []string{"oncall@example.com"}, Timeout: time.Millisecond * 100}
+	sink, err := NewSMTPSink(cfg)
+	require.NoError(t, err)
+	start := time.Now()
+	err = sink.Send(context.Background(), testAlert)
+	var timeout net.Error
+	require.True(t, errors.As(err, &timeout), err)
+	assert.True(t, timeout.Timeout())
+	assert.Less(t, time.Since(start), time.Second*5)
+
+	cfg.Timeout = 0
+	sink, err = NewSMTPSink(cfg)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*100, cancel)
+	start = time.Now()
+	err = sink.Send(ctx, testAlert)
+	assert.Equal(t, context.Canceled, err)
+	assert.Less(t, time.Since(start), time.Second*5)
+}
+
+func Test_CommandAndFileSinks(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	out := filepath.Join(dir, "command.out")
+	command, err := NewCommandSink(config.Command{Path: "sh", Args: []string{"-c", `echo "$ALERT_SEVERITY $ALERT_KEY" > ` + out + ` && cat >> ` + out}})
+	require.NoError(t, err)
+	require.NoError(t, command.Send(ctx, testAlert))
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	lines := strings.SplitN(string(data), "\n", 2)
+	assert.Equal(t, "critical 0xabc", lines[0])
+	var decoded Alert
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &decoded))
+	assert.Equal(t, testAlert.Title, decoded.Title)
+
+	failing, err := NewCommandSink(config.Command{Path: "sh", Args: []string{"-c", "echo broken; exit 3"}})
+	require.NoError(t, err)
+	err = failing.Send(ctx, testAlert)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken")
+
+	path := filepath.Join(dir, "alerts.jsonl")
+	file, err := NewFileSink(config.File{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, file.Send(ctx, testAlert))
+	require.NoError(t, file.Send(ctx, testAlert))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	lines = strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &decoded))
+	assert.Equal(t, testAlert, decoded)
+}
+
+func Test_NewRouterFromConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.jsonl")
+	var cfg config.Config
+	require.NoError(t, yaml.Unmarshal([]byte(`
+alerts:
+  escalation:
+    after: 10m
+    count: 3
+  sinks:
+    - name: log
+      type: file
+      dedup: 1m
+      rate_limit:
+        events: 10
+        interval: 1h
+      file:
+        path: `+path+`
+    - type: webhook
+      min_severity: critical
+      webhook:
+        url: http://127.0.0.1:1
+`), &cfg))
+	assert.Equal(t, time.Minute*10, cfg.Alerts.Escalation.After)
+	r, err := NewRouterFromConfig(cfg.Alerts)
+	require.NoError(t, err)
+	defer r.Close()
+	require.NoError(t, r.Send(context.Background(), Alert{Key: "a", Severity: Warn}))
+	stats := r.Stats()
+	assert.Equal(t, uint64(1), stats["log"].Sent)
+	assert.Contains(t, stats, "webhook-1")
+
+	cfg.Alerts.Sinks[0].RateLimit.Interval = 0
+	_, err = NewRouterFromConfig(cfg.Alerts)
+	assert.Error(t, err, "a rate limit without an interval would never limit")
+
+	cfg.Alerts.Sinks[0].RateLimit.Interval = time.Hour
+	cfg.Alerts.Sinks[0].Type = "pager"
+	_, err = NewRouterFromConfig(cfg.Alerts)
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package alert
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/musinit/go-defi/v2/config"
+)
+
+// DefaultSMTPTimeout is how long an smtp sink waits for a whole session
+const DefaultSMTPTimeout = time.Second * 30
+
+// SMTPSink is used to email alerts
+type SMTPSink struct {
+	cfg  config.SMTP
+	host string
+	auth smtp.Auth
+}
+
+// NewSMTPSink returns a sink emailing alerts through the given server,
+// authenticating with PLAIN auth when a username is set
+func NewSMTPSink(cfg config.SMTP) (*SMTPSink, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("smtp addr is empty")
+	}
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("smtp from and to are required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultSMTPTimeout
+	}
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	sink := &SMTPSink{cfg: cfg, host: host}
+	if cfg.Username != "" {
+		sink.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, host)
+	}
+	return sink, nil
+}
+
+// Send emails the alert like smtp.SendMail, using STARTTLS when the server
+// supports it. The session is bounded by the timeout of the sink and by ctx,
+// so a stuck server does not hold up the sinks after it
+func (s *SMTPSink) Send(ctx context.Context, alert Alert) error {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// closing the connection unblocks the session when ctx is cancelled
+	// before the deadline
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	if err := s.send(conn, alert); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *SMTPSink) send(conn net.Conn, alert Alert) error {
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support AUTH")
+		}
+		if err := c.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range s.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.message(alert)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// headerValue strips line breaks from a header value, so that caller
+// supplied text can not add headers or end the header section
+var headerValue = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// message formats the alert as a plain text email
+func (s *SMTPSink) message(alert Alert) []byte {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [%s] %s\r\n", alert.Severity, headerValue.Replace(alert.Title))
+	fmt.Fprintf(&msg, "Date: %s\r\n", alert.Time.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(alert.Message + "\r\n")
+	if len(alert.Fields) > 0 {
+		msg.WriteString("\r\n")
+		keys := make([]string, 0, len(alert.Fields))
+		for key := range alert.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&msg, "%s: %s\r\n", key, alert.Fields[key])
+		}
+	}
+	return []byte(msg.String())
+}
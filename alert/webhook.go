@@ -0,0 +1,80 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"text/template"
+
+	"github.com/musinit/go-defi/v2/config"
+)
+
+// WebhookSink is used to send alerts to an http endpoint as json
+type WebhookSink struct {
+	cfg      config.Webhook
+	template *template.Template
+	client   *http.Client
+}
+
+// templateFuncs are available in webhook templates, json encodes a value so
+// that strings can be embedded safely, ex. {"text": {{json .Message}}}
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// NewWebhookSink returns a sink for the given webhook
+func NewWebhookSink(cfg config.Webhook) (*WebhookSink, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("webhook url is empty")
+	}
+	if cfg.Method == "" {
+		cfg.Method = http.MethodPost
+	}
+	sink := &WebhookSink{cfg: cfg, client: http.DefaultClient}
+	if cfg.Template != "" {
+		tmpl, err := template.New("webhook").Funcs(templateFuncs).Parse(cfg.Template)
+		if err != nil {
+			return nil, err
+		}
+		sink.template = tmpl
+	}
+	return sink, nil
+}
+
+// Send posts the alert, rendered through the template if one is set
+func (s *WebhookSink) Send(ctx context.Context, alert Alert) error {
+	var body bytes.Buffer
+	if s.template != nil {
+		if err := s.template.Execute(&body, alert); err != nil {
+			return err
+		}
+	} else if err := json.NewEncoder(&body).Encode(alert); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, s.cfg.Method, s.cfg.URL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	for key, value := range s.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
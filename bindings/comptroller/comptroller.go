@@ -4,6 +4,7 @@
 package bindings
 
 import (
+	"errors"
 	"math/big"
 	"strings"
 
@@ -17,18 +18,24 @@ import (
 
 // Reference imports to suppress errors if they are not otherwise used.
 var (
+	_ = errors.New
 	_ = big.NewInt
 	_ = strings.NewReader
 	_ = ethereum.NotFound
-	_ = abi.MaxUint256
 	_ = bind.Bind
 	_ = common.Big1
 	_ = types.BloomLookup
 	_ = event.NewSubscription
 )
 
+// BindingsMetaData contains all meta data concerning the Bindings contract.
+var BindingsMetaData = &bind.MetaData{
+	ABI: "[{\"constant\":true,\"inputs\":[],\"name\":\"getAllMarkets\",\"outputs\":[{\"name\":\"\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"isComptroller\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"payer\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"repayAmount\",\"type\":\"uint256\"},{\"name\":\"borrowerIndex\",\"type\":\"uint256\"}],\"name\":\"repayBorrowVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"payer\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"repayAmount\",\"type\":\"uint256\"}],\"name\":\"repayBorrowAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"pendingAdmin\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"newCloseFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"_setCloseFactor\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"unitroller\",\"type\":\"address\"},{\"name\":\"_oracle\",\"type\":\"address\"},{\"name\":\"_closeFactorMantissa\",\"type\":\"uint256\"},{\"name\":\"_maxAssets\",\"type\":\"uint256\"},{\"name\":\"reinitializing\",\"type\":\"bool\"}],\"name\":\"_become\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"minter\",\"type\":\"address\"},{\"name\":\"mintAmount\",\"type\":\"uint256\"},{\"name\":\"mintTokens\",\"type\":\"uint256\"}],\"name\":\"mintVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"name\":\"liquidator\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"repayAmount\",\"type\":\"uint256\"},{\"name\":\"seizeTokens\",\"type\":\"uint256\"}],\"name\":\"liquidateBorrowVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"liquidationIncentiveMantissa\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"minter\",\"type\":\"address\"},{\"name\":\"mintAmount\",\"type\":\"uint256\"}],\"name\":\"mintAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"newLiquidationIncentiveMantissa\",\"type\":\"uint256\"}],\"name\":\"_setLiquidationIncentive\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"redeemer\",\"type\":\"address\"},{\"name\":\"redeemAmount\",\"type\":\"uint256\"},{\"name\":\"redeemTokens\",\"type\":\"uint256\"}],\"name\":\"redeemVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"newOracle\",\"type\":\"address\"}],\"name\":\"_setPriceOracle\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"borrowAmount\",\"type\":\"uint256\"}],\"name\":\"borrowVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"}],\"name\":\"getAccountLiquidity\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"name\":\"liquidator\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"repayAmount\",\"type\":\"uint256\"}],\"name\":\"liquidateBorrowAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"src\",\"type\":\"address\"},{\"name\":\"dst\",\"type\":\"address\"},{\"name\":\"transferTokens\",\"type\":\"uint256\"}],\"name\":\"transferVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"name\":\"liquidator\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"seizeTokens\",\"type\":\"uint256\"}],\"name\":\"seizeVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"oracle\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"markets\",\"outputs\":[{\"name\":\"isListed\",\"type\":\"bool\"},{\"name\":\"collateralFactorMantissa\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"},{\"name\":\"cToken\",\"type\":\"address\"}],\"name\":\"checkMembership\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"maxAssets\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"}],\"name\":\"_supportMarket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"}],\"name\":\"getAssetsIn\",\"outputs\":[{\"name\":\"\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"comptrollerImplementation\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"src\",\"type\":\"address\"},{\"name\":\"dst\",\"type\":\"address\"},{\"name\":\"transferTokens\",\"type\":\"uint256\"}],\"name\":\"transferAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokens\",\"type\":\"address[]\"}],\"name\":\"enterMarkets\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256[]\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"name\":\"repayAmount\",\"type\":\"uint256\"}],\"name\":\"liquidateCalculateSeizeTokens\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"name\":\"liquidator\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"seizeTokens\",\"type\":\"uint256\"}],\"name\":\"seizeAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"newMaxAssets\",\"type\":\"uint256\"}],\"name\":\"_setMaxAssets\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"borrowAmount\",\"type\":\"uint256\"}],\"name\":\"borrowAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"},{\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"accountAssets\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"pendingComptrollerImplementation\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"newCollateralFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"_setCollateralFactor\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"closeFactorMantissa\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"redeemer\",\"type\":\"address\"},{\"name\":\"redeemTokens\",\"type\":\"uint256\"}],\"name\":\"redeemAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokenAddress\",\"type\":\"address\"}],\"name\":\"exitMarket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"admin\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"cToken\",\"type\":\"address\"}],\"name\":\"MarketListed\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"cToken\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"account\",\"type\":\"address\"}],\"name\":\"MarketEntered\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"cToken\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"account\",\"type\":\"address\"}],\"name\":\"MarketExited\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"oldCloseFactorMantissa\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"newCloseFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"NewCloseFactor\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"cToken\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"oldCollateralFactorMantissa\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"newCollateralFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"NewCollateralFactor\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"oldLiquidationIncentiveMantissa\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"newLiquidationIncentiveMantissa\",\"type\":\"uint256\"}],\"name\":\"NewLiquidationIncentive\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"oldMaxAssets\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"newMaxAssets\",\"type\":\"uint256\"}],\"name\":\"NewMaxAssets\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"oldPriceOracle\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"newPriceOracle\",\"type\":\"address\"}],\"name\":\"NewPriceOracle\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"error\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"info\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"detail\",\"type\":\"uint256\"}],\"name\":\"Failure\",\"type\":\"event\"}]",
+}
+
 // BindingsABI is the input ABI used to generate the binding from.
-const BindingsABI = "[{\"constant\":true,\"inputs\":[],\"name\":\"isComptroller\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"payer\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"repayAmount\",\"type\":\"uint256\"},{\"name\":\"borrowerIndex\",\"type\":\"uint256\"}],\"name\":\"repayBorrowVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"payer\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"repayAmount\",\"type\":\"uint256\"}],\"name\":\"repayBorrowAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"pendingAdmin\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"newCloseFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"_setCloseFactor\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"unitroller\",\"type\":\"address\"},{\"name\":\"_oracle\",\"type\":\"address\"},{\"name\":\"_closeFactorMantissa\",\"type\":\"uint256\"},{\"name\":\"_maxAssets\",\"type\":\"uint256\"},{\"name\":\"reinitializing\",\"type\":\"bool\"}],\"name\":\"_become\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"minter\",\"type\":\"address\"},{\"name\":\"mintAmount\",\"type\":\"uint256\"},{\"name\":\"mintTokens\",\"type\":\"uint256\"}],\"name\":\"mintVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"name\":\"liquidator\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"repayAmount\",\"type\":\"uint256\"},{\"name\":\"seizeTokens\",\"type\":\"uint256\"}],\"name\":\"liquidateBorrowVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"liquidationIncentiveMantissa\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"minter\",\"type\":\"address\"},{\"name\":\"mintAmount\",\"type\":\"uint256\"}],\"name\":\"mintAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"newLiquidationIncentiveMantissa\",\"type\":\"uint256\"}],\"name\":\"_setLiquidationIncentive\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"redeemer\",\"type\":\"address\"},{\"name\":\"redeemAmount\",\"type\":\"uint256\"},{\"name\":\"redeemTokens\",\"type\":\"uint256\"}],\"name\":\"redeemVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"newOracle\",\"type\":\"address\"}],\"name\":\"_setPriceOracle\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"borrowAmount\",\"type\":\"uint256\"}],\"name\":\"borrowVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"}],\"name\":\"getAccountLiquidity\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"name\":\"liquidator\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"repayAmount\",\"type\":\"uint256\"}],\"name\":\"liquidateBorrowAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"src\",\"type\":\"address\"},{\"name\":\"dst\",\"type\":\"address\"},{\"name\":\"transferTokens\",\"type\":\"uint256\"}],\"name\":\"transferVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"name\":\"liquidator\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"seizeTokens\",\"type\":\"uint256\"}],\"name\":\"seizeVerify\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"oracle\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"markets\",\"outputs\":[{\"name\":\"isListed\",\"type\":\"bool\"},{\"name\":\"collateralFactorMantissa\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"},{\"name\":\"cToken\",\"type\":\"address\"}],\"name\":\"checkMembership\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"maxAssets\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"}],\"name\":\"_supportMarket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"}],\"name\":\"getAssetsIn\",\"outputs\":[{\"name\":\"\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"comptrollerImplementation\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"src\",\"type\":\"address\"},{\"name\":\"dst\",\"type\":\"address\"},{\"name\":\"transferTokens\",\"type\":\"uint256\"}],\"name\":\"transferAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokens\",\"type\":\"address[]\"}],\"name\":\"enterMarkets\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256[]\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"name\":\"repayAmount\",\"type\":\"uint256\"}],\"name\":\"liquidateCalculateSeizeTokens\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"name\":\"liquidator\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"seizeTokens\",\"type\":\"uint256\"}],\"name\":\"seizeAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"newMaxAssets\",\"type\":\"uint256\"}],\"name\":\"_setMaxAssets\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"borrower\",\"type\":\"address\"},{\"name\":\"borrowAmount\",\"type\":\"uint256\"}],\"name\":\"borrowAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"},{\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"accountAssets\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"pendingComptrollerImplementation\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"newCollateralFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"_setCollateralFactor\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"closeFactorMantissa\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cToken\",\"type\":\"address\"},{\"name\":\"redeemer\",\"type\":\"address\"},{\"name\":\"redeemTokens\",\"type\":\"uint256\"}],\"name\":\"redeemAllowed\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokenAddress\",\"type\":\"address\"}],\"name\":\"exitMarket\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"admin\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"cToken\",\"type\":\"address\"}],\"name\":\"MarketListed\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"cToken\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"account\",\"type\":\"address\"}],\"name\":\"MarketEntered\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"cToken\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"account\",\"type\":\"address\"}],\"name\":\"MarketExited\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"oldCloseFactorMantissa\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"newCloseFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"NewCloseFactor\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"cToken\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"oldCollateralFactorMantissa\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"newCollateralFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"NewCollateralFactor\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"oldLiquidationIncentiveMantissa\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"newLiquidationIncentiveMantissa\",\"type\":\"uint256\"}],\"name\":\"NewLiquidationIncentive\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"oldMaxAssets\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"newMaxAssets\",\"type\":\"uint256\"}],\"name\":\"NewMaxAssets\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"oldPriceOracle\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"newPriceOracle\",\"type\":\"address\"}],\"name\":\"NewPriceOracle\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"error\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"info\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"detail\",\"type\":\"uint256\"}],\"name\":\"Failure\",\"type\":\"event\"}]"
+// Deprecated: Use BindingsMetaData.ABI instead.
+var BindingsABI = BindingsMetaData.ABI
 
 // Bindings is an auto generated Go binding around an Ethereum contract.
 type Bindings struct {
@@ -138,8 +145,8 @@ func bindBindings(address common.Address, caller bind.ContractCaller, transactor
 // sets the output to result. The result type might be a single field for simple
 // returns, a slice of interfaces for anonymous returns and a struct for named
 // returns.
-func (_Bindings *BindingsRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
-	return _Bindings.Contract.BindingsCaller.contract.Call(opts, &[]interface{}{result}, method, params...)
+func (_Bindings *BindingsRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Bindings.Contract.BindingsCaller.contract.Call(opts, result, method, params...)
 }
 
 // Transfer initiates a plain transaction to move funds to the contract, calling
@@ -157,8 +164,8 @@ func (_Bindings *BindingsRaw) Transact(opts *bind.TransactOpts, method string, p
 // sets the output to result. The result type might be a single field for simple
 // returns, a slice of interfaces for anonymous returns and a struct for named
 // returns.
-func (_Bindings *BindingsCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
-	return _Bindings.Contract.contract.Call(opts, &[]interface{}{result}, method, params...)
+func (_Bindings *BindingsCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Bindings.Contract.contract.Call(opts, result, method, params...)
 }
 
 // Transfer initiates a plain transaction to move funds to the contract, calling
@@ -174,293 +181,376 @@ func (_Bindings *BindingsTransactorRaw) Transact(opts *bind.TransactOpts, method
 
 // AccountAssets is a free data retrieval call binding the contract method 0xdce15449.
 //
-// Solidity: function accountAssets(address , uint256 ) constant returns(address)
+// Solidity: function accountAssets(address , uint256 ) view returns(address)
 func (_Bindings *BindingsCaller) AccountAssets(opts *bind.CallOpts, arg0 common.Address, arg1 *big.Int) (common.Address, error) {
-	var (
-		ret0 = new(common.Address)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "accountAssets", arg0, arg1)
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "accountAssets", arg0, arg1)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
 }
 
 // AccountAssets is a free data retrieval call binding the contract method 0xdce15449.
 //
-// Solidity: function accountAssets(address , uint256 ) constant returns(address)
+// Solidity: function accountAssets(address , uint256 ) view returns(address)
 func (_Bindings *BindingsSession) AccountAssets(arg0 common.Address, arg1 *big.Int) (common.Address, error) {
 	return _Bindings.Contract.AccountAssets(&_Bindings.CallOpts, arg0, arg1)
 }
 
 // AccountAssets is a free data retrieval call binding the contract method 0xdce15449.
 //
-// Solidity: function accountAssets(address , uint256 ) constant returns(address)
+// Solidity: function accountAssets(address , uint256 ) view returns(address)
 func (_Bindings *BindingsCallerSession) AccountAssets(arg0 common.Address, arg1 *big.Int) (common.Address, error) {
 	return _Bindings.Contract.AccountAssets(&_Bindings.CallOpts, arg0, arg1)
 }
 
 // Admin is a free data retrieval call binding the contract method 0xf851a440.
 //
-// Solidity: function admin() constant returns(address)
+// Solidity: function admin() view returns(address)
 func (_Bindings *BindingsCaller) Admin(opts *bind.CallOpts) (common.Address, error) {
-	var (
-		ret0 = new(common.Address)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "admin")
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "admin")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
 }
 
 // Admin is a free data retrieval call binding the contract method 0xf851a440.
 //
-// Solidity: function admin() constant returns(address)
+// Solidity: function admin() view returns(address)
 func (_Bindings *BindingsSession) Admin() (common.Address, error) {
 	return _Bindings.Contract.Admin(&_Bindings.CallOpts)
 }
 
 // Admin is a free data retrieval call binding the contract method 0xf851a440.
 //
-// Solidity: function admin() constant returns(address)
+// Solidity: function admin() view returns(address)
 func (_Bindings *BindingsCallerSession) Admin() (common.Address, error) {
 	return _Bindings.Contract.Admin(&_Bindings.CallOpts)
 }
 
 // CheckMembership is a free data retrieval call binding the contract method 0x929fe9a1.
 //
-// Solidity: function checkMembership(address account, address cToken) constant returns(bool)
+// Solidity: function checkMembership(address account, address cToken) view returns(bool)
 func (_Bindings *BindingsCaller) CheckMembership(opts *bind.CallOpts, account common.Address, cToken common.Address) (bool, error) {
-	var (
-		ret0 = new(bool)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "checkMembership", account, cToken)
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "checkMembership", account, cToken)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
 }
 
 // CheckMembership is a free data retrieval call binding the contract method 0x929fe9a1.
 //
-// Solidity: function checkMembership(address account, address cToken) constant returns(bool)
+// Solidity: function checkMembership(address account, address cToken) view returns(bool)
 func (_Bindings *BindingsSession) CheckMembership(account common.Address, cToken common.Address) (bool, error) {
 	return _Bindings.Contract.CheckMembership(&_Bindings.CallOpts, account, cToken)
 }
 
 // CheckMembership is a free data retrieval call binding the contract method 0x929fe9a1.
 //
-// Solidity: function checkMembership(address account, address cToken) constant returns(bool)
+// Solidity: function checkMembership(address account, address cToken) view returns(bool)
 func (_Bindings *BindingsCallerSession) CheckMembership(account common.Address, cToken common.Address) (bool, error) {
 	return _Bindings.Contract.CheckMembership(&_Bindings.CallOpts, account, cToken)
 }
 
 // CloseFactorMantissa is a free data retrieval call binding the contract method 0xe8755446.
 //
-// Solidity: function closeFactorMantissa() constant returns(uint256)
+// Solidity: function closeFactorMantissa() view returns(uint256)
 func (_Bindings *BindingsCaller) CloseFactorMantissa(opts *bind.CallOpts) (*big.Int, error) {
-	var (
-		ret0 = new(*big.Int)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "closeFactorMantissa")
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "closeFactorMantissa")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
 }
 
 // CloseFactorMantissa is a free data retrieval call binding the contract method 0xe8755446.
 //
-// Solidity: function closeFactorMantissa() constant returns(uint256)
+// Solidity: function closeFactorMantissa() view returns(uint256)
 func (_Bindings *BindingsSession) CloseFactorMantissa() (*big.Int, error) {
 	return _Bindings.Contract.CloseFactorMantissa(&_Bindings.CallOpts)
 }
 
 // CloseFactorMantissa is a free data retrieval call binding the contract method 0xe8755446.
 //
-// Solidity: function closeFactorMantissa() constant returns(uint256)
+// Solidity: function closeFactorMantissa() view returns(uint256)
 func (_Bindings *BindingsCallerSession) CloseFactorMantissa() (*big.Int, error) {
 	return _Bindings.Contract.CloseFactorMantissa(&_Bindings.CallOpts)
 }
 
 // ComptrollerImplementation is a free data retrieval call binding the contract method 0xbb82aa5e.
 //
-// Solidity: function comptrollerImplementation() constant returns(address)
+// Solidity: function comptrollerImplementation() view returns(address)
 func (_Bindings *BindingsCaller) ComptrollerImplementation(opts *bind.CallOpts) (common.Address, error) {
-	var (
-		ret0 = new(common.Address)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "comptrollerImplementation")
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "comptrollerImplementation")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
 }
 
 // ComptrollerImplementation is a free data retrieval call binding the contract method 0xbb82aa5e.
 //
-// Solidity: function comptrollerImplementation() constant returns(address)
+// Solidity: function comptrollerImplementation() view returns(address)
 func (_Bindings *BindingsSession) ComptrollerImplementation() (common.Address, error) {
 	return _Bindings.Contract.ComptrollerImplementation(&_Bindings.CallOpts)
 }
 
 // ComptrollerImplementation is a free data retrieval call binding the contract method 0xbb82aa5e.
 //
-// Solidity: function comptrollerImplementation() constant returns(address)
+// Solidity: function comptrollerImplementation() view returns(address)
 func (_Bindings *BindingsCallerSession) ComptrollerImplementation() (common.Address, error) {
 	return _Bindings.Contract.ComptrollerImplementation(&_Bindings.CallOpts)
 }
 
 // GetAccountLiquidity is a free data retrieval call binding the contract method 0x5ec88c79.
 //
-// Solidity: function getAccountLiquidity(address account) constant returns(uint256, uint256, uint256)
+// Solidity: function getAccountLiquidity(address account) view returns(uint256, uint256, uint256)
 func (_Bindings *BindingsCaller) GetAccountLiquidity(opts *bind.CallOpts, account common.Address) (*big.Int, *big.Int, *big.Int, error) {
-	var (
-		ret0 = new(*big.Int)
-		ret1 = new(*big.Int)
-		ret2 = new(*big.Int)
-	)
-	out := &[]interface{}{
-		ret0,
-		ret1,
-		ret2,
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "getAccountLiquidity", account)
+
+	if err != nil {
+		return *new(*big.Int), *new(*big.Int), *new(*big.Int), err
 	}
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "getAccountLiquidity", account)
-	return *ret0, *ret1, *ret2, err
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	out1 := *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	out2 := *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+
+	return out0, out1, out2, err
+
 }
 
 // GetAccountLiquidity is a free data retrieval call binding the contract method 0x5ec88c79.
 //
-// Solidity: function getAccountLiquidity(address account) constant returns(uint256, uint256, uint256)
+// Solidity: function getAccountLiquidity(address account) view returns(uint256, uint256, uint256)
 func (_Bindings *BindingsSession) GetAccountLiquidity(account common.Address) (*big.Int, *big.Int, *big.Int, error) {
 	return _Bindings.Contract.GetAccountLiquidity(&_Bindings.CallOpts, account)
 }
 
 // GetAccountLiquidity is a free data retrieval call binding the contract method 0x5ec88c79.
 //
-// Solidity: function getAccountLiquidity(address account) constant returns(uint256, uint256, uint256)
+// Solidity: function getAccountLiquidity(address account) view returns(uint256, uint256, uint256)
 func (_Bindings *BindingsCallerSession) GetAccountLiquidity(account common.Address) (*big.Int, *big.Int, *big.Int, error) {
 	return _Bindings.Contract.GetAccountLiquidity(&_Bindings.CallOpts, account)
 }
 
+// GetAllMarkets is a free data retrieval call binding the contract method 0xb0772d0b.
+//
+// Solidity: function getAllMarkets() view returns(address[])
+func (_Bindings *BindingsCaller) GetAllMarkets(opts *bind.CallOpts) ([]common.Address, error) {
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "getAllMarkets")
+
+	if err != nil {
+		return *new([]common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+
+	return out0, err
+
+}
+
+// GetAllMarkets is a free data retrieval call binding the contract method 0xb0772d0b.
+//
+// Solidity: function getAllMarkets() view returns(address[])
+func (_Bindings *BindingsSession) GetAllMarkets() ([]common.Address, error) {
+	return _Bindings.Contract.GetAllMarkets(&_Bindings.CallOpts)
+}
+
+// GetAllMarkets is a free data retrieval call binding the contract method 0xb0772d0b.
+//
+// Solidity: function getAllMarkets() view returns(address[])
+func (_Bindings *BindingsCallerSession) GetAllMarkets() ([]common.Address, error) {
+	return _Bindings.Contract.GetAllMarkets(&_Bindings.CallOpts)
+}
+
 // GetAssetsIn is a free data retrieval call binding the contract method 0xabfceffc.
 //
-// Solidity: function getAssetsIn(address account) constant returns(address[])
+// Solidity: function getAssetsIn(address account) view returns(address[])
 func (_Bindings *BindingsCaller) GetAssetsIn(opts *bind.CallOpts, account common.Address) ([]common.Address, error) {
-	var (
-		ret0 = new([]common.Address)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "getAssetsIn", account)
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "getAssetsIn", account)
+
+	if err != nil {
+		return *new([]common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+
+	return out0, err
+
 }
 
 // GetAssetsIn is a free data retrieval call binding the contract method 0xabfceffc.
 //
-// Solidity: function getAssetsIn(address account) constant returns(address[])
+// Solidity: function getAssetsIn(address account) view returns(address[])
 func (_Bindings *BindingsSession) GetAssetsIn(account common.Address) ([]common.Address, error) {
 	return _Bindings.Contract.GetAssetsIn(&_Bindings.CallOpts, account)
 }
 
 // GetAssetsIn is a free data retrieval call binding the contract method 0xabfceffc.
 //
-// Solidity: function getAssetsIn(address account) constant returns(address[])
+// Solidity: function getAssetsIn(address account) view returns(address[])
 func (_Bindings *BindingsCallerSession) GetAssetsIn(account common.Address) ([]common.Address, error) {
 	return _Bindings.Contract.GetAssetsIn(&_Bindings.CallOpts, account)
 }
 
 // IsComptroller is a free data retrieval call binding the contract method 0x007e3dd2.
 //
-// Solidity: function isComptroller() constant returns(bool)
+// Solidity: function isComptroller() view returns(bool)
 func (_Bindings *BindingsCaller) IsComptroller(opts *bind.CallOpts) (bool, error) {
-	var (
-		ret0 = new(bool)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "isComptroller")
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "isComptroller")
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
 }
 
 // IsComptroller is a free data retrieval call binding the contract method 0x007e3dd2.
 //
-// Solidity: function isComptroller() constant returns(bool)
+// Solidity: function isComptroller() view returns(bool)
 func (_Bindings *BindingsSession) IsComptroller() (bool, error) {
 	return _Bindings.Contract.IsComptroller(&_Bindings.CallOpts)
 }
 
 // IsComptroller is a free data retrieval call binding the contract method 0x007e3dd2.
 //
-// Solidity: function isComptroller() constant returns(bool)
+// Solidity: function isComptroller() view returns(bool)
 func (_Bindings *BindingsCallerSession) IsComptroller() (bool, error) {
 	return _Bindings.Contract.IsComptroller(&_Bindings.CallOpts)
 }
 
 // LiquidateCalculateSeizeTokens is a free data retrieval call binding the contract method 0xc488847b.
 //
-// Solidity: function liquidateCalculateSeizeTokens(address cTokenBorrowed, address cTokenCollateral, uint256 repayAmount) constant returns(uint256, uint256)
+// Solidity: function liquidateCalculateSeizeTokens(address cTokenBorrowed, address cTokenCollateral, uint256 repayAmount) view returns(uint256, uint256)
 func (_Bindings *BindingsCaller) LiquidateCalculateSeizeTokens(opts *bind.CallOpts, cTokenBorrowed common.Address, cTokenCollateral common.Address, repayAmount *big.Int) (*big.Int, *big.Int, error) {
-	var (
-		ret0 = new(*big.Int)
-		ret1 = new(*big.Int)
-	)
-	out := &[]interface{}{
-		ret0,
-		ret1,
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "liquidateCalculateSeizeTokens", cTokenBorrowed, cTokenCollateral, repayAmount)
+
+	if err != nil {
+		return *new(*big.Int), *new(*big.Int), err
 	}
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "liquidateCalculateSeizeTokens", cTokenBorrowed, cTokenCollateral, repayAmount)
-	return *ret0, *ret1, err
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	out1 := *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return out0, out1, err
+
 }
 
 // LiquidateCalculateSeizeTokens is a free data retrieval call binding the contract method 0xc488847b.
 //
-// Solidity: function liquidateCalculateSeizeTokens(address cTokenBorrowed, address cTokenCollateral, uint256 repayAmount) constant returns(uint256, uint256)
+// Solidity: function liquidateCalculateSeizeTokens(address cTokenBorrowed, address cTokenCollateral, uint256 repayAmount) view returns(uint256, uint256)
 func (_Bindings *BindingsSession) LiquidateCalculateSeizeTokens(cTokenBorrowed common.Address, cTokenCollateral common.Address, repayAmount *big.Int) (*big.Int, *big.Int, error) {
 	return _Bindings.Contract.LiquidateCalculateSeizeTokens(&_Bindings.CallOpts, cTokenBorrowed, cTokenCollateral, repayAmount)
 }
 
 // LiquidateCalculateSeizeTokens is a free data retrieval call binding the contract method 0xc488847b.
 //
-// Solidity: function liquidateCalculateSeizeTokens(address cTokenBorrowed, address cTokenCollateral, uint256 repayAmount) constant returns(uint256, uint256)
+// Solidity: function liquidateCalculateSeizeTokens(address cTokenBorrowed, address cTokenCollateral, uint256 repayAmount) view returns(uint256, uint256)
 func (_Bindings *BindingsCallerSession) LiquidateCalculateSeizeTokens(cTokenBorrowed common.Address, cTokenCollateral common.Address, repayAmount *big.Int) (*big.Int, *big.Int, error) {
 	return _Bindings.Contract.LiquidateCalculateSeizeTokens(&_Bindings.CallOpts, cTokenBorrowed, cTokenCollateral, repayAmount)
 }
 
 // LiquidationIncentiveMantissa is a free data retrieval call binding the contract method 0x4ada90af.
 //
-// Solidity: function liquidationIncentiveMantissa() constant returns(uint256)
+// Solidity: function liquidationIncentiveMantissa() view returns(uint256)
 func (_Bindings *BindingsCaller) LiquidationIncentiveMantissa(opts *bind.CallOpts) (*big.Int, error) {
-	var (
-		ret0 = new(*big.Int)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "liquidationIncentiveMantissa")
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "liquidationIncentiveMantissa")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
 }
 
 // LiquidationIncentiveMantissa is a free data retrieval call binding the contract method 0x4ada90af.
 //
-// Solidity: function liquidationIncentiveMantissa() constant returns(uint256)
+// Solidity: function liquidationIncentiveMantissa() view returns(uint256)
 func (_Bindings *BindingsSession) LiquidationIncentiveMantissa() (*big.Int, error) {
 	return _Bindings.Contract.LiquidationIncentiveMantissa(&_Bindings.CallOpts)
 }
 
 // LiquidationIncentiveMantissa is a free data retrieval call binding the contract method 0x4ada90af.
 //
-// Solidity: function liquidationIncentiveMantissa() constant returns(uint256)
+// Solidity: function liquidationIncentiveMantissa() view returns(uint256)
 func (_Bindings *BindingsCallerSession) LiquidationIncentiveMantissa() (*big.Int, error) {
 	return _Bindings.Contract.LiquidationIncentiveMantissa(&_Bindings.CallOpts)
 }
 
 // Markets is a free data retrieval call binding the contract method 0x8e8f294b.
 //
-// Solidity: function markets(address ) constant returns(bool isListed, uint256 collateralFactorMantissa)
+// Solidity: function markets(address ) view returns(bool isListed, uint256 collateralFactorMantissa)
 func (_Bindings *BindingsCaller) Markets(opts *bind.CallOpts, arg0 common.Address) (struct {
 	IsListed                 bool
 	CollateralFactorMantissa *big.Int
 }, error) {
-	ret := new(struct {
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "markets", arg0)
+
+	outstruct := new(struct {
 		IsListed                 bool
 		CollateralFactorMantissa *big.Int
 	})
-	out := ret
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "markets", arg0)
-	return *ret, err
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.IsListed = *abi.ConvertType(out[0], new(bool)).(*bool)
+	outstruct.CollateralFactorMantissa = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
 }
 
 // Markets is a free data retrieval call binding the contract method 0x8e8f294b.
 //
-// Solidity: function markets(address ) constant returns(bool isListed, uint256 collateralFactorMantissa)
+// Solidity: function markets(address ) view returns(bool isListed, uint256 collateralFactorMantissa)
 func (_Bindings *BindingsSession) Markets(arg0 common.Address) (struct {
 	IsListed                 bool
 	CollateralFactorMantissa *big.Int
@@ -470,7 +560,7 @@ func (_Bindings *BindingsSession) Markets(arg0 common.Address) (struct {
 
 // Markets is a free data retrieval call binding the contract method 0x8e8f294b.
 //
-// Solidity: function markets(address ) constant returns(bool isListed, uint256 collateralFactorMantissa)
+// Solidity: function markets(address ) view returns(bool isListed, uint256 collateralFactorMantissa)
 func (_Bindings *BindingsCallerSession) Markets(arg0 common.Address) (struct {
 	IsListed                 bool
 	CollateralFactorMantissa *big.Int
@@ -480,104 +570,124 @@ func (_Bindings *BindingsCallerSession) Markets(arg0 common.Address) (struct {
 
 // MaxAssets is a free data retrieval call binding the contract method 0x94b2294b.
 //
-// Solidity: function maxAssets() constant returns(uint256)
+// Solidity: function maxAssets() view returns(uint256)
 func (_Bindings *BindingsCaller) MaxAssets(opts *bind.CallOpts) (*big.Int, error) {
-	var (
-		ret0 = new(*big.Int)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "maxAssets")
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "maxAssets")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
 }
 
 // MaxAssets is a free data retrieval call binding the contract method 0x94b2294b.
 //
-// Solidity: function maxAssets() constant returns(uint256)
+// Solidity: function maxAssets() view returns(uint256)
 func (_Bindings *BindingsSession) MaxAssets() (*big.Int, error) {
 	return _Bindings.Contract.MaxAssets(&_Bindings.CallOpts)
 }
 
 // MaxAssets is a free data retrieval call binding the contract method 0x94b2294b.
 //
-// Solidity: function maxAssets() constant returns(uint256)
+// Solidity: function maxAssets() view returns(uint256)
 func (_Bindings *BindingsCallerSession) MaxAssets() (*big.Int, error) {
 	return _Bindings.Contract.MaxAssets(&_Bindings.CallOpts)
 }
 
 // Oracle is a free data retrieval call binding the contract method 0x7dc0d1d0.
 //
-// Solidity: function oracle() constant returns(address)
+// Solidity: function oracle() view returns(address)
 func (_Bindings *BindingsCaller) Oracle(opts *bind.CallOpts) (common.Address, error) {
-	var (
-		ret0 = new(common.Address)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "oracle")
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "oracle")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
 }
 
 // Oracle is a free data retrieval call binding the contract method 0x7dc0d1d0.
 //
-// Solidity: function oracle() constant returns(address)
+// Solidity: function oracle() view returns(address)
 func (_Bindings *BindingsSession) Oracle() (common.Address, error) {
 	return _Bindings.Contract.Oracle(&_Bindings.CallOpts)
 }
 
 // Oracle is a free data retrieval call binding the contract method 0x7dc0d1d0.
 //
-// Solidity: function oracle() constant returns(address)
+// Solidity: function oracle() view returns(address)
 func (_Bindings *BindingsCallerSession) Oracle() (common.Address, error) {
 	return _Bindings.Contract.Oracle(&_Bindings.CallOpts)
 }
 
 // PendingAdmin is a free data retrieval call binding the contract method 0x26782247.
 //
-// Solidity: function pendingAdmin() constant returns(address)
+// Solidity: function pendingAdmin() view returns(address)
 func (_Bindings *BindingsCaller) PendingAdmin(opts *bind.CallOpts) (common.Address, error) {
-	var (
-		ret0 = new(common.Address)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "pendingAdmin")
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "pendingAdmin")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
 }
 
 // PendingAdmin is a free data retrieval call binding the contract method 0x26782247.
 //
-// Solidity: function pendingAdmin() constant returns(address)
+// Solidity: function pendingAdmin() view returns(address)
 func (_Bindings *BindingsSession) PendingAdmin() (common.Address, error) {
 	return _Bindings.Contract.PendingAdmin(&_Bindings.CallOpts)
 }
 
 // PendingAdmin is a free data retrieval call binding the contract method 0x26782247.
 //
-// Solidity: function pendingAdmin() constant returns(address)
+// Solidity: function pendingAdmin() view returns(address)
 func (_Bindings *BindingsCallerSession) PendingAdmin() (common.Address, error) {
 	return _Bindings.Contract.PendingAdmin(&_Bindings.CallOpts)
 }
 
 // PendingComptrollerImplementation is a free data retrieval call binding the contract method 0xdcfbc0c7.
 //
-// Solidity: function pendingComptrollerImplementation() constant returns(address)
+// Solidity: function pendingComptrollerImplementation() view returns(address)
 func (_Bindings *BindingsCaller) PendingComptrollerImplementation(opts *bind.CallOpts) (common.Address, error) {
-	var (
-		ret0 = new(common.Address)
-	)
-	out := ret0
-	err := _Bindings.contract.Call(opts, &[]interface{}{out}, "pendingComptrollerImplementation")
-	return *ret0, err
+	var out []interface{}
+	err := _Bindings.contract.Call(opts, &out, "pendingComptrollerImplementation")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
 }
 
 // PendingComptrollerImplementation is a free data retrieval call binding the contract method 0xdcfbc0c7.
 //
-// Solidity: function pendingComptrollerImplementation() constant returns(address)
+// Solidity: function pendingComptrollerImplementation() view returns(address)
 func (_Bindings *BindingsSession) PendingComptrollerImplementation() (common.Address, error) {
 	return _Bindings.Contract.PendingComptrollerImplementation(&_Bindings.CallOpts)
 }
 
 // PendingComptrollerImplementation is a free data retrieval call binding the contract method 0xdcfbc0c7.
 //
-// Solidity: function pendingComptrollerImplementation() constant returns(address)
+// Solidity: function pendingComptrollerImplementation() view returns(address)
 func (_Bindings *BindingsCallerSession) PendingComptrollerImplementation() (common.Address, error) {
 	return _Bindings.Contract.PendingComptrollerImplementation(&_Bindings.CallOpts)
 }
@@ -1197,6 +1307,7 @@ func (_Bindings *BindingsFilterer) ParseFailure(log types.Log) (*BindingsFailure
 	if err := _Bindings.contract.UnpackLog(event, "Failure", log); err != nil {
 		return nil, err
 	}
+	event.Raw = log
 	return event, nil
 }
 
@@ -1331,6 +1442,7 @@ func (_Bindings *BindingsFilterer) ParseMarketEntered(log types.Log) (*BindingsM
 	if err := _Bindings.contract.UnpackLog(event, "MarketEntered", log); err != nil {
 		return nil, err
 	}
+	event.Raw = log
 	return event, nil
 }
 
@@ -1465,6 +1577,7 @@ func (_Bindings *BindingsFilterer) ParseMarketExited(log types.Log) (*BindingsMa
 	if err := _Bindings.contract.UnpackLog(event, "MarketExited", log); err != nil {
 		return nil, err
 	}
+	event.Raw = log
 	return event, nil
 }
 
@@ -1598,6 +1711,7 @@ func (_Bindings *BindingsFilterer) ParseMarketListed(log types.Log) (*BindingsMa
 	if err := _Bindings.contract.UnpackLog(event, "MarketListed", log); err != nil {
 		return nil, err
 	}
+	event.Raw = log
 	return event, nil
 }
 
@@ -1732,6 +1846,7 @@ func (_Bindings *BindingsFilterer) ParseNewCloseFactor(log types.Log) (*Bindings
 	if err := _Bindings.contract.UnpackLog(event, "NewCloseFactor", log); err != nil {
 		return nil, err
 	}
+	event.Raw = log
 	return event, nil
 }
 
@@ -1867,6 +1982,7 @@ func (_Bindings *BindingsFilterer) ParseNewCollateralFactor(log types.Log) (*Bin
 	if err := _Bindings.contract.UnpackLog(event, "NewCollateralFactor", log); err != nil {
 		return nil, err
 	}
+	event.Raw = log
 	return event, nil
 }
 
@@ -2001,6 +2117,7 @@ func (_Bindings *BindingsFilterer) ParseNewLiquidationIncentive(log types.Log) (
 	if err := _Bindings.contract.UnpackLog(event, "NewLiquidationIncentive", log); err != nil {
 		return nil, err
 	}
+	event.Raw = log
 	return event, nil
 }
 
@@ -2135,6 +2252,7 @@ func (_Bindings *BindingsFilterer) ParseNewMaxAssets(log types.Log) (*BindingsNe
 	if err := _Bindings.contract.UnpackLog(event, "NewMaxAssets", log); err != nil {
 		return nil, err
 	}
+	event.Raw = log
 	return event, nil
 }
 
@@ -2269,5 +2387,6 @@ func (_Bindings *BindingsFilterer) ParseNewPriceOracle(log types.Log) (*Bindings
 	if err := _Bindings.contract.UnpackLog(event, "NewPriceOracle", log); err != nil {
 		return nil, err
 	}
+	event.Raw = log
 	return event, nil
 }
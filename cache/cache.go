@@ -0,0 +1,101 @@
+// Package cache provides the response cache used by the api and blockchain
+// clients, backed by a pluggable Store
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Store is a key value store holding cached responses. Implementations
+// must be safe for concurrent use
+type Store interface {
+	// Get returns the value stored under key, and false if
+	// there is no value or it has expired
+	Get(key string) ([]byte, bool, error)
+	// Set stores value under key. A ttl of 0 or lower never expires
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete removes the value stored under key, if any
+	Delete(key string) error
+}
+
+// Stats are the hit and miss counters of a Cache
+type Stats struct {
+	Hits   uint64
+	Misses uint64
+	// the number of store operations that failed, failed reads also count as misses
+	Errors uint64
+}
+
+// HitRate returns the share of lookups that were hits, between 0 and 1
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// Cache records hit and miss statistics on top of a Store. Store errors are
+// counted but never returned, a failing store behaves like an empty cache
+type Cache struct {
+	store  Store
+	hits   uint64
+	misses uint64
+	errors uint64
+}
+
+// New returns a cache backed by the given store
+func New(store Store) *Cache {
+	return &Cache{store: store}
+}
+
+// Get returns the value cached under key
+func (c *Cache) Get(key string) ([]byte, bool) {
+	value, ok, err := c.store.Get(key)
+	if err != nil {
+		atomic.AddUint64(&c.errors, 1)
+	}
+	if err != nil || !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	atomic.AddUint64(&c.hits, 1)
+	return value, true
+}
+
+// Set caches value under key for the given ttl
+func (c *Cache) Set(key string, value []byte, ttl time.Duration) {
+	if err := c.store.Set(key, value, ttl); err != nil {
+		atomic.AddUint64(&c.errors, 1)
+	}
+}
+
+// Delete removes the value cached under key
+func (c *Cache) Delete(key string) {
+	if err := c.store.Delete(key); err != nil {
+		atomic.AddUint64(&c.errors, 1)
+	}
+}
+
+// Stats returns a snapshot of the cache statistics
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+		Errors: atomic.LoadUint64(&c.errors),
+	}
+}
+
+// expiry returns the time at which an entry set now with ttl expires,
+// or the zero time if it never does
+func expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+func expired(at time.Time) bool {
+	return !at.IsZero() && time.Now().After(at)
+}
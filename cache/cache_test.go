@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, store Store) {
+	_, ok, err := store.Get("missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Set("forever", []byte("a"), 0))
+	require.NoError(t, store.Set("brief", []byte("b"), time.Millisecond*20))
+	value, ok, err := store.Get("forever")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("a"), value)
+	value, ok, err = store.Get("brief")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("b"), value)
+
+	time.Sleep(time.Millisecond * 30)
+	_, ok, err = store.Get("brief")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Set("forever", []byte("c"), 0))
+	value, _, _ = store.Get("forever")
+	assert.Equal(t, []byte("c"), value)
+	require.NoError(t, store.Delete("forever"))
+	require.NoError(t, store.Delete("forever"))
+	_, ok, _ = store.Get("forever")
+	assert.False(t, ok)
+}
+
+func Test_Memory(t *testing.T) {
+	testStore(t, NewMemory())
+}
+
+func Test_Memory_Sweep(t *testing.T) {
+	store := NewMemory()
+	for i := 0; i < minSweep; i++ {
+		require.NoError(t, store.Set(fmt.Sprint(i), nil, time.Nanosecond))
+	}
+	time.Sleep(time.Millisecond)
+	require.NoError(t, store.Set("fresh", nil, 0))
+	assert.Equal(t, 1, store.Len())
+}
+
+func Test_Disk(t *testing.T) {
+	store, err := NewDisk(t.TempDir())
+	require.NoError(t, err)
+	testStore(t, store)
+}
+
+type failingStore struct{}
+
+func (failingStore) Get(string) ([]byte, bool, error)        { return nil, false, errors.New("down") }
+func (failingStore) Set(string, []byte, time.Duration) error { return errors.New("down") }
+func (failingStore) Delete(string) error                     { return errors.New("down") }
+
+func Test_Cache_Stats(t *testing.T) {
+	c := New(NewMemory())
+	_, ok := c.Get("key")
+	assert.False(t, ok)
+	c.Set("key", []byte("value"), 0)
+	value, ok := c.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), value)
+	c.Get("key")
+	assert.Equal(t, Stats{Hits: 2, Misses: 1}, c.Stats())
+	assert.InDelta(t, 2.0/3.0, c.Stats().HitRate(), 0.0001)
+
+	failing := New(failingStore{})
+	failing.Set("key", nil, 0)
+	_, ok = failing.Get("key")
+	assert.False(t, ok)
+	assert.Equal(t, Stats{Misses: 1, Errors: 2}, failing.Stats())
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Disk is a Store keeping one file per entry in a directory, so cached
+// responses survive restarts and can be shared between processes
+type Disk struct {
+	dir string
+}
+
+// NewDisk returns a store keeping its entries in dir, creating it if needed
+func NewDisk(dir string) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &Disk{dir: dir}, nil
+}
+
+// path returns the file holding the entry for key
+func (d *Disk) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements Store
+func (d *Disk) Get(key string) ([]byte, bool, error) {
+	data, err := ioutil.ReadFile(d.path(key))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(data) < 8 {
+		return nil, false, errors.New("corrupt cache entry")
+	}
+	var expires time.Time
+	if nanos := int64(binary.BigEndian.Uint64(data[:8])); nanos != 0 {
+		expires = time.Unix(0, nanos)
+	}
+	if expired(expires) {
+		return nil, false, d.Delete(key)
+	}
+	return data[8:], true, nil
+}
+
+// Set implements Store. Entries are written to a temporary file and renamed
+// into place, so concurrent readers never see a partial entry
+func (d *Disk) Set(key string, value []byte, ttl time.Duration) error {
+	var nanos int64
+	if expires := expiry(ttl); !expires.IsZero() {
+		nanos = expires.UnixNano()
+	}
+	data := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(data[:8], uint64(nanos))
+	copy(data[8:], value)
+	tmp, err := ioutil.TempFile(d.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(key))
+}
+
+// Delete implements Store
+func (d *Disk) Delete(key string) error {
+	if err := os.Remove(d.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
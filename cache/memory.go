@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// minSweep is the number of entries a Memory store holds before it
+// starts sweeping expired entries
+const minSweep = 64
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// Memory is an in-memory Store. Expired entries are removed when read, and
+// swept whenever the store has doubled in size since the last sweep
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	nextSweep int
+}
+
+// NewMemory returns an empty in-memory store
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]memoryEntry), nextSweep: minSweep}
+}
+
+// Get implements Store
+func (m *Memory) Get(key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if expired(entry.expires) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set implements Store
+func (m *Memory) Set(key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.entries) >= m.nextSweep {
+		m.sweep()
+	}
+	m.entries[key] = memoryEntry{value: append([]byte(nil), value...), expires: expiry(ttl)}
+	return nil
+}
+
+// Delete implements Store
+func (m *Memory) Delete(key string) error {
+	m.mu.Lock()
+	delete(m.entries, key)
+	m.mu.Unlock()
+	return nil
+}
+
+// Len returns the number of entries held, including expired entries not yet swept
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// sweep removes every expired entry, the caller must hold the lock
+func (m *Memory) sweep() {
+	for key, entry := range m.entries {
+		if expired(entry.expires) {
+			delete(m.entries, key)
+		}
+	}
+	m.nextSweep = 2 * len(m.entries)
+	if m.nextSweep < minSweep {
+		m.nextSweep = minSweep
+	}
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	poolv2 "github.com/musinit/go-defi/v2/bindings/aave_lending_pool_v2"
+	poolv3 "github.com/musinit/go-defi/v2/bindings/aave_lending_pool_v3"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// AaveMarket is an aave lending pool deployment
+type AaveMarket struct {
+	Name    string
+	ChainID int64
+	Pool    Address
+	// Version is the aave protocol version of the pool, 2 or 3
+	Version int
+}
+
+// BaseDecimals returns the decimals of the collateral, debt and available
+// borrows amounts: v2 pools report them in wei, v3 pools in usd with 8 decimals
+func (m AaveMarket) BaseDecimals() int {
+	if m.Version == 2 {
+		return 18
+	}
+	return 8
+}
+
+var (
+	// AavePolygonV3 is the aave v3 pool on polygon
+	AavePolygonV3 = AaveMarket{Name: "polygon", ChainID: 137, Pool: AaveLendingPoolV3, Version: 3}
+	// AavePolygonV2 is the aave v2 pool on polygon
+	AavePolygonV2 = AaveMarket{Name: "polygon-v2", ChainID: 137, Pool: AaveLendingPoolV2, Version: 2}
+	// AaveMumbaiV3 is the aave v3 pool on the polygon mumbai testnet
+	AaveMumbaiV3 = AaveMarket{Name: "mumbai", ChainID: 80001, Pool: AaveLendingPoolV3Mumbai, Version: 3}
+
+	// AaveMarkets is a map containing the name, and deployment of all aave markets
+	AaveMarkets = map[string]AaveMarket{
+		AavePolygonV3.Name: AavePolygonV3,
+		AavePolygonV2.Name: AavePolygonV2,
+		AaveMumbaiV3.Name:  AaveMumbaiV3,
+	}
+)
+
+// AaveAccountData is the full result of a pool's getUserAccountData, as
+// the raw integers returned on chain
+type AaveAccountData struct {
+	// TotalCollateral, TotalDebt and AvailableBorrows are in the base
+	// currency of the pool, scaled by BaseDecimals
+	TotalCollateral  *big.Int
+	TotalDebt        *big.Int
+	AvailableBorrows *big.Int
+	// LiquidationThreshold and LTV are in basis points
+	LiquidationThreshold *big.Int
+	LTV                  *big.Int
+	// HealthFactor is scaled by 1e18, and is the max uint256 without debt
+	HealthFactor *big.Int
+	BaseDecimals int
+}
+
+// HasDebt returns whether the account borrows anything
+func (d *AaveAccountData) HasDebt() bool {
+	return d.TotalDebt.Sign() > 0
+}
+
+// Health returns the health factor, an account with a health below 1 can be
+// liquidated. Accounts without debt have a health of 0
+func (d *AaveAccountData) Health() models.Decimal {
+	if !d.HasDebt() {
+		return models.Decimal{}
+	}
+	return models.NewDecimalFromInt(d.HealthFactor, 18)
+}
+
+// CollateralValue returns the collateral in the base currency of the pool
+func (d *AaveAccountData) CollateralValue() models.Decimal {
+	return models.NewDecimalFromInt(d.TotalCollateral, d.BaseDecimals)
+}
+
+// DebtValue returns the debt in the base currency of the pool
+func (d *AaveAccountData) DebtValue() models.Decimal {
+	return models.NewDecimalFromInt(d.TotalDebt, d.BaseDecimals)
+}
+
+// AvailableBorrowsValue returns how much more the account can borrow, in the
+// base currency of the pool
+func (d *AaveAccountData) AvailableBorrowsValue() models.Decimal {
+	return models.NewDecimalFromInt(d.AvailableBorrows, d.BaseDecimals)
+}
+
+// AaveReader is used to read aave account data
+type AaveReader interface {
+	// Market returns the deployment the reader reads from
+	Market() AaveMarket
+	// UserAccountData returns the account data of user
+	UserAccountData(ctx context.Context, user common.Address) (*AaveAccountData, error)
+}
+
+// AavePool is an AaveReader over the lending pool bindings of either version
+type AavePool struct {
+	market AaveMarket
+	v2     *poolv2.Bindings
+	v3     *poolv3.Bindings
+}
+
+// NewAavePool returns a reader of the pool of market, through backend
+func NewAavePool(backend bind.ContractBackend, market AaveMarket) (*AavePool, error) {
+	pool := &AavePool{market: market}
+	var err error
+	switch market.Version {
+	case 2:
+		pool.v2, err = poolv2.NewBindings(market.Pool.EthAddress(), backend)
+	case 3:
+		pool.v3, err = poolv3.NewBindings(market.Pool.EthAddress(), backend)
+	default:
+		return nil, fmt.Errorf("unsupported aave version %d", market.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// Market returns the deployment the pool reads from
+func (p *AavePool) Market() AaveMarket {
+	return p.market
+}
+
+// UserAccountData returns the account data of user
+func (p *AavePool) UserAccountData(ctx context.Context, user common.Address) (*AaveAccountData, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	data := &AaveAccountData{BaseDecimals: p.market.BaseDecimals()}
+	if p.v2 != nil {
+		out, err := p.v2.GetUserAccountData(opts, user)
+		if err != nil {
+			return nil, err
+		}
+		data.TotalCollateral, data.TotalDebt, data.AvailableBorrows = out.TotalCollateralETH, out.TotalDebtETH, out.AvailableBorrowsETH
+		data.LiquidationThreshold, data.LTV, data.HealthFactor = out.CurrentLiquidationThreshold, out.Ltv, out.HealthFactor
+		return data, nil
+	}
+	out, err := p.v3.GetUserAccountData(opts, user)
+	if err != nil {
+		return nil, err
+	}
+	data.TotalCollateral, data.TotalDebt, data.AvailableBorrows = out.TotalCollateralBase, out.TotalDebtBase, out.AvailableBorrowsBase
+	data.LiquidationThreshold, data.LTV, data.HealthFactor = out.CurrentLiquidationThreshold, out.Ltv, out.HealthFactor
+	return data, nil
+}
+
+// AavePool returns a reader of the pool of market through the client's connection
+func (bc *BClient) AavePool(market AaveMarket) (*AavePool, error) {
+	return NewAavePool(bc.client, market)
+}
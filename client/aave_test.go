@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	poolv2 "github.com/musinit/go-defi/v2/bindings/aave_lending_pool_v2"
+	poolv3 "github.com/musinit/go-defi/v2/bindings/aave_lending_pool_v3"
+	"github.com/musinit/go-defi/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// poolNode is the eth namespace of a node where every pool returns the same
+// account data
+type poolNode struct {
+	abi  abi.ABI
+	data []interface{}
+}
+
+func (n *poolNode) Call(args callArgs, block string) (hexutil.Bytes, error) {
+	return n.abi.Methods["getUserAccountData"].Outputs.Pack(n.data...)
+}
+
+func Test_AavePool(t *testing.T) {
+	for _, tt := range []struct {
+		market AaveMarket
+		abi    string
+	}{
+		{AavePolygonV3, poolv3.BindingsABI},
+		{AavePolygonV2, poolv2.BindingsABI},
+	} {
+		parsed, err := abi.JSON(strings.NewReader(tt.abi))
+		require.NoError(t, err)
+		// 2 of health, a value that overflowed the previous int64 result
+		healthFactor := new(big.Int).Mul(big.NewInt(2), math.BigPow(10, 18))
+		base := math.BigPow(10, int64(tt.market.BaseDecimals()))
+		node := &poolNode{abi: parsed, data: []interface{}{
+			new(big.Int).Mul(big.NewInt(300), base),
+			new(big.Int).Mul(big.NewInt(100), base),
+			new(big.Int).Mul(big.NewInt(125), base),
+			big.NewInt(8250),
+			big.NewInt(7500),
+			healthFactor,
+		}}
+		srv := rpc.NewServer()
+		require.NoError(t, srv.RegisterName("eth", node))
+		api := httptest.NewServer(srv)
+		rpcClient, err := rpc.Dial(api.URL)
+		require.NoError(t, err)
+
+		pool, err := NewAavePool(ethclient.NewClient(rpcClient), tt.market)
+		require.NoError(t, err)
+		data, err := pool.UserAccountData(context.Background(), common.HexToAddress(account))
+		require.NoError(t, err, tt.market.Name)
+		assert.Equal(t, "2", data.Health().String())
+		assert.Equal(t, "300", data.CollateralValue().String())
+		assert.Equal(t, "100", data.DebtValue().String())
+		assert.Equal(t, "125", data.AvailableBorrowsValue().String())
+		assert.Equal(t, "8250", data.LiquidationThreshold.String())
+		assert.Equal(t, "7500", data.LTV.String())
+		rpcClient.Close()
+		api.Close()
+	}
+	_, err := NewAavePool(nil, AaveMarket{Version: 1})
+	assert.Error(t, err)
+}
+
+// aaveSource is an AaveReader with account data set by the test, every
+// account has 100 of debt
+type aaveSource struct {
+	mu     sync.Mutex
+	health map[common.Address]int64
+	err    error
+}
+
+func (s *aaveSource) set(account common.Address, healthPercent int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health[account] = healthPercent
+}
+
+func (s *aaveSource) Market() AaveMarket {
+	return AavePolygonV3
+}
+
+func (s *aaveSource) UserAccountData(ctx context.Context, user common.Address) (*AaveAccountData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	health := s.health[user]
+	// available borrows shrink with the health, down to 0 at a health of 1
+	available := health - 100
+	if available < 0 {
+		available = 0
+	}
+	return &AaveAccountData{
+		TotalCollateral:      big.NewInt(200e8),
+		TotalDebt:            big.NewInt(100e8),
+		AvailableBorrows:     new(big.Int).Mul(big.NewInt(available), big.NewInt(1e8)),
+		LiquidationThreshold: big.NewInt(8000),
+		LTV:                  big.NewInt(7000),
+		HealthFactor:         new(big.Int).Mul(big.NewInt(health), big.NewInt(1e16)),
+		BaseDecimals:         8,
+	}, nil
+}
+
+func nextAaveEvent(t *testing.T, w *AaveWatcher) AaveEvent {
+	t.Helper()
+	select {
+	case event := <-w.Events():
+		return event
+	case <-time.After(time.Second * 5):
+		t.Fatal("no event")
+		return AaveEvent{}
+	}
+}
+
+func Test_AaveWatcher(t *testing.T) {
+	user := common.HexToAddress(account)
+	src := &aaveSource{health: map[common.Address]int64{user: 150}}
+	w := NewAaveWatcher(src, WithAaveInterval(time.Millisecond*10))
+	w.Watch(user, &AaveThresholds{Health: DefaultThresholds, MinAvailableBorrows: models.NewDecimal(30)})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	event := nextAaveEvent(t, w)
+	assert.Equal(t, "polygon", event.Market)
+	assert.Equal(t, HealthSafe, event.State)
+	assert.Equal(t, HealthUnknown, event.PreviousState)
+	assert.False(t, event.LowBorrows)
+	assert.Nil(t, event.Previous)
+
+	// below the available borrows threshold, but still above the warn one
+	src.set(user, 125)
+	event = nextAaveEvent(t, w)
+	assert.Equal(t, HealthSafe, event.State)
+	assert.True(t, event.LowBorrows)
+	assert.Equal(t, "1.5", event.Previous.Health().String())
+
+	src.set(user, 99)
+	event = nextAaveEvent(t, w)
+	assert.Equal(t, HealthRisk, event.State)
+	assert.Equal(t, HealthSafe, event.PreviousState)
+	assert.Equal(t, "0.99", event.Data.Health().String())
+
+	src.mu.Lock()
+	src.err = errors.New("node down")
+	src.mu.Unlock()
+	event = nextAaveEvent(t, w)
+	require.Error(t, event.Err)
+	assert.False(t, event.Transition())
+	assert.Equal(t, HealthRisk, event.State)
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.NotZero(t, w.Stats().Delivered)
+	assert.Error(t, w.Run(context.Background()))
+}
@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// AaveThresholds are the levels at which an aave account is signalled. Health
+// applies to the health factor, and an account is flagged low on borrows once
+// its available borrows, in the base currency of the pool, fall to or below
+// MinAvailableBorrows. A zero MinAvailableBorrows disables the flag
+type AaveThresholds struct {
+	Health              Thresholds
+	MinAvailableBorrows models.Decimal
+}
+
+// DefaultAaveThresholds are the thresholds of accounts watched without their own
+var DefaultAaveThresholds = AaveThresholds{Health: DefaultThresholds}
+
+// AaveEvent is emitted by an AaveWatcher when an account changes state or
+// becomes, or stops being, low on borrows, including the first time it is
+// read. When reading it fails Err is set and the rest is left as it was
+type AaveEvent struct {
+	Market             string
+	Account            common.Address
+	Data               *AaveAccountData
+	Previous           *AaveAccountData
+	State              HealthState
+	PreviousState      HealthState
+	LowBorrows         bool
+	PreviousLowBorrows bool
+	Time               time.Time
+	Err                error
+}
+
+// Transition returns whether the event is a change of state or of the low
+// borrows flag
+func (e AaveEvent) Transition() bool {
+	return e.Err == nil && (e.State != e.PreviousState || e.LowBorrows != e.PreviousLowBorrows)
+}
+
+// String returns a one line description of the event, suitable for logging
+func (e AaveEvent) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("aave %s account %s read failed: %s", e.Market, e.Account.Hex(), e.Err)
+	}
+	return fmt.Sprintf("aave %s account %s health %s available borrows %s %s -> %s low borrows %t",
+		e.Market, e.Account.Hex(), e.Data.Health(), e.Data.AvailableBorrowsValue(), e.PreviousState, e.State, e.LowBorrows)
+}
+
+// AaveWatcherOption is used to configure an AaveWatcher
+type AaveWatcherOption func(*AaveWatcher)
+
+// WithAaveInterval sets how often the watched accounts are read
+func WithAaveInterval(interval time.Duration) AaveWatcherOption {
+	return func(w *AaveWatcher) {
+		w.interval = interval
+	}
+}
+
+// WithAaveThresholds sets the thresholds of accounts watched without their own
+func WithAaveThresholds(thresholds AaveThresholds) AaveWatcherOption {
+	return func(w *AaveWatcher) {
+		w.thresholds = thresholds
+	}
+}
+
+// WithAaveBuffer sets the size of the events channel
+func WithAaveBuffer(size int) AaveWatcherOption {
+	return func(w *AaveWatcher) {
+		w.events = make(chan AaveEvent, size)
+	}
+}
+
+// AaveWatcher is used to watch the health factor and available borrows of
+// aave accounts, with the event model of the PortfolioWatcher: events on
+// changes of state, hysteresis on the thresholds and delivery without
+// blocking, dropping the events nobody reads
+type AaveWatcher struct {
+	reader     AaveReader
+	interval   time.Duration
+	thresholds AaveThresholds
+	events     chan AaveEvent
+
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+
+	mu       sync.Mutex
+	running  bool
+	accounts map[common.Address]*aaveAccount
+}
+
+type aaveAccount struct {
+	thresholds AaveThresholds
+	data       *AaveAccountData
+	state      HealthState
+	lowBorrows bool
+}
+
+// NewAaveWatcher returns a watcher reading accounts through reader, ex. an
+// AavePool of one of the AaveMarkets
+func NewAaveWatcher(reader AaveReader, opts ...AaveWatcherOption) *AaveWatcher {
+	w := &AaveWatcher{
+		reader:     reader,
+		interval:   DefaultWatchInterval,
+		thresholds: DefaultAaveThresholds,
+		events:     make(chan AaveEvent, DefaultEventBuffer),
+		accounts:   make(map[common.Address]*aaveAccount),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Watch starts watching an account, a nil thresholds uses the watcher's defaults
+func (w *AaveWatcher) Watch(account common.Address, thresholds *AaveThresholds) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	acct := &aaveAccount{thresholds: w.thresholds}
+	if thresholds != nil {
+		acct.thresholds = *thresholds
+	}
+	w.accounts[account] = acct
+}
+
+// Unwatch stops watching the given accounts
+func (w *AaveWatcher) Unwatch(accounts ...common.Address) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, account := range accounts {
+		delete(w.accounts, account)
+	}
+}
+
+// Events returns the channel events are delivered on, it is closed once Run returns
+func (w *AaveWatcher) Events() <-chan AaveEvent {
+	return w.events
+}
+
+// Stats returns the number of delivered and dropped events
+func (w *AaveWatcher) Stats() WatcherStats {
+	return WatcherStats{Delivered: w.delivered.Load(), Dropped: w.dropped.Load()}
+}
+
+// Run reads the accounts every interval until ctx is done, and then closes
+// the events channel. A watcher can only be run once
+func (w *AaveWatcher) Run(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return errors.New("watcher is already running")
+	}
+	w.running = true
+	w.mu.Unlock()
+	defer close(w.events)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.check(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// check reads every watched account, emitting the events of those that changed
+func (w *AaveWatcher) check(ctx context.Context) {
+	w.mu.Lock()
+	accounts := make([]common.Address, 0, len(w.accounts))
+	for account := range w.accounts {
+		accounts = append(accounts, account)
+	}
+	w.mu.Unlock()
+	for _, account := range accounts {
+		data, err := w.reader.UserAccountData(ctx, account)
+		if ctx.Err() != nil {
+			return
+		}
+		w.mu.Lock()
+		acct, ok := w.accounts[account]
+		if !ok {
+			// unwatched during the read
+			w.mu.Unlock()
+			continue
+		}
+		event := AaveEvent{
+			Market:             w.reader.Market().Name,
+			Account:            account,
+			Data:               acct.data,
+			Previous:           acct.data,
+			State:              acct.state,
+			PreviousState:      acct.state,
+			LowBorrows:         acct.lowBorrows,
+			PreviousLowBorrows: acct.lowBorrows,
+			Time:               time.Now(),
+			Err:                err,
+		}
+		if err == nil {
+			event.Data = data
+			if data.HasDebt() {
+				event.State = acct.thresholds.Health.State(acct.state, data.Health())
+			} else {
+				// an account without debt can not be liquidated
+				event.State = HealthSafe
+			}
+			minBorrows := acct.thresholds.MinAvailableBorrows
+			event.LowBorrows = minBorrows.Sign() > 0 && data.AvailableBorrowsValue().Cmp(minBorrows) <= 0
+			acct.data, acct.state, acct.lowBorrows = data, event.State, event.LowBorrows
+		}
+		w.mu.Unlock()
+		if err != nil || event.Transition() {
+			w.emit(event)
+		}
+	}
+}
+
+// emit delivers an event without blocking
+func (w *AaveWatcher) emit(event AaveEvent) {
+	select {
+	case w.events <- event:
+		w.delivered.Add(1)
+	default:
+		w.dropped.Add(1)
+	}
+}
@@ -130,16 +130,10 @@ func Test_AccountBalanceOfUnderlying_goerli(t *testing.T) {
 	bclient := client.NewBClient(auth, ethclient)
 	assert.Nil(t, err)
 	owner := client.Address(acc.Address.String())
-	tx, err := bclient.BalanceOfUnderlying(ctx, client.CompoundUSDC_goerli, owner)
-	assert.NotNil(t, tx)
-
-	tt := tx.Value()
-
-	if tt != nil {
-		kk := tt.String()
-		fmt.Println(kk)
-	}
-	fmt.Println(tt)
+	balance, err := bclient.BalanceOfUnderlying(ctx, client.CompoundUSDC_goerli, owner)
+	assert.Nil(t, err)
+	assert.NotNil(t, balance)
+	fmt.Println(balance)
 }
 
 func Test_SupplyRate_goerli(t *testing.T) {
@@ -175,13 +169,10 @@ func Test_SupplyExchangeRate_goerli(t *testing.T) {
 	auth, ethclient, err := client.ConfigToOpts(&cfg)
 	bclient := client.NewBClient(auth, ethclient)
 	assert.Nil(t, err)
-	tx, err := bclient.ExchangeRateCurrent(ctx, client.CompoundUSDC_goerli)
-	assert.NotNil(t, tx)
-
-	tt := tx.Value().Int64()
-	v := float32(tt) / 1000000000000000000
-	fmt.Println(v)
-	fmt.Println(tt)
+	rate, err := bclient.ExchangeRateCurrent(ctx, client.CompoundUSDC_goerli)
+	assert.Nil(t, err)
+	assert.NotNil(t, rate)
+	fmt.Println(rate)
 }
 
 func Test_DecryptKey(t *testing.T) {
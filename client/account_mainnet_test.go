@@ -183,15 +183,9 @@ func Test_AccountBalanceOfUnderlying(t *testing.T) {
 	bclient := client.NewBClient(auth, ethclient)
 	assert.Nil(t, err)
 	owner := client.Address("")
-	tx, err := bclient.BalanceOfUnderlying(ctx, client.CompoundUSDC, owner)
-	assert.NotNil(t, tx)
-
-	tt := tx.Value()
-
-	if tt != nil {
-		kk := tt.String()
-		fmt.Println(kk)
-	}
-	fmt.Println(tt)
+	balance, err := bclient.BalanceOfUnderlying(ctx, client.CompoundUSDC, owner)
+	assert.Nil(t, err)
+	assert.NotNil(t, balance)
+	fmt.Println(balance)
 
 }
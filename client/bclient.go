@@ -7,27 +7,65 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/big"
+	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	cbat "github.com/musinit/go-defi/v2/bindings/cbat"
-	cdai "github.com/musinit/go-defi/v2/bindings/cdai"
-	ceth "github.com/musinit/go-defi/v2/bindings/ceth"
 	comptroller "github.com/musinit/go-defi/v2/bindings/comptroller"
-	crep "github.com/musinit/go-defi/v2/bindings/crep"
-	csai "github.com/musinit/go-defi/v2/bindings/csai"
-	cusdc "github.com/musinit/go-defi/v2/bindings/cusdc"
-	cwbtc "github.com/musinit/go-defi/v2/bindings/cusdc"
-	czrx "github.com/musinit/go-defi/v2/bindings/cusdc"
+	erc20 "github.com/musinit/go-defi/v2/bindings/usdc"
+	"github.com/musinit/go-defi/v2/cache"
 	"github.com/musinit/go-defi/v2/config"
 )
 
 // BClient is an ethereum blockchain client
 type BClient struct {
-	auth   *bind.TransactOpts
-	client *ethclient.Client
+	auth     *bind.TransactOpts
+	client   *ethclient.Client
+	cache    *cache.Cache
+	cacheTTL time.Duration
+	// cachedMu guards cached, the latest block each key was cached at
+	cachedMu sync.Mutex
+	cached   map[string]uint64
+	markets  *MarketRegistry
+	// comptroller is the address markets are entered and exited through
+	comptroller Address
+}
+
+// BClientOption is used to configure a BClient
+type BClientOption func(*BClient)
+
+// WithChainCache caches contract reads keyed by the block they were read at,
+// so repeated reads within the same block hit the cache. Only the entry of
+// the latest block read is kept for each read, the entry of an older block is
+// evicted once a newer block is read. Entries also expire after ttl, a ttl of
+// 0 keeps them until they are evicted
+func WithChainCache(c *cache.Cache, ttl time.Duration) BClientOption {
+	return func(bc *BClient) {
+		bc.cache = c
+		bc.cacheTTL = ttl
+		bc.cached = make(map[string]uint64)
+	}
+}
+
+// WithMarkets sets the registry ctoken addresses are looked up in, by
+// default the mainnet and goerli markets
+func WithMarkets(r *MarketRegistry) BClientOption {
+	return func(bc *BClient) {
+		bc.markets = r
+	}
+}
+
+// WithComptroller sets the comptroller markets are entered and exited
+// through, by default the mainnet Unitroller
+func WithComptroller(address Address) BClientOption {
+	return func(bc *BClient) {
+		bc.comptroller = address
+	}
 }
 
 // ConfigToOpts structs the optios needed for a bclient
@@ -53,8 +91,75 @@ func ConfigToOpts(cfg *config.Config) (*bind.TransactOpts, *ethclient.Client, er
 }
 
 // NewBClient registers a new blockchain client
-func NewBClient(auth *bind.TransactOpts, client *ethclient.Client) *BClient {
-	return &BClient{auth: auth, client: client}
+func NewBClient(auth *bind.TransactOpts, client *ethclient.Client, opts ...BClientOption) *BClient {
+	bc := &BClient{auth: auth, client: client, comptroller: Unitroller}
+	for _, opt := range opts {
+		opt(bc)
+	}
+	if bc.markets == nil {
+		bc.markets = NewDefaultMarketRegistry()
+	}
+	return bc
+}
+
+// Markets returns the registry ctoken addresses are looked up in
+func (bc *BClient) Markets() *MarketRegistry {
+	return bc.markets
+}
+
+// CToken returns the ctoken of the registered market at address, or
+// ErrMarketNotFound for unknown addresses
+func (bc *BClient) CToken(address Address) (CToken, error) {
+	return bc.markets.CToken(address, bc.client)
+}
+
+// readBig performs a contract read returning a big.Int. With a chain cache
+// configured the read is pinned to the latest block, and cached under key
+// and that block number
+func (bc *BClient) readBig(ctx context.Context, key string, call func(opts *bind.CallOpts) (*big.Int, error)) (*big.Int, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	if bc.cache == nil {
+		return call(opts)
+	}
+	block, err := bc.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts.BlockNumber = new(big.Int).SetUint64(block)
+	if cached, ok := bc.cache.Get(blockKey(key, block)); ok {
+		if value, ok := new(big.Int).SetString(string(cached), 10); ok {
+			return value, nil
+		}
+	}
+	value, err := call(opts)
+	if err != nil || value == nil {
+		return value, err
+	}
+	bc.setCached(key, block, []byte(value.String()))
+	return value, nil
+}
+
+// setCached caches the value of key read at block, and evicts the entry of
+// key cached at an older block. Values read at an older block than the one
+// cached are not cached
+func (bc *BClient) setCached(key string, block uint64, value []byte) {
+	bc.cachedMu.Lock()
+	last, ok := bc.cached[key]
+	if ok && block < last {
+		bc.cachedMu.Unlock()
+		return
+	}
+	bc.cached[key] = block
+	bc.cachedMu.Unlock()
+	bc.cache.Set(blockKey(key, block), value, bc.cacheTTL)
+	if ok && block > last {
+		bc.cache.Delete(blockKey(key, last))
+	}
+}
+
+// blockKey is the cache key of a read of key at block
+func blockKey(key string, block uint64) string {
+	return fmt.Sprintf("%s@%d", key, block)
 }
 
 // GetPrice returns the price/exchange rate of the cToken (WIP)
@@ -65,68 +170,28 @@ func (bc *BClient) GetPrice(ctx context.Context, address Address) (*big.Int, err
 		You need to fetch an exchange rate for your cToken, multiply by the underlying quantity and then divide the total by 1e18 to get eth value
 		If you want to get to $ value, just do the opposite with usdc. Fetch cUSDC exchange rate from oracle, multiply previous product by 1e18, then divide this by the usdc exchange rate. You should get usdc value
 	*/
-	switch address {
-	case CompoundBAT:
-		contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		return contract.ExchangeRateStored(nil)
-	case CompoundDAI:
-		contract, err := cdai.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		return contract.ExchangeRateStored(nil)
-	case CompoundSAI:
-		contract, err := csai.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		return contract.ExchangeRateStored(nil)
-	case CompoundETH:
-		contract, err := ceth.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		return contract.ExchangeRateStored(nil)
-	case CompoundREP:
-		contract, err := crep.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		return contract.ExchangeRateStored(nil)
-	case CompoundUSDC:
-		contract, err := cusdc.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		return contract.ExchangeRateStored(nil)
-	case CompoundWBTC:
-		contract, err := cwbtc.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		return contract.ExchangeRateStored(nil)
-	case CompoundZRX:
-		contract, err := czrx.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		return contract.ExchangeRateStored(nil)
-	default:
-		return nil, errors.New("invalid contract type")
+	return bc.readBig(ctx, "exchangeRateStored:"+address.String(), func(opts *bind.CallOpts) (*big.Int, error) {
+		return bc.exchangeRateStored(opts, address)
+	})
+}
+
+// exchangeRateStored reads the stored exchange rate of the given ctoken
+func (bc *BClient) exchangeRateStored(opts *bind.CallOpts, address Address) (*big.Int, error) {
+	ctoken, err := bc.CToken(address)
+	if err != nil {
+		return nil, err
 	}
+	return ctoken.ExchangeRateStored(opts)
 }
 
 // CanLiquidate is used to check whether or not the given address can be liquidated
 func (bc *BClient) CanLiquidate(ctx context.Context, account common.Address) (bool, error) {
-	contract, err := comptroller.NewBindings(Comptroller.EthAddress(), bc.client)
+	contract, err := comptroller.NewBindings(bc.comptroller.EthAddress(), bc.client)
 	if err != nil {
 		return false, err
 	}
 
-	errCode, liquidity, shortfall, err := contract.GetAccountLiquidity(nil, account)
+	errCode, liquidity, shortfall, err := contract.GetAccountLiquidity(&bind.CallOpts{Context: ctx}, account)
 	if err != nil {
 		return false, err
 	}
@@ -151,257 +216,243 @@ func (bc *BClient) CanLiquidate(ctx context.Context, account common.Address) (bo
 	return false, nil
 }
 
+// Approve is used to let spender, ex. a ctoken, transfer up to amount of the
+// token at address from the wallet of the client
 func (bc *BClient) Approve(ctx context.Context, address, spender Address, amount *big.Int) error {
-	var (
-		tx  *types.Transaction
-		err error
-	)
 	contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
 	if err != nil {
 		return err
 	}
-
-	tx, err = contract.Approve(bc.auth, spender.EthAddress(), amount)
-	if err != nil {
-		return err
-	}
-	rcpt, err := bind.WaitMined(ctx, bc.client, tx)
-	if err != nil {
-		return err
-	}
-	if rcpt.Status != 1 {
-		return errors.New("tx receipt status is not 1, indicating a failure occurred")
-	}
-	return nil
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.Approve(auth, spender.EthAddress(), amount)
+	})
 }
 
 // Borrow is used to borrow a particular address
 func (bc *BClient) Borrow(ctx context.Context, address Address, borrowAmount *big.Int) error {
-	var (
-		tx  *types.Transaction
-		err error
-	)
-	switch address {
-	case CompoundBAT:
-		contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.Borrow(bc.auth, borrowAmount)
-	case CompoundDAI:
-		contract, err := cdai.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.Borrow(bc.auth, borrowAmount)
-	case CompoundSAI:
-		contract, err := csai.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.Borrow(bc.auth, borrowAmount)
-	case CompoundETH:
-		contract, err := ceth.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.Borrow(bc.auth, borrowAmount)
-	case CompoundREP:
-		contract, err := crep.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.Borrow(bc.auth, borrowAmount)
-	case CompoundUSDC:
-		contract, err := cusdc.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.Borrow(bc.auth, borrowAmount)
-	case CompoundWBTC:
-		contract, err := cwbtc.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.Borrow(bc.auth, borrowAmount)
-	case CompoundZRX:
-		contract, err := czrx.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.Borrow(bc.auth, borrowAmount)
-	}
+	ctoken, err := bc.CToken(address)
 	if err != nil {
 		return err
 	}
-	rcpt, err := bind.WaitMined(ctx, bc.client, tx)
-	if err != nil {
-		return err
-	}
-	if rcpt.Status != 1 {
-		return errors.New("tx receipt status is not 1, indicating a failure occurred")
-	}
-	return nil
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ctoken.Borrow(auth, borrowAmount)
+	})
 }
 
 // GetBorrowRate calls BorrowRatePerBlock to retrieve the current borrow interest rate
 func (bc *BClient) GetBorrowRate(ctx context.Context, address Address) (*big.Int, error) {
-	var (
-		rate *big.Int
-		err  error
-	)
-	switch address {
-	case CompoundBAT:
-		contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		rate, err = contract.BorrowRatePerBlock(nil)
-	case CompoundDAI:
-		contract, err := cdai.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		rate, err = contract.BorrowRatePerBlock(nil)
-	case CompoundSAI:
-		contract, err := csai.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		rate, err = contract.BorrowRatePerBlock(nil)
-	case CompoundETH:
-		contract, err := ceth.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		rate, err = contract.BorrowRatePerBlock(nil)
-	case CompoundREP:
-		contract, err := crep.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		rate, err = contract.BorrowRatePerBlock(nil)
-	case CompoundUSDC:
-		contract, err := cusdc.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		rate, err = contract.BorrowRatePerBlock(nil)
-	case CompoundWBTC:
-		contract, err := cwbtc.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		rate, err = contract.BorrowRatePerBlock(nil)
-	case CompoundZRX:
-		contract, err := czrx.NewBindings(address.EthAddress(), bc.client)
-		if err != nil {
-			return nil, err
-		}
-		rate, err = contract.BorrowRatePerBlock(nil)
-	}
+	return bc.readBig(ctx, "borrowRatePerBlock:"+address.String(), func(opts *bind.CallOpts) (*big.Int, error) {
+		return bc.borrowRatePerBlock(opts, address)
+	})
+}
+
+// borrowRatePerBlock reads the borrow rate of the given ctoken
+func (bc *BClient) borrowRatePerBlock(opts *bind.CallOpts, address Address) (*big.Int, error) {
+	ctoken, err := bc.CToken(address)
 	if err != nil {
 		return nil, err
 	}
-	return rate, nil
+	return ctoken.BorrowRatePerBlock(opts)
 }
 
+// Mint is used to supply mintAmount of the underlying token to the given
+// ctoken, the ctoken must be approved to spend it. On the ether market the
+// amount is sent as the value of the transaction
 func (bc *BClient) Mint(ctx context.Context, address Address, mintAmount *big.Int) error {
-	var (
-		tx  *types.Transaction
-		err error
-	)
-	contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
+	ctoken, err := bc.CToken(address)
 	if err != nil {
 		return err
 	}
-	tx, err = contract.Mint(bc.auth, mintAmount)
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ctoken.Mint(auth, mintAmount)
+	})
+}
+
+// RepayBorrow is used to repay repayAmount of the underlying token borrowed
+// from the given ctoken, the ctoken must be approved to spend it. An amount
+// of MaxRepay repays the whole borrow, except on the ether market where the
+// amount is sent as the value of the transaction
+func (bc *BClient) RepayBorrow(ctx context.Context, address Address, repayAmount *big.Int) error {
+	ctoken, err := bc.CToken(address)
 	if err != nil {
 		return err
 	}
-	rcpt, err := bind.WaitMined(ctx, bc.client, tx)
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ctoken.RepayBorrow(auth, repayAmount)
+	})
+}
+
+// RepayBorrowBehalf is like RepayBorrow, but repays the borrow of borrower
+func (bc *BClient) RepayBorrowBehalf(ctx context.Context, address, borrower Address, repayAmount *big.Int) error {
+	ctoken, err := bc.CToken(address)
 	if err != nil {
 		return err
 	}
-	fmt.Println(rcpt.Status)
-	if rcpt.Status != 1 {
-		return errors.New("tx receipt status is not 1, indicating a failure occurred")
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ctoken.RepayBorrowBehalf(auth, borrower.EthAddress(), repayAmount)
+	})
+}
+
+// Redeem is used to exchange redeemTokens ctokens of the given ctoken back to
+// the underlying token
+func (bc *BClient) Redeem(ctx context.Context, address Address, redeemTokens *big.Int) error {
+	ctoken, err := bc.CToken(address)
+	if err != nil {
+		return err
 	}
-	return nil
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ctoken.Redeem(auth, redeemTokens)
+	})
 }
 
-func (bc *BClient) MintAaveV3(ctx context.Context, address, owner Address, mintAmount *big.Int, token common.Address) error {
-	var (
-		tx  *types.Transaction
-		err error
-	)
-	contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
+// RedeemUnderlying is used to withdraw redeemAmount of the underlying token
+// supplied to the given ctoken
+func (bc *BClient) RedeemUnderlying(ctx context.Context, address Address, redeemAmount *big.Int) error {
+	ctoken, err := bc.CToken(address)
 	if err != nil {
 		return err
 	}
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ctoken.RedeemUnderlying(auth, redeemAmount)
+	})
+}
 
-	tx, err = contract.Supply(bc.auth, token, mintAmount, owner.EthAddress(), 0)
+// EnterMarkets is used to use the supplies of the wallet in the given
+// ctokens as collateral, which is required before borrowing against them.
+// It fails when any of the markets was not entered
+func (bc *BClient) EnterMarkets(ctx context.Context, addresses ...Address) error {
+	markets := make([]common.Address, 0, len(addresses))
+	for _, address := range addresses {
+		if _, err := bc.markets.Market(address); err != nil {
+			return fmt.Errorf("%s: %w", address, err)
+		}
+		markets = append(markets, address.EthAddress())
+	}
+	contract, err := comptroller.NewBindings(bc.comptroller.EthAddress(), bc.client)
 	if err != nil {
 		return err
 	}
-	rcpt, err := bind.WaitMined(ctx, bc.client, tx)
+	rcpt, err := bc.transactReceipt(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.EnterMarkets(auth, markets)
+	})
 	if err != nil {
 		return err
 	}
-	if rcpt.Status != 1 {
-		return errors.New("tx receipt status is not 1, indicating a failure occurred")
+	return checkEntered(ctx, contract, bc.comptroller.EthAddress(), bc.auth.From, rcpt, markets)
+}
+
+// checkEntered returns an error for the first market the account is not a
+// member of once rcpt is mined. The comptroller reports per market failures,
+// ex. a market not listed, only in the return value of enterMarkets, and
+// emits MarketEntered for each market entered. Markets the account already
+// was a member of emit nothing, their membership is read instead
+func checkEntered(ctx context.Context, contract *comptroller.Bindings, address, account common.Address, rcpt *types.Receipt, markets []common.Address) error {
+	entered := make(map[common.Address]bool)
+	for _, log := range rcpt.Logs {
+		if log.Address != address || len(log.Topics) == 0 || log.Topics[0] != marketEnteredTopic {
+			continue
+		}
+		event, err := contract.ParseMarketEntered(*log)
+		if err != nil {
+			return err
+		}
+		if event.Account == account {
+			entered[event.CToken] = true
+		}
+	}
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: rcpt.BlockNumber}
+	for _, market := range markets {
+		if entered[market] {
+			continue
+		}
+		member, err := contract.CheckMembership(opts, account, market)
+		if err != nil {
+			return err
+		}
+		if !member {
+			return fmt.Errorf("tx %s: market %s was not entered: %w", rcpt.TxHash.Hex(), market.Hex(), ErrTxFailed)
+		}
 	}
 	return nil
 }
 
-func (bc *BClient) MintAaveV2(ctx context.Context, address, owner Address, mintAmount *big.Int) error {
-	var (
-		tx  *types.Transaction
-		err error
-	)
-	contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
+// ExitMarket is used to stop using the supply of the wallet in the given
+// ctoken as collateral. It fails while the wallet borrows from the market,
+// or needs the collateral for its other borrows
+func (bc *BClient) ExitMarket(ctx context.Context, address Address) error {
+	if _, err := bc.markets.Market(address); err != nil {
+		return fmt.Errorf("%s: %w", address, err)
+	}
+	contract, err := comptroller.NewBindings(bc.comptroller.EthAddress(), bc.client)
 	if err != nil {
 		return err
 	}
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.ExitMarket(auth, address.EthAddress())
+	})
+}
 
-	tx, err = contract.Deposit(bc.auth, AaveUSDTv2.EthAddress(), mintAmount, owner.EthAddress(), 0)
+// UnderlyingDecimals returns the decimals of the underlying token of the
+// given ctoken, 18 for the ether market. Decimals of discovered markets are
+// not read again
+func (bc *BClient) UnderlyingDecimals(ctx context.Context, address Address) (uint8, error) {
+	market, err := bc.markets.Market(address)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	if market.Ether {
+		return 18, nil
 	}
-	rcpt, err := bind.WaitMined(ctx, bc.client, tx)
+	if market.UnderlyingDecimals != 0 {
+		return market.UnderlyingDecimals, nil
+	}
+	ctoken, err := NewCToken(market, bc.client)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	fmt.Println(rcpt.Status)
-	if rcpt.Status != 1 {
-		return errors.New("tx receipt status is not 1, indicating a failure occurred")
+	opts := &bind.CallOpts{Context: ctx}
+	underlying, err := ctoken.Underlying(opts)
+	if err != nil {
+		return 0, err
 	}
-	return nil
+	if underlying == (common.Address{}) {
+		return 18, nil
+	}
+	token, err := erc20.NewBindings(underlying, bc.client)
+	if err != nil {
+		return 0, err
+	}
+	return token.Decimals(opts)
 }
 
-func (bc *BClient) WithdrawAaveV3(ctx context.Context, address, owner Address, withdrawAmount *big.Int, token common.Address) error {
-	var (
-		tx  *types.Transaction
-		err error
-	)
+func (bc *BClient) MintAaveV3(ctx context.Context, address, owner Address, mintAmount *big.Int, token common.Address) error {
 	contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
 	if err != nil {
 		return err
 	}
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.Supply(auth, token, mintAmount, owner.EthAddress(), 0)
+	})
+}
 
-	tx, err = contract.Withdraw(bc.auth, token, withdrawAmount, owner.EthAddress())
+func (bc *BClient) MintAaveV2(ctx context.Context, address, owner Address, mintAmount *big.Int) error {
+	contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
 	if err != nil {
 		return err
 	}
-	rcpt, err := bind.WaitMined(ctx, bc.client, tx)
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.Deposit(auth, AaveUSDTv2.EthAddress(), mintAmount, owner.EthAddress(), 0)
+	})
+}
+
+func (bc *BClient) WithdrawAaveV3(ctx context.Context, address, owner Address, withdrawAmount *big.Int, token common.Address) error {
+	contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
 	if err != nil {
 		return err
 	}
-	fmt.Println(rcpt.Status)
-	if rcpt.Status != 1 {
-		return errors.New("tx receipt status is not 1, indicating a failure occurred")
-	}
-	return nil
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.Withdraw(auth, token, withdrawAmount, owner.EthAddress())
+	})
 }
 
 func (bc *BClient) GetReserveDataAaveV3(ctx context.Context, address, owner, token Address) (int64, error) {
@@ -451,46 +502,32 @@ func (bc *BClient) GetReserveNormilizedIncomeAaveV3(ctx context.Context, address
 	return result, nil
 }
 
-func (bc *BClient) GetUserAccountDataAaveV3(ctx context.Context, address, owner, token Address) (int64, error) {
-	var (
-		err error
-	)
-	contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
-	if err != nil {
-		return 0, err
-	}
-
-	reserveData, err := contract.GetUserAccountData(&bind.CallOpts{
-		Pending: false,
-		From:    address.EthAddress(),
-		Context: ctx,
-	}, owner.EthAddress())
+// GetUserAccountDataAaveV3 returns the account data of owner in the aave v3
+// pool at address
+func (bc *BClient) GetUserAccountDataAaveV3(ctx context.Context, address, owner, token Address) (*AaveAccountData, error) {
+	pool, err := NewAavePool(bc.client, AaveMarket{Pool: address, Version: 3})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-
-	result := reserveData.HealthFactor.Int64()
-	return result, nil
+	return pool.UserAccountData(ctx, owner.EthAddress())
 }
 
+// SupplyRatePerBlock returns the current supply interest rate of the given ctoken
 func (bc *BClient) SupplyRatePerBlock(ctx context.Context, address Address) (*big.Int, error) {
-	contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
+	ctoken, err := bc.CToken(address)
 	if err != nil {
 		return nil, err
 	}
-	return contract.SupplyRatePerBlock(&bind.CallOpts{
-		Pending: false,
-		From:    address.EthAddress(),
-		Context: ctx,
+	return bc.readBig(ctx, "supplyRatePerBlock:"+address.String(), func(opts *bind.CallOpts) (*big.Int, error) {
+		opts.From = address.EthAddress()
+		return ctoken.SupplyRatePerBlock(opts)
 	})
 }
 
-func (bc *BClient) BalanceOfUnderlying(ctx context.Context, address Address, owner Address) (*types.Transaction, error) {
-	contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
-	if err != nil {
-		return nil, err
-	}
-	return contract.BalanceOfUnderlying(bc.auth, owner.EthAddress())
+// BalanceOfUnderlying returns the underlying balance of owner in the given
+// ctoken, with interest accrued up to the latest block
+func (bc *BClient) BalanceOfUnderlying(ctx context.Context, address Address, owner Address) (*big.Int, error) {
+	return bc.callCurrent(ctx, address, "balanceOfUnderlying", owner.EthAddress())
 }
 
 func (bc *BClient) BalanceOf(ctx context.Context, address Address, owner Address) (*big.Int, error) {
@@ -498,19 +535,32 @@ func (bc *BClient) BalanceOf(ctx context.Context, address Address, owner Address
 	if err != nil {
 		return nil, err
 	}
-	return contract.BalanceOf(&bind.CallOpts{
-		Pending: false,
-		From:    address.EthAddress(),
-		Context: ctx,
-	}, owner.EthAddress())
+	return bc.readBig(ctx, "balanceOf:"+address.String()+":"+owner.String(), func(opts *bind.CallOpts) (*big.Int, error) {
+		opts.From = address.EthAddress()
+		return contract.BalanceOf(opts, owner.EthAddress())
+	})
 }
 
-func (bc *BClient) ExchangeRateCurrent(ctx context.Context, address Address) (*types.Transaction, error) {
-	contract, err := cbat.NewBindings(address.EthAddress(), bc.client)
+// ExchangeRateCurrent returns the exchange rate of the given ctoken, with
+// interest accrued up to the latest block
+func (bc *BClient) ExchangeRateCurrent(ctx context.Context, address Address) (*big.Int, error) {
+	return bc.callCurrent(ctx, address, "exchangeRateCurrent")
+}
+
+// callCurrent reads the result of a ctoken method that accrues interest
+// before returning a value. The accrual is simulated with a call, no
+// transaction is sent
+func (bc *BClient) callCurrent(ctx context.Context, address Address, method string, params ...interface{}) (*big.Int, error) {
+	contract, err := cbat.NewBindingsCaller(address.EthAddress(), bc.client)
 	if err != nil {
 		return nil, err
 	}
-	return contract.ExchangeRateCurrent(bc.auth)
+	var out []interface{}
+	raw := &cbat.BindingsCallerRaw{Contract: contract}
+	if err := raw.Call(&bind.CallOpts{Context: ctx}, &out, method, params...); err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
 }
 
 // LiquidateOpts is used to provide input parameters to
@@ -523,78 +573,11 @@ type LiquidateOpts struct {
 
 // GetLiqd is used to liquidate a borrower
 func (bc *BClient) GetLiqd(ctx context.Context, borrowToken Address, opts LiquidateOpts) error {
-	var (
-		tx  *types.Transaction
-		err error
-	)
-	switch borrowToken {
-	case CompoundBAT:
-		contract, err := cbat.NewBindings(borrowToken.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.LiquidateBorrow(bc.auth, opts.Borrower, opts.RepayAmount, opts.CTokenCollateral.EthAddress())
-	case CompoundDAI:
-		contract, err := cdai.NewBindings(borrowToken.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.LiquidateBorrow(bc.auth, opts.Borrower, opts.RepayAmount, opts.CTokenCollateral.EthAddress())
-	case CompoundSAI:
-		contract, err := csai.NewBindings(borrowToken.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.LiquidateBorrow(bc.auth, opts.Borrower, opts.RepayAmount, opts.CTokenCollateral.EthAddress())
-	case CompoundETH:
-		contract, err := ceth.NewBindings(borrowToken.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.LiquidateBorrow(bc.auth, opts.Borrower, opts.CTokenCollateral.EthAddress())
-	case CompoundREP:
-		contract, err := crep.NewBindings(borrowToken.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.LiquidateBorrow(bc.auth, opts.Borrower, opts.RepayAmount, opts.CTokenCollateral.EthAddress())
-	case CompoundUSDC:
-		contract, err := cusdc.NewBindings(borrowToken.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.LiquidateBorrow(bc.auth, opts.Borrower, opts.RepayAmount, opts.CTokenCollateral.EthAddress())
-	case CompoundWBTC:
-		contract, err := cwbtc.NewBindings(borrowToken.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.LiquidateBorrow(bc.auth, opts.Borrower, opts.RepayAmount, opts.CTokenCollateral.EthAddress())
-	case CompoundZRX:
-		contract, err := czrx.NewBindings(borrowToken.EthAddress(), bc.client)
-		if err != nil {
-			return err
-		}
-		tx, err = contract.LiquidateBorrow(bc.auth, opts.Borrower, opts.RepayAmount, opts.CTokenCollateral.EthAddress())
-	}
-	if err != nil {
-		return err
-	}
-	rcpt, err := bind.WaitMined(ctx, bc.client, tx)
+	ctoken, err := bc.CToken(borrowToken)
 	if err != nil {
 		return err
 	}
-	if rcpt.Status != 1 {
-		return errors.New("tx receipt status is not 1, indicating a failure occurred")
-	}
-	return nil
-}
-
-// GetAccountSnapshot returns a snapshot of the account state
-func (bc *BClient) GetAccountSnapshot(ctx context.Context, address Address) {
-	/*
-		* https://github.com/compound-finance/compound-protocol/blob/f37f7bf8b6e1fb39239e43a4fb12fd15cf8b7c69/contracts/CErc20Delegator.sol#L226
-		* https://github.com/compound-finance/compound-protocol/blob/f37f7bf8b6e1fb39239e43a4fb12fd15cf8b7c69/contracts/CToken.sol#L203
-		(possible error, token balance, borrow balance, exchange rate mantissa)
-	*/
+	return bc.transact(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ctoken.LiquidateBorrow(auth, opts.Borrower, opts.RepayAmount, opts.CTokenCollateral.EthAddress())
+	})
 }
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/musinit/go-defi/v2/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Client_Cache(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Query().Get("addresses[]") == selfAccount {
+			w.Write([]byte(`{"error": {"error_code": 1}}`))
+			return
+		}
+		w.Write([]byte(`{"accounts":[{"address":"` + account + `"}]}`))
+	}))
+	defer srv.Close()
+
+	c := cache.New(cache.NewMemory())
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry), WithCache(c, time.Minute))
+	for i := 0; i < 3; i++ {
+		resp, err := cl.GetAccountContext(context.Background(), account)
+		require.NoError(t, err)
+		assert.Equal(t, account, resp.Accounts[0].Address)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// a different block is a different url
+	_, err := cl.GetAccountContext(context.Background(), account, AtBlock(1))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// api errors are never cached
+	for i := 0; i < 2; i++ {
+		_, err := cl.GetAccountContext(context.Background(), selfAccount)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+	assert.Equal(t, cache.Stats{Hits: 2, Misses: 4}, c.Stats())
+}
+
+// blockService answers eth_blockNumber for an in process rpc server
+type blockService struct {
+	block uint64
+}
+
+func (s *blockService) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(atomic.LoadUint64(&s.block))
+}
+
+func Test_BClient_ChainCache(t *testing.T) {
+	service := &blockService{block: 100}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+	defer server.Stop()
+	eth := ethclient.NewClient(rpc.DialInProc(server))
+	defer eth.Close()
+
+	store := cache.NewMemory()
+	c := cache.New(store)
+	bc := NewBClient(nil, eth, WithChainCache(c, 0))
+	var reads []uint64
+	read := func(opts *bind.CallOpts) (*big.Int, error) {
+		var block uint64
+		if opts.BlockNumber != nil {
+			block = opts.BlockNumber.Uint64()
+		}
+		reads = append(reads, block)
+		return big.NewInt(int64(len(reads))), nil
+	}
+	for i := 0; i < 3; i++ {
+		value, err := bc.readBig(context.Background(), "rate", read)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), value.Int64())
+	}
+	atomic.StoreUint64(&service.block, 101)
+	value, err := bc.readBig(context.Background(), "rate", read)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), value.Int64())
+	assert.Equal(t, []uint64{100, 101}, reads)
+	assert.Equal(t, cache.Stats{Hits: 2, Misses: 2}, c.Stats())
+
+	// only the entry of the latest block is kept, even without a ttl
+	for block := uint64(102); block < 200; block++ {
+		atomic.StoreUint64(&service.block, block)
+		_, err = bc.readBig(context.Background(), "rate", read)
+		require.NoError(t, err)
+		_, err = bc.readBig(context.Background(), "price", read)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, store.Len())
+	_, ok := c.Get("rate@198")
+	assert.False(t, ok)
+	_, ok = c.Get("rate@199")
+	assert.True(t, ok)
+
+	// with a ttl entries also expire within a block
+	expiring := NewBClient(nil, eth, WithChainCache(cache.New(cache.NewMemory()), time.Millisecond))
+	reads = nil
+	_, err = expiring.readBig(context.Background(), "rate", read)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = expiring.readBig(context.Background(), "rate", read)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{199, 199}, reads)
+
+	// without a cache every read goes to the chain, at the latest block
+	reads = nil
+	uncached := NewBClient(nil, eth)
+	for i := 0; i < 2; i++ {
+		_, err = uncached.readBig(context.Background(), "rate", read)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []uint64{0, 0}, reads)
+}
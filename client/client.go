@@ -1,109 +1,132 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
+	"errors"
 	"net/http"
+	"time"
 
-	"strconv"
-
+	"github.com/musinit/go-defi/v2/cache"
 	"github.com/musinit/go-defi/v2/models"
 )
 
 // Client is used to interact with the compound.finance api
 type Client struct {
-	url    string
-	client *http.Client
+	url           string
+	client        *http.Client
+	timeout       time.Duration
+	header        http.Header
+	requestHooks  []RequestHook
+	responseHooks []ResponseHook
+	retry         RetryPolicy
+	limiter       *rateLimiter
+	cache         *cache.Cache
+	cacheTTL      time.Duration
 }
 
-// NewClient is used to instantiate a new go-compound client
-func NewClient(url string) *Client {
-	return &Client{url: url, client: &http.Client{}}
+// NewClient is used to instantiate a new go-compound client. An empty
+// url uses DefaultURL
+func NewClient(url string, opts ...Option) *Client {
+	if url == "" {
+		url = DefaultURL
+	}
+	c := &Client{
+		url:     url,
+		client:  &http.Client{},
+		timeout: defaultTimeout,
+		header:  http.Header{},
+		retry:   DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // GetTotalCollateralValueInEth is used to retrieve the total collateral value
 // in eth that is owned by this account
-func (c *Client) GetTotalCollateralValueInEth(address string) (float64, error) {
-	resp, err := c.GetAccount(address)
+func (c *Client) GetTotalCollateralValueInEth(address string, opts ...ReadOption) (models.Decimal, error) {
+	return c.GetTotalCollateralValueInEthContext(context.Background(), address, opts...)
+}
+
+// GetTotalCollateralValueInEthContext is like GetTotalCollateralValueInEth but
+// uses the given context for the underlying request
+func (c *Client) GetTotalCollateralValueInEthContext(ctx context.Context, address string, opts ...ReadOption) (models.Decimal, error) {
+	resp, err := c.GetAccountContext(ctx, address, opts...)
 	if err != nil {
-		return 0, err
+		return models.Decimal{}, err
 	}
-
-	return strconv.ParseFloat(resp.Accounts[0].TotalCollateralValueInEth.Value, 64)
+	if len(resp.Accounts) == 0 {
+		return models.Decimal{}, errors.New("no accounts found")
+	}
+	return resp.Accounts[0].TotalCollateralValueInEth.Value, nil
 }
 
 // GetTotalBorrowValueInEth is used to retrieve the total collateral value
 // in eth that is owned by this account
-func (c *Client) GetTotalBorrowValueInEth(address string) (float64, error) {
-	resp, err := c.GetAccount(address)
-	if err != nil {
-		return 0, err
-	}
-
-	return strconv.ParseFloat(resp.Accounts[0].TotalBorrowValueInEth.Value, 64)
+func (c *Client) GetTotalBorrowValueInEth(address string, opts ...ReadOption) (models.Decimal, error) {
+	return c.GetTotalBorrowValueInEthContext(context.Background(), address, opts...)
 }
 
-// GetAccount is used to retrieve information on a single account
-func (c *Client) GetAccount(address string) (*models.AccountResponse, error) {
-	call := fmt.Sprintf("/account?addresses[]=%s", address)
-	apiURL := fmt.Sprintf("%s/%s", c.url, call)
-	bodyBytes, err := c.sendRequest(apiURL)
+// GetTotalBorrowValueInEthContext is like GetTotalBorrowValueInEth but
+// uses the given context for the underlying request
+func (c *Client) GetTotalBorrowValueInEthContext(ctx context.Context, address string, opts ...ReadOption) (models.Decimal, error) {
+	resp, err := c.GetAccountContext(ctx, address, opts...)
 	if err != nil {
-		return nil, err
+		return models.Decimal{}, err
 	}
-	response := &models.AccountResponse{}
-	if err := json.Unmarshal(bodyBytes, response); err != nil {
-		return nil, err
+	if len(resp.Accounts) == 0 {
+		return models.Decimal{}, errors.New("no accounts found")
 	}
-	return response, nil
+	return resp.Accounts[0].TotalBorrowValueInEth.Value, nil
+}
+
+// GetAccount is used to retrieve information on a single account, optionally
+// as of a past block or time, see AtBlock and AtTime
+func (c *Client) GetAccount(address string, opts ...ReadOption) (*models.AccountResponse, error) {
+	return c.GetAccountContext(context.Background(), address, opts...)
+}
+
+// GetAccountContext is like GetAccount but uses the given context for the request
+func (c *Client) GetAccountContext(ctx context.Context, address string, opts ...ReadOption) (*models.AccountResponse, error) {
+	return c.QueryAccounts(ctx, applyReadOptions(opts).account(NewAccountRequest().Addresses(address)))
 }
 
 // GetAccounts is used to retrieve information on many accounts
-func (c *Client) GetAccounts(pageSize, pageNum string) (*models.AccountResponse, error) {
+func (c *Client) GetAccounts(pageSize, pageNum string, opts ...ReadOption) (*models.AccountResponse, error) {
+	return c.GetAccountsContext(context.Background(), pageSize, pageNum, opts...)
+}
+
+// GetAccountsContext is like GetAccounts but uses the given context for the request
+func (c *Client) GetAccountsContext(ctx context.Context, pageSize, pageNum string, opts ...ReadOption) (*models.AccountResponse, error) {
 	// https://api.compound.finance/api/v2/account
 	if pageSize == "" {
 		pageSize = "10"
 	}
-	if pageNum == "" {
-		pageNum = "0"
-	}
-	apiURL := fmt.Sprintf("%s/account?page_size=%s&page_number=%s", c.url, pageSize, pageNum)
-	bodyBytes, err := c.sendRequest(apiURL)
+	size, num, err := parsePagination(pageSize, pageNum)
 	if err != nil {
 		return nil, err
 	}
-	response := &models.AccountResponse{}
-	if err := json.Unmarshal(bodyBytes, response); err != nil {
-		return nil, err
-	}
-	return response, nil
+	return c.QueryAccounts(ctx, applyReadOptions(opts).account(NewAccountRequest().PageSize(size).PageNumber(num)))
 }
 
-// GetCToken is used to get information on a particular ctoken
-func (c *Client) GetCToken(address string) (*models.CTokenResponse, error) {
-	call := fmt.Sprintf("/ctoken?addresses[]=%s", address)
-	apiURL := fmt.Sprintf("%s/%s", c.url, call)
-	bodyBytes, err := c.sendRequest(apiURL)
-	if err != nil {
-		return nil, err
-	}
-	response := &models.CTokenResponse{}
-	if err := json.Unmarshal(bodyBytes, response); err != nil {
-		return nil, err
-	}
-	return response, nil
+// GetCToken is used to get information on a particular ctoken, optionally
+// as of a past block or time, see AtBlock and AtTime
+func (c *Client) GetCToken(address string, opts ...ReadOption) (*models.CTokenResponse, error) {
+	return c.GetCTokenContext(context.Background(), address, opts...)
+}
+
+// GetCTokenContext is like GetCToken but uses the given context for the request
+func (c *Client) GetCTokenContext(ctx context.Context, address string, opts ...ReadOption) (*models.CTokenResponse, error) {
+	return c.QueryCTokens(ctx, applyReadOptions(opts).ctoken(NewCTokenRequest().Addresses(address)))
 }
 
 // GetCTokens is used to retrieve information on many ctokens
-func (c *Client) GetCTokens() (*models.CTokenResponse, error) {
-	apiURL := fmt.Sprintf("%s/ctoken", c.url)
-	bodyBytes, err := c.sendRequest(apiURL)
-	if err != nil {
-		return nil, err
-	}
-	response := &models.CTokenResponse{}
-	if err := json.Unmarshal(bodyBytes, response); err != nil {
-		return nil, err
-	}
-	return response, nil
+func (c *Client) GetCTokens(opts ...ReadOption) (*models.CTokenResponse, error) {
+	return c.GetCTokensContext(context.Background(), opts...)
+}
+
+// GetCTokensContext is like GetCTokens but uses the given context for the request
+func (c *Client) GetCTokensContext(ctx context.Context, opts ...ReadOption) (*models.CTokenResponse, error) {
+	return c.QueryCTokens(ctx, applyReadOptions(opts).ctoken(NewCTokenRequest()))
 }
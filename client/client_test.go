@@ -2,19 +2,22 @@ package client
 
 import (
 	"context"
+	"github.com/musinit/go-defi/v2/mockapi"
+	"github.com/musinit/go-defi/v2/models"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
 )
 
 var (
-	api         = "https://api.compound.finance/api/v2"
 	account     = "0xe18999d3f7e1a84e35bf9c15699b79e46eb44704"
 	selfAccount = "0xcd0fBe49Ac5e009858DFd1c5F7330907C710fe96"
 )
 
 func Test_Client(t *testing.T) {
-	client := NewClient(api)
+	srv := mockapi.New()
+	defer srv.Close()
+	client := NewClient(srv.URL)
 	if _, err := client.GetAccount(account); err != nil {
 		t.Fatal(err)
 	}
@@ -32,11 +35,31 @@ func Test_Client(t *testing.T) {
 	} else if len(accts) == 0 {
 		t.Fatal("no accounts at risk of liquidation")
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+
+	defer func(interval time.Duration) { watchInterval = interval }(watchInterval)
+	watchInterval = time.Millisecond * 10
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
-	riskChan := make(chan float64, 1)
-	warnChan := make(chan float64, 1)
-	if err := client.WatchHealth(ctx, account, riskChan, warnChan); err != nil {
+	riskChan := make(chan models.Decimal, 1)
+	warnChan := make(chan models.Decimal, 1)
+	srv.SetHealth(account, models.MustParseDecimal("0.95"))
+	errChan := make(chan error, 1)
+	go func() { errChan <- client.WatchHealth(ctx, account, riskChan, warnChan) }()
+	select {
+	case health := <-riskChan:
+		assert.Equal(t, "0.95", health.String())
+	case <-ctx.Done():
+		t.Fatal("no risk signal")
+	}
+	srv.SetHealth(account, models.MustParseDecimal("1.1"))
+	select {
+	case health := <-warnChan:
+		assert.Equal(t, "1.1", health.String())
+	case <-ctx.Done():
+		t.Fatal("no warn signal")
+	}
+	cancel()
+	if err := <-errChan; err != nil {
 		t.Fatal(err)
 	}
 }
@@ -68,7 +91,9 @@ func Test_CompoundAddresses(t *testing.T) {
 }
 
 func Test_SelfAccount(t *testing.T) {
-	client := NewClient(api)
+	srv := mockapi.New()
+	defer srv.Close()
+	client := NewClient(srv.URL)
 	if _, err := client.GetAccount(selfAccount); err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,401 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	cerc20 "github.com/musinit/go-defi/v2/bindings/cbat"
+	ceth "github.com/musinit/go-defi/v2/bindings/ceth"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// ErrEtherMaxRepay is returned when repaying MaxRepay on the ether market,
+// whose repay amount is the value sent with the transaction
+var ErrEtherMaxRepay = errors.New("repaying ether needs an exact amount, not MaxRepay")
+
+// CToken is used to interact with a compound market, whether it lends an
+// erc20 token or ether. Amounts are in units of the underlying token
+type CToken interface {
+	// Address returns the address of the ctoken contract
+	Address() Address
+	// Underlying returns the address of the underlying token, the zero
+	// address for the ether market
+	Underlying(opts *bind.CallOpts) (common.Address, error)
+	ExchangeRateStored(opts *bind.CallOpts) (*big.Int, error)
+	BorrowRatePerBlock(opts *bind.CallOpts) (*big.Int, error)
+	SupplyRatePerBlock(opts *bind.CallOpts) (*big.Int, error)
+	BalanceOf(opts *bind.CallOpts, owner common.Address) (*big.Int, error)
+	// GetAccountSnapshot returns the error code, ctoken balance, borrow
+	// balance and exchange rate mantissa of account
+	GetAccountSnapshot(opts *bind.CallOpts, account common.Address) (*big.Int, *big.Int, *big.Int, *big.Int, error)
+
+	Mint(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error)
+	Borrow(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error)
+	RepayBorrow(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error)
+	RepayBorrowBehalf(auth *bind.TransactOpts, borrower common.Address, amount *big.Int) (*types.Transaction, error)
+	// Redeem takes an amount of ctokens, unlike RedeemUnderlying
+	Redeem(auth *bind.TransactOpts, tokens *big.Int) (*types.Transaction, error)
+	RedeemUnderlying(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error)
+	LiquidateBorrow(auth *bind.TransactOpts, borrower common.Address, amount *big.Int, collateral common.Address) (*types.Transaction, error)
+}
+
+// CErc20 is a CToken lending an erc20 token
+type CErc20 struct {
+	address  Address
+	contract *cerc20.Bindings
+}
+
+// NewCErc20 returns the erc20 market at address, through backend
+func NewCErc20(address Address, backend bind.ContractBackend) (*CErc20, error) {
+	contract, err := cerc20.NewBindings(address.EthAddress(), backend)
+	if err != nil {
+		return nil, err
+	}
+	return &CErc20{address: address, contract: contract}, nil
+}
+
+// Address returns the address of the ctoken contract
+func (c *CErc20) Address() Address { return c.address }
+
+// Underlying returns the address of the lent erc20 token
+func (c *CErc20) Underlying(opts *bind.CallOpts) (common.Address, error) {
+	return c.contract.Underlying(opts)
+}
+
+// ExchangeRateStored returns the stored exchange rate of ctokens to the underlying token
+func (c *CErc20) ExchangeRateStored(opts *bind.CallOpts) (*big.Int, error) {
+	return c.contract.ExchangeRateStored(opts)
+}
+
+// BorrowRatePerBlock returns the current borrow interest rate per block
+func (c *CErc20) BorrowRatePerBlock(opts *bind.CallOpts) (*big.Int, error) {
+	return c.contract.BorrowRatePerBlock(opts)
+}
+
+// SupplyRatePerBlock returns the current supply interest rate per block
+func (c *CErc20) SupplyRatePerBlock(opts *bind.CallOpts) (*big.Int, error) {
+	return c.contract.SupplyRatePerBlock(opts)
+}
+
+// BalanceOf returns the ctoken balance of owner
+func (c *CErc20) BalanceOf(opts *bind.CallOpts, owner common.Address) (*big.Int, error) {
+	return c.contract.BalanceOf(opts, owner)
+}
+
+// GetAccountSnapshot returns the error code, ctoken balance, borrow balance
+// and exchange rate mantissa of account
+func (c *CErc20) GetAccountSnapshot(opts *bind.CallOpts, account common.Address) (*big.Int, *big.Int, *big.Int, *big.Int, error) {
+	return c.contract.GetAccountSnapshot(opts, account)
+}
+
+// Mint supplies amount of the underlying token
+func (c *CErc20) Mint(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+	return c.contract.Mint(auth, amount)
+}
+
+// Borrow borrows amount of the underlying token
+func (c *CErc20) Borrow(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+	return c.contract.Borrow(auth, amount)
+}
+
+// RepayBorrow repays amount of the borrowed underlying token
+func (c *CErc20) RepayBorrow(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+	return c.contract.RepayBorrow(auth, amount)
+}
+
+// RepayBorrowBehalf repays amount of the borrowed underlying token of borrower
+func (c *CErc20) RepayBorrowBehalf(auth *bind.TransactOpts, borrower common.Address, amount *big.Int) (*types.Transaction, error) {
+	return c.contract.RepayBorrowBehalf(auth, borrower, amount)
+}
+
+// Redeem exchanges tokens ctokens back to the underlying token
+func (c *CErc20) Redeem(auth *bind.TransactOpts, tokens *big.Int) (*types.Transaction, error) {
+	return c.contract.Redeem(auth, tokens)
+}
+
+// RedeemUnderlying withdraws amount of the supplied underlying token
+func (c *CErc20) RedeemUnderlying(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+	return c.contract.RedeemUnderlying(auth, amount)
+}
+
+// LiquidateBorrow repays amount of the borrow of borrower, seizing the collateral ctoken
+func (c *CErc20) LiquidateBorrow(auth *bind.TransactOpts, borrower common.Address, amount *big.Int, collateral common.Address) (*types.Transaction, error) {
+	return c.contract.LiquidateBorrow(auth, borrower, amount, collateral)
+}
+
+// CEther is the CToken lending ether
+type CEther struct {
+	address  Address
+	contract *ceth.Bindings
+}
+
+// NewCEther returns the ether market at address, through backend
+func NewCEther(address Address, backend bind.ContractBackend) (*CEther, error) {
+	contract, err := ceth.NewBindings(address.EthAddress(), backend)
+	if err != nil {
+		return nil, err
+	}
+	return &CEther{address: address, contract: contract}, nil
+}
+
+// Address returns the address of the ctoken contract
+func (c *CEther) Address() Address { return c.address }
+
+// Underlying returns the zero address, ether is not a token
+func (c *CEther) Underlying(opts *bind.CallOpts) (common.Address, error) {
+	return common.Address{}, nil
+}
+
+// ExchangeRateStored returns the stored exchange rate of ctokens to ether
+func (c *CEther) ExchangeRateStored(opts *bind.CallOpts) (*big.Int, error) {
+	return c.contract.ExchangeRateStored(opts)
+}
+
+// BorrowRatePerBlock returns the current borrow interest rate per block
+func (c *CEther) BorrowRatePerBlock(opts *bind.CallOpts) (*big.Int, error) {
+	return c.contract.BorrowRatePerBlock(opts)
+}
+
+// SupplyRatePerBlock returns the current supply interest rate per block
+func (c *CEther) SupplyRatePerBlock(opts *bind.CallOpts) (*big.Int, error) {
+	return c.contract.SupplyRatePerBlock(opts)
+}
+
+// BalanceOf returns the ctoken balance of owner
+func (c *CEther) BalanceOf(opts *bind.CallOpts, owner common.Address) (*big.Int, error) {
+	return c.contract.BalanceOf(opts, owner)
+}
+
+// GetAccountSnapshot returns the error code, ctoken balance, borrow balance
+// and exchange rate mantissa of account
+func (c *CEther) GetAccountSnapshot(opts *bind.CallOpts, account common.Address) (*big.Int, *big.Int, *big.Int, *big.Int, error) {
+	return c.contract.GetAccountSnapshot(opts, account)
+}
+
+// Mint supplies amount of ether, sent as the value of the transaction
+func (c *CEther) Mint(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+	return c.contract.Mint(withValue(auth, amount))
+}
+
+// Borrow borrows amount of ether
+func (c *CEther) Borrow(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+	return c.contract.Borrow(auth, amount)
+}
+
+// RepayBorrow repays amount of the borrowed ether, sent as the value of the
+// transaction
+func (c *CEther) RepayBorrow(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+	if amount.Cmp(MaxRepay) == 0 {
+		return nil, ErrEtherMaxRepay
+	}
+	return c.contract.RepayBorrow(withValue(auth, amount))
+}
+
+// RepayBorrowBehalf repays amount of the borrowed ether of borrower, sent as
+// the value of the transaction
+func (c *CEther) RepayBorrowBehalf(auth *bind.TransactOpts, borrower common.Address, amount *big.Int) (*types.Transaction, error) {
+	if amount.Cmp(MaxRepay) == 0 {
+		return nil, ErrEtherMaxRepay
+	}
+	return c.contract.RepayBorrowBehalf(withValue(auth, amount), borrower)
+}
+
+// Redeem exchanges tokens ctokens back to ether
+func (c *CEther) Redeem(auth *bind.TransactOpts, tokens *big.Int) (*types.Transaction, error) {
+	return c.contract.Redeem(auth, tokens)
+}
+
+// RedeemUnderlying withdraws amount of the supplied ether
+func (c *CEther) RedeemUnderlying(auth *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+	return c.contract.RedeemUnderlying(auth, amount)
+}
+
+// LiquidateBorrow repays amount of the borrowed ether of borrower, sent as
+// the value of the transaction, seizing the collateral ctoken
+func (c *CEther) LiquidateBorrow(auth *bind.TransactOpts, borrower common.Address, amount *big.Int, collateral common.Address) (*types.Transaction, error) {
+	return c.contract.LiquidateBorrow(withValue(auth, amount), borrower, collateral)
+}
+
+// withValue returns a copy of auth sending value with the transaction, the
+// shared auth of a client is never changed
+func withValue(auth *bind.TransactOpts, value *big.Int) *bind.TransactOpts {
+	opts := *auth
+	opts.Value = new(big.Int).Set(value)
+	return &opts
+}
+
+// Market is a compound market listed on a chain. The static markets only
+// set the symbol, address and chain, the discovered ones every field
+type Market struct {
+	Symbol  string
+	Address Address
+	ChainID int64
+	// Ether is whether the market lends ether instead of an erc20 token
+	Ether bool
+	// Underlying is empty for the ether market
+	Underlying         Address
+	Decimals           uint8
+	UnderlyingDecimals uint8
+	// Listed is whether the comptroller still lists the market
+	Listed           bool
+	CollateralFactor models.Decimal
+}
+
+// NewCToken returns the CToken of market, through backend
+func NewCToken(market Market, backend bind.ContractBackend) (CToken, error) {
+	if market.Ether {
+		return NewCEther(market.Address, backend)
+	}
+	return NewCErc20(market.Address, backend)
+}
+
+var (
+	// MainnetMarkets are the compound v2 markets on the ethereum mainnet
+	MainnetMarkets = []Market{
+		{Symbol: "cBAT", Address: CompoundBAT, ChainID: 1},
+		{Symbol: "cDAI", Address: CompoundDAI, ChainID: 1},
+		{Symbol: "cSAI", Address: CompoundSAI, ChainID: 1},
+		{Symbol: "cETH", Address: CompoundETH, ChainID: 1, Ether: true},
+		{Symbol: "cREP", Address: CompoundREP, ChainID: 1},
+		{Symbol: "cUSDC", Address: CompoundUSDC, ChainID: 1, Underlying: USDC},
+		{Symbol: "cUSDT", Address: CompoundUSDT, ChainID: 1},
+		{Symbol: "cWBTC", Address: CompoundWBTC, ChainID: 1},
+		{Symbol: "cZRX", Address: CompoundZRX, ChainID: 1},
+	}
+	// GoerliMarkets are the compound v2 markets on the goerli testnet
+	GoerliMarkets = []Market{
+		{Symbol: "cDAI", Address: CompoundDAI_goerli, ChainID: 5},
+		{Symbol: "cETH", Address: CompoundETH_goerli, ChainID: 5, Ether: true},
+		{Symbol: "cUSDC", Address: CompoundUSDC_goerli, ChainID: 5, Underlying: USDC_goerli},
+	}
+)
+
+// ErrAmbiguousMarket is returned when a lookup matches markets on several chains
+var ErrAmbiguousMarket = errors.New("market is ambiguous")
+
+// MarketRegistry is used to look up markets by the address of their ctoken,
+// their symbol or their underlying token
+type MarketRegistry struct {
+	mu      sync.RWMutex
+	markets map[common.Address]Market
+}
+
+// NewMarketRegistry returns a registry of markets
+func NewMarketRegistry(markets ...Market) *MarketRegistry {
+	r := &MarketRegistry{markets: make(map[common.Address]Market)}
+	r.Register(markets...)
+	return r
+}
+
+// NewDefaultMarketRegistry returns a registry of the mainnet and goerli markets
+func NewDefaultMarketRegistry() *MarketRegistry {
+	return NewMarketRegistry(append(append([]Market{}, MainnetMarkets...), GoerliMarkets...)...)
+}
+
+// Register adds markets to the registry, replacing those at the same address
+func (r *MarketRegistry) Register(markets ...Market) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, market := range markets {
+		r.markets[market.Address.EthAddress()] = market
+	}
+}
+
+// Market returns the market at address, or ErrMarketNotFound
+func (r *MarketRegistry) Market(address Address) (Market, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	market, ok := r.markets[address.EthAddress()]
+	if !ok {
+		return Market{}, ErrMarketNotFound
+	}
+	return market, nil
+}
+
+// BySymbol returns the market with the given symbol, matched case
+// insensitively, or ErrAmbiguousMarket if several chains list it
+func (r *MarketRegistry) BySymbol(symbol string) (Market, error) {
+	return r.find(symbol, func(m Market) bool { return strings.EqualFold(m.Symbol, symbol) })
+}
+
+// ByUnderlying returns the market lending the given token
+func (r *MarketRegistry) ByUnderlying(token Address) (Market, error) {
+	address := token.EthAddress()
+	return r.find(token.String(), func(m Market) bool {
+		return m.Underlying != "" && m.Underlying.EthAddress() == address
+	})
+}
+
+// Lookup returns the market whose ctoken address, underlying token address
+// or symbol is query
+func (r *MarketRegistry) Lookup(query string) (Market, error) {
+	if !common.IsHexAddress(query) {
+		return r.BySymbol(query)
+	}
+	if market, err := r.Market(Address(query)); err == nil {
+		return market, nil
+	}
+	return r.ByUnderlying(Address(query))
+}
+
+// find returns the single market matching match
+func (r *MarketRegistry) find(query string, match func(Market) bool) (Market, error) {
+	var found []Market
+	for _, market := range r.Markets() {
+		if match(market) {
+			found = append(found, market)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Market{}, fmt.Errorf("%s: %w", query, ErrMarketNotFound)
+	case 1:
+		return found[0], nil
+	default:
+		return Market{}, fmt.Errorf("%s is listed on %d chains: %w", query, len(found), ErrAmbiguousMarket)
+	}
+}
+
+// OnChain returns a registry of the markets on the given chain
+func (r *MarketRegistry) OnChain(chainID int64) *MarketRegistry {
+	var markets []Market
+	for _, market := range r.Markets() {
+		if market.ChainID == chainID {
+			markets = append(markets, market)
+		}
+	}
+	return NewMarketRegistry(markets...)
+}
+
+// Markets returns every registered market, ordered by chain and symbol
+func (r *MarketRegistry) Markets() []Market {
+	r.mu.RLock()
+	markets := make([]Market, 0, len(r.markets))
+	for _, market := range r.markets {
+		markets = append(markets, market)
+	}
+	r.mu.RUnlock()
+	sort.Slice(markets, func(i, j int) bool {
+		if markets[i].ChainID != markets[j].ChainID {
+			return markets[i].ChainID < markets[j].ChainID
+		}
+		return markets[i].Symbol < markets[j].Symbol
+	})
+	return markets
+}
+
+// CToken returns the CToken of the market at address, through backend
+func (r *MarketRegistry) CToken(address Address, backend bind.ContractBackend) (CToken, error) {
+	market, err := r.Market(address)
+	if err != nil {
+		return nil, err
+	}
+	return NewCToken(market, backend)
+}
@@ -0,0 +1,312 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	cerc20 "github.com/musinit/go-defi/v2/bindings/cbat"
+	ceth "github.com/musinit/go-defi/v2/bindings/ceth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contractNode is the eth namespace of a node answering eth_calls with the
+// outputs set for the called contract and method, or the handler of the
+// method, and log queries with the logs of the requested blocks
+type contractNode struct {
+	abis     []abi.ABI
+	outputs  map[common.Address]map[string][]interface{}
+	handlers map[string]func(to common.Address, in []interface{}) []interface{}
+	logs     []types.Log
+	head     uint64
+	// receipts returns the logs of the receipt of a sent transaction
+	receipts func(tx *types.Transaction) []*types.Log
+
+	mu         sync.Mutex
+	logQueries int
+	sent       []*types.Transaction
+}
+
+// newContractNode returns a node answering calls of the methods of the
+// given abis, the first abi with a method packs its outputs
+func newContractNode(t *testing.T, outputs map[common.Address]map[string][]interface{}, abis ...string) *contractNode {
+	n := &contractNode{outputs: outputs, handlers: make(map[string]func(common.Address, []interface{}) []interface{})}
+	for _, abiJSON := range abis {
+		parsed, err := abi.JSON(strings.NewReader(abiJSON))
+		require.NoError(t, err)
+		n.abis = append(n.abis, parsed)
+	}
+	return n
+}
+
+func (n *contractNode) Call(args callArgs, block string) (hexutil.Bytes, error) {
+	for _, parsed := range n.abis {
+		method, err := parsed.MethodById(args.Data[:4])
+		if err != nil {
+			continue
+		}
+		if handler, ok := n.handlers[method.Name]; ok {
+			in, err := method.Inputs.Unpack(args.Data[4:])
+			if err != nil {
+				return nil, err
+			}
+			return method.Outputs.Pack(handler(args.To, in)...)
+		}
+		out, ok := n.outputs[args.To][method.Name]
+		if !ok {
+			return nil, fmt.Errorf("unexpected call of %s on %s", method.Name, args.To)
+		}
+		return method.Outputs.Pack(out...)
+	}
+	return nil, fmt.Errorf("unknown method %x", args.Data[:4])
+}
+
+type logQuery struct {
+	FromBlock string `json:"fromBlock"`
+	ToBlock   string `json:"toBlock"`
+}
+
+func (n *contractNode) GetLogs(query logQuery) ([]types.Log, error) {
+	n.mu.Lock()
+	n.logQueries++
+	n.mu.Unlock()
+	from, err := hexutil.DecodeUint64(query.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to := n.head
+	if query.ToBlock != "" && query.ToBlock != "latest" {
+		if to, err = hexutil.DecodeUint64(query.ToBlock); err != nil {
+			return nil, err
+		}
+	}
+	logs := []types.Log{}
+	for _, log := range n.logs {
+		if log.BlockNumber >= from && log.BlockNumber <= to {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (n *contractNode) GetBlockByNumber(number string, full bool) (*types.Header, error) {
+	return &types.Header{Number: new(big.Int).SetUint64(n.head), Difficulty: new(big.Int)}, nil
+}
+
+func (n *contractNode) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(n.head)
+}
+
+func (n *contractNode) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1337))
+}
+
+// SendRawTransaction records the transaction, which is mined at once
+func (n *contractNode) SendRawTransaction(raw hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return common.Hash{}, err
+	}
+	n.mu.Lock()
+	n.sent = append(n.sent, tx)
+	n.mu.Unlock()
+	return tx.Hash(), nil
+}
+
+// GetTransactionReceipt returns a successful receipt of a sent transaction
+func (n *contractNode) GetTransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, tx := range n.sent {
+		if tx.Hash() != hash {
+			continue
+		}
+		rcpt := &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: hash, Logs: []*types.Log{}}
+		if n.receipts != nil {
+			rcpt.Logs = append(rcpt.Logs, n.receipts(tx)...)
+		}
+		rcpt.Bloom = types.CreateBloom(types.Receipts{rcpt})
+		return rcpt, nil
+	}
+	return nil, nil
+}
+
+// transactions returns the transactions sent so far
+func (n *contractNode) transactions() []*types.Transaction {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]*types.Transaction(nil), n.sent...)
+}
+
+// testAuth returns transact options that sign nothing, and set every field
+// that would otherwise be read from the node
+func testAuth() *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:     common.HexToAddress("0xa1"),
+		Nonce:    big.NewInt(1),
+		GasPrice: big.NewInt(1),
+		GasLimit: 100000,
+		Signer: func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	}
+}
+
+// dial serves the node, and returns a client of it
+func (n *contractNode) dial(t *testing.T) *ethclient.Client {
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("eth", n))
+	httpSrv := httptest.NewServer(srv)
+	t.Cleanup(httpSrv.Close)
+	rpcClient, err := rpc.Dial(httpSrv.URL)
+	require.NoError(t, err)
+	t.Cleanup(rpcClient.Close)
+	return ethclient.NewClient(rpcClient)
+}
+
+func Test_MarketRegistry(t *testing.T) {
+	r := NewDefaultMarketRegistry()
+	market, err := r.Market(Address(strings.ToUpper(CompoundUSDT.String()[2:])))
+	require.NoError(t, err)
+	assert.Equal(t, "cUSDT", market.Symbol)
+	market, err = r.Market(CompoundETH_goerli)
+	require.NoError(t, err)
+	assert.True(t, market.Ether)
+	assert.Equal(t, int64(5), market.ChainID)
+
+	_, err = r.Market(AaveUSDTv3)
+	assert.True(t, errors.Is(err, ErrMarketNotFound))
+	r.Register(Market{Symbol: "aUSDT", Address: AaveUSDTv3, ChainID: 137})
+	_, err = r.Market(AaveUSDTv3)
+	assert.NoError(t, err)
+
+	_, err = r.BySymbol("cdai")
+	assert.True(t, errors.Is(err, ErrAmbiguousMarket))
+	market, err = r.OnChain(5).BySymbol("cdai")
+	require.NoError(t, err)
+	assert.Equal(t, CompoundDAI_goerli, market.Address)
+	market, err = r.Lookup(USDC.String())
+	require.NoError(t, err)
+	assert.Equal(t, CompoundUSDC, market.Address)
+	market, err = r.Lookup(CompoundZRX.String())
+	require.NoError(t, err)
+	assert.Equal(t, "cZRX", market.Symbol)
+	market, err = r.Lookup("cusdt")
+	require.NoError(t, err)
+	assert.Equal(t, CompoundUSDT, market.Address)
+	_, err = r.Lookup("cFOO")
+	assert.True(t, errors.Is(err, ErrMarketNotFound))
+
+	markets := r.Markets()
+	assert.Len(t, markets, len(MainnetMarkets)+len(GoerliMarkets)+1)
+	assert.Equal(t, "cBAT", markets[0].Symbol)
+	assert.Equal(t, "aUSDT", markets[len(markets)-1].Symbol)
+
+	ctoken, err := NewCToken(Market{Address: CompoundETH, Ether: true}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &CEther{}, ctoken)
+	ctoken, err = NewCToken(Market{Address: CompoundUSDT}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &CErc20{}, ctoken)
+	assert.Equal(t, CompoundUSDT, ctoken.Address())
+}
+
+func Test_BClient_Markets(t *testing.T) {
+	eth := newContractNode(t, map[common.Address]map[string][]interface{}{
+		CompoundUSDT.EthAddress(): {
+			"exchangeRateStored": {big.NewInt(200)},
+			"borrowRatePerBlock": {big.NewInt(7)},
+			"supplyRatePerBlock": {big.NewInt(3)},
+		},
+		CompoundDAI_goerli.EthAddress(): {
+			"exchangeRateStored": {big.NewInt(300)},
+		},
+	}, cerc20.BindingsABI).dial(t)
+	bc := NewBClient(nil, eth)
+	ctx := context.Background()
+
+	price, err := bc.GetPrice(ctx, CompoundUSDT)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), price.Int64())
+	rate, err := bc.GetBorrowRate(ctx, CompoundUSDT)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), rate.Int64())
+	rate, err = bc.SupplyRatePerBlock(ctx, CompoundUSDT)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), rate.Int64())
+	price, err = bc.GetPrice(ctx, CompoundDAI_goerli)
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), price.Int64())
+	decimals, err := bc.UnderlyingDecimals(ctx, CompoundETH_goerli)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(18), decimals)
+
+	// unknown markets fail before anything is sent
+	unknown := Address("0x0000000000000000000000000000000000000001")
+	_, err = bc.GetPrice(ctx, unknown)
+	assert.True(t, errors.Is(err, ErrMarketNotFound))
+	assert.True(t, errors.Is(bc.Borrow(ctx, unknown, big.NewInt(1)), ErrMarketNotFound))
+	assert.True(t, errors.Is(bc.GetLiqd(ctx, unknown, LiquidateOpts{}), ErrMarketNotFound))
+	assert.Equal(t, ErrEtherMaxRepay, bc.RepayBorrow(ctx, CompoundETH, MaxRepay))
+
+	bc = NewBClient(nil, eth, WithMarkets(NewMarketRegistry(Market{Symbol: "cX", Address: unknown})))
+	_, err = bc.GetPrice(ctx, CompoundUSDT)
+	assert.True(t, errors.Is(err, ErrMarketNotFound))
+}
+
+func Test_CEther_Payable(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(ceth.BindingsABI))
+	require.NoError(t, err)
+	// transactions are built and signed, but never sent
+	auth := testAuth()
+	auth.NoSend = true
+	ctoken, err := NewCEther(CompoundETH, nil)
+	require.NoError(t, err)
+	borrower, collateral := common.HexToAddress("0xb1"), common.HexToAddress("0xc1")
+
+	for _, tt := range []struct {
+		method string
+		send   func(amount *big.Int) (*types.Transaction, error)
+		args   []interface{}
+	}{
+		{"mint", func(amount *big.Int) (*types.Transaction, error) { return ctoken.Mint(auth, amount) }, nil},
+		{"repayBorrow", func(amount *big.Int) (*types.Transaction, error) { return ctoken.RepayBorrow(auth, amount) }, nil},
+		{"repayBorrowBehalf", func(amount *big.Int) (*types.Transaction, error) {
+			return ctoken.RepayBorrowBehalf(auth, borrower, amount)
+		}, []interface{}{borrower}},
+		{"liquidateBorrow", func(amount *big.Int) (*types.Transaction, error) {
+			return ctoken.LiquidateBorrow(auth, borrower, amount, collateral)
+		}, []interface{}{borrower, collateral}},
+	} {
+		amount := big.NewInt(1e18)
+		tx, err := tt.send(amount)
+		require.NoError(t, err, tt.method)
+		assert.Equal(t, amount, tx.Value(), tt.method)
+		data, err := parsed.Pack(tt.method, tt.args...)
+		require.NoError(t, err)
+		assert.Equal(t, data, tx.Data(), tt.method)
+		// the value is copied, and the shared auth is left as is
+		amount.SetInt64(1)
+		assert.Equal(t, big.NewInt(1e18), tx.Value(), tt.method)
+		assert.Nil(t, auth.Value, tt.method)
+	}
+
+	_, err = ctoken.RepayBorrow(auth, MaxRepay)
+	assert.Equal(t, ErrEtherMaxRepay, err)
+	tx, err := ctoken.Borrow(auth, big.NewInt(5))
+	require.NoError(t, err)
+	assert.Zero(t, tx.Value().Sign())
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	cerc20 "github.com/musinit/go-defi/v2/bindings/cbat"
+	comptroller "github.com/musinit/go-defi/v2/bindings/comptroller"
+	erc20 "github.com/musinit/go-defi/v2/bindings/usdc"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// Deployment is a compound v2 deployment, whose comptroller lists its markets
+type Deployment struct {
+	Name    string
+	ChainID int64
+	// Comptroller is the address markets are listed by, the unitroller proxy
+	Comptroller Address
+	// FromBlock is the block the comptroller was deployed at, listings are
+	// replayed from it
+	FromBlock uint64
+	// Ether is the address of the ether market, which has no underlying token
+	Ether Address
+}
+
+var (
+	// CompoundMainnet is the compound v2 deployment on the ethereum mainnet
+	CompoundMainnet = Deployment{Name: "mainnet", ChainID: 1, Comptroller: Unitroller, FromBlock: 7710671, Ether: CompoundETH}
+	// CompoundGoerli is the compound v2 deployment on the goerli testnet
+	CompoundGoerli = Deployment{Name: "goerli", ChainID: 5, Comptroller: Comptroller_goerli, Ether: CompoundETH_goerli}
+
+	// Deployments is a map containing the name, and deployment of all compound deployments
+	Deployments = map[string]Deployment{
+		CompoundMainnet.Name: CompoundMainnet,
+		CompoundGoerli.Name:  CompoundGoerli,
+	}
+)
+
+// DiscoverOption is used to configure market discovery
+type DiscoverOption func(*discovery)
+
+type discovery struct {
+	logRange uint64
+}
+
+// WithLogRange replays listings in windows of at most blocks blocks, for
+// nodes limiting the range of log queries. By default a single query is made
+func WithLogRange(blocks uint64) DiscoverOption {
+	return func(d *discovery) {
+		d.logRange = blocks
+	}
+}
+
+// DiscoverMarkets returns every market the comptroller of the deployment
+// ever listed, in listing order, by replaying its MarketListed events and
+// reading each ctoken and its listing
+func DiscoverMarkets(ctx context.Context, backend bind.ContractBackend, d Deployment, opts ...DiscoverOption) ([]Market, error) {
+	cfg := &discovery{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	contract, err := comptroller.NewBindings(d.Comptroller.EthAddress(), backend)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := listedMarkets(ctx, backend, contract, d.FromBlock, cfg.logRange)
+	if err != nil {
+		return nil, err
+	}
+	markets := make([]Market, 0, len(addresses))
+	for _, address := range addresses {
+		market, err := discoverMarket(&bind.CallOpts{Context: ctx}, backend, contract, address, d.Ether.EthAddress())
+		if err != nil {
+			return nil, err
+		}
+		market.ChainID = d.ChainID
+		markets = append(markets, market)
+	}
+	return markets, nil
+}
+
+// listedMarkets replays the MarketListed events of the comptroller from the
+// given block, returning each listed address once
+func listedMarkets(ctx context.Context, backend bind.ContractBackend, contract *comptroller.Bindings, from, window uint64) ([]common.Address, error) {
+	var head uint64
+	if window > 0 {
+		header, err := backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		head = header.Number.Uint64()
+	}
+	var (
+		addresses []common.Address
+		seen      = make(map[common.Address]bool)
+	)
+	for start := from; ; start += window {
+		opts := &bind.FilterOpts{Start: start, Context: ctx}
+		if window > 0 {
+			end := start + window - 1
+			opts.End = &end
+		}
+		it, err := contract.FilterMarketListed(opts)
+		if err != nil {
+			return nil, err
+		}
+		for it.Next() {
+			if !seen[it.Event.CToken] {
+				seen[it.Event.CToken] = true
+				addresses = append(addresses, it.Event.CToken)
+			}
+		}
+		err = it.Error()
+		it.Close()
+		if err != nil {
+			return nil, err
+		}
+		if window == 0 || start+window > head {
+			return addresses, nil
+		}
+	}
+}
+
+// discoverMarket reads the details of the ctoken at address and its listing,
+// the market at ether is the ether market
+func discoverMarket(opts *bind.CallOpts, backend bind.ContractBackend, contract *comptroller.Bindings, address, ether common.Address) (Market, error) {
+	market := Market{Address: Address(address.Hex())}
+	ctoken, err := cerc20.NewBindings(address, backend)
+	if err != nil {
+		return market, err
+	}
+	if market.Symbol, err = ctoken.Symbol(opts); err != nil {
+		return market, fmt.Errorf("%s symbol: %w", address.Hex(), err)
+	}
+	decimals, err := ctoken.Decimals(opts)
+	if err != nil {
+		return market, fmt.Errorf("%s decimals: %w", market.Symbol, err)
+	}
+	market.Decimals = uint8(decimals.Uint64())
+	if address == ether {
+		market.Ether, market.UnderlyingDecimals = true, 18
+	} else {
+		underlying, err := ctoken.Underlying(opts)
+		if err != nil {
+			return market, fmt.Errorf("%s underlying: %w", market.Symbol, err)
+		}
+		token, err := erc20.NewBindings(underlying, backend)
+		if err != nil {
+			return market, err
+		}
+		market.Underlying = Address(underlying.Hex())
+		if market.UnderlyingDecimals, err = token.Decimals(opts); err != nil {
+			return market, fmt.Errorf("%s underlying decimals: %w", market.Symbol, err)
+		}
+	}
+	listing, err := contract.Markets(opts, address)
+	if err != nil {
+		return market, fmt.Errorf("%s listing: %w", market.Symbol, err)
+	}
+	market.Listed = listing.IsListed
+	if listing.CollateralFactorMantissa == nil {
+		listing.CollateralFactorMantissa = new(big.Int)
+	}
+	market.CollateralFactor = models.NewDecimalFromInt(listing.CollateralFactorMantissa, 18)
+	return market, nil
+}
+
+// DiscoverMarkets discovers the markets of the deployment, and registers
+// them in the registry of the client
+func (bc *BClient) DiscoverMarkets(ctx context.Context, d Deployment, opts ...DiscoverOption) ([]Market, error) {
+	markets, err := DiscoverMarkets(ctx, bc.client, d, opts...)
+	if err != nil {
+		return nil, err
+	}
+	bc.markets.Register(markets...)
+	return markets, nil
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	cerc20 "github.com/musinit/go-defi/v2/bindings/cbat"
+	comptroller "github.com/musinit/go-defi/v2/bindings/comptroller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiscoverMarkets(t *testing.T) {
+	var (
+		comptrollerAddress = common.HexToAddress("0xc0")
+		cETH               = common.HexToAddress("0xc1")
+		cUSDT              = common.HexToAddress("0xc2")
+		usdt               = common.HexToAddress("0xd2")
+		mantissa           = func(m string) *big.Int { v, _ := new(big.Int).SetString(m, 10); return v }
+	)
+	node := newContractNode(t, map[common.Address]map[string][]interface{}{
+		cETH: {
+			"symbol":   {"cETH"},
+			"decimals": {big.NewInt(8)},
+		},
+		cUSDT: {
+			"symbol":     {"cUSDT"},
+			"decimals":   {big.NewInt(8)},
+			"underlying": {usdt},
+		},
+		usdt: {
+			"decimals": {big.NewInt(6)},
+		},
+	}, cerc20.BindingsABI, comptroller.BindingsABI)
+	node.handlers["markets"] = func(to common.Address, in []interface{}) []interface{} {
+		if in[0].(common.Address) == cETH {
+			return []interface{}{true, mantissa("750000000000000000")}
+		}
+		return []interface{}{false, new(big.Int)}
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(comptroller.BindingsABI))
+	require.NoError(t, err)
+	listed := parsed.Events["MarketListed"]
+	for i, listing := range []struct {
+		block  uint64
+		ctoken common.Address
+	}{{10, cETH}, {60, cUSDT}, {120, cETH}} {
+		data, err := listed.Inputs.NonIndexed().Pack(listing.ctoken)
+		require.NoError(t, err)
+		node.logs = append(node.logs, types.Log{
+			Address:     comptrollerAddress,
+			Topics:      []common.Hash{listed.ID},
+			Data:        data,
+			BlockNumber: listing.block,
+			Index:       uint(i),
+		})
+	}
+	node.head = 150
+	eth := node.dial(t)
+	ctx := context.Background()
+	d := Deployment{Name: "test", ChainID: 1337, Comptroller: Address(comptrollerAddress.Hex()), FromBlock: 5, Ether: Address(cETH.Hex())}
+
+	markets, err := DiscoverMarkets(ctx, eth, d, WithLogRange(50))
+	require.NoError(t, err)
+	assert.Equal(t, 3, node.logQueries)
+	require.Len(t, markets, 2)
+	assert.Equal(t, "cETH", markets[0].Symbol)
+	assert.True(t, markets[0].Ether)
+	assert.True(t, markets[0].Listed)
+	assert.Equal(t, "0.75", markets[0].CollateralFactor.String())
+	assert.Equal(t, uint8(18), markets[0].UnderlyingDecimals)
+	assert.Equal(t, "cUSDT", markets[1].Symbol)
+	assert.Equal(t, Address(usdt.Hex()), markets[1].Underlying)
+	assert.Equal(t, uint8(8), markets[1].Decimals)
+	assert.Equal(t, uint8(6), markets[1].UnderlyingDecimals)
+	assert.False(t, markets[1].Listed)
+	assert.Equal(t, int64(1337), markets[1].ChainID)
+
+	// discovered markets are looked up and used by the client
+	bc := NewBClient(nil, eth, WithMarkets(NewMarketRegistry()))
+	_, err = bc.DiscoverMarkets(ctx, d)
+	require.NoError(t, err)
+	assert.Equal(t, 4, node.logQueries)
+	market, err := bc.Markets().Lookup(usdt.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, Address(cUSDT.Hex()), market.Address)
+	market, err = bc.Markets().Lookup("ceth")
+	require.NoError(t, err)
+	assert.Equal(t, Address(cETH.Hex()), market.Address)
+	decimals, err := bc.UnderlyingDecimals(ctx, Address(strings.ToLower(cUSDT.Hex())))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(6), decimals)
+
+	delete(node.outputs[cUSDT], "underlying")
+	_, err = DiscoverMarkets(ctx, eth, d)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cUSDT underlying")
+	assert.False(t, errors.Is(err, ErrMarketNotFound))
+
+	// ether is identified by address, a failing call is not taken for it
+	d.Ether = ""
+	_, err = DiscoverMarkets(ctx, eth, d)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cETH underlying")
+}
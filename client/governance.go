@@ -0,0 +1,234 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// ProposalRequest filters the proposals returned by the governance api
+type ProposalRequest struct {
+	// only return the proposals with these ids
+	ProposalIDs []int
+	// only return proposals in this state, ex. "active" or "pending"
+	State string
+	// include the targets, values, signatures and calldatas of each proposal
+	WithDetail bool
+	PageSize   uint32
+	PageNumber uint32
+}
+
+// Query encodes the request as GET query parameters
+func (r *ProposalRequest) Query() url.Values {
+	query := url.Values{}
+	for _, id := range r.ProposalIDs {
+		query.Add("proposal_ids[]", strconv.Itoa(id))
+	}
+	if r.State != "" {
+		query.Set("state", r.State)
+	}
+	if r.WithDetail {
+		query.Set("with_detail", "true")
+	}
+	setUint(query, "page_size", r.PageSize)
+	setUint(query, "page_number", r.PageNumber)
+	return query
+}
+
+// VoteReceiptRequest filters the vote receipts returned by the governance api
+type VoteReceiptRequest struct {
+	// only return votes cast on this proposal
+	ProposalID int
+	// only return votes cast by this account
+	Account string
+	// only return votes for (true) or against (false) the proposal
+	Support *bool
+	// include the proposal each vote was cast on
+	WithProposalData bool
+	PageSize         uint32
+	PageNumber       uint32
+}
+
+// Query encodes the request as GET query parameters
+func (r *VoteReceiptRequest) Query() url.Values {
+	query := url.Values{}
+	if r.ProposalID != 0 {
+		query.Set("proposal_id", strconv.Itoa(r.ProposalID))
+	}
+	if r.Account != "" {
+		query.Set("account", r.Account)
+	}
+	if r.Support != nil {
+		query.Set("support", strconv.FormatBool(*r.Support))
+	}
+	if r.WithProposalData {
+		query.Set("with_proposal_data", "true")
+	}
+	setUint(query, "page_size", r.PageSize)
+	setUint(query, "page_number", r.PageNumber)
+	return query
+}
+
+// GovernanceAccountRequest filters the accounts returned by the governance api
+type GovernanceAccountRequest struct {
+	// only return these accounts
+	Addresses []string
+	// the ordering of the accounts, ex. "votes" or "balance"
+	OrderBy string
+	// include the voting history of each account
+	WithHistory bool
+	PageSize    uint32
+	PageNumber  uint32
+}
+
+// Query encodes the request as GET query parameters
+func (r *GovernanceAccountRequest) Query() url.Values {
+	query := url.Values{}
+	for _, address := range r.Addresses {
+		query.Add("addresses[]", address)
+	}
+	if r.OrderBy != "" {
+		query.Set("order_by", r.OrderBy)
+	}
+	if r.WithHistory {
+		query.Set("with_history", "true")
+	}
+	setUint(query, "page_size", r.PageSize)
+	setUint(query, "page_number", r.PageNumber)
+	return query
+}
+
+// GetProposals is used to retrieve governance proposals
+func (c *Client) GetProposals(ctx context.Context, req *ProposalRequest) (*models.ProposalsResponse, error) {
+	if req == nil {
+		req = &ProposalRequest{}
+	}
+	response := &models.ProposalsResponse{}
+	if err := c.getResponse(ctx, c.endpoint("/governance/proposals", req.Query()), response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// ProposalPages walks every page of proposals matching req, see AccountPages
+func (c *Client) ProposalPages(ctx context.Context, req *ProposalRequest, concurrency int) <-chan Page[*models.ProposalsResponse] {
+	if req == nil {
+		req = &ProposalRequest{}
+	}
+	return walkPages(ctx, req.PageNumber, concurrency, func(ctx context.Context, number uint32) (*models.ProposalsResponse, uint32, error) {
+		pageReq := *req
+		pageReq.PageNumber = number
+		resp, err := c.GetProposals(ctx, &pageReq)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, uint32(resp.PaginationSummary.TotalPages), nil
+	})
+}
+
+// AllProposals collects the proposals of every page matching req, see AllAccounts
+func (c *Client) AllProposals(ctx context.Context, req *ProposalRequest, concurrency int) ([]models.Proposal, error) {
+	pages, err := collectPages(ctx, c.ProposalPages(ctx, req, concurrency))
+	var proposals []models.Proposal
+	for _, page := range pages {
+		proposals = append(proposals, page.Response.Proposals...)
+	}
+	return proposals, err
+}
+
+// GetProposalVoteReceipts is used to retrieve the votes cast on governance proposals
+func (c *Client) GetProposalVoteReceipts(ctx context.Context, req *VoteReceiptRequest) (*models.ProposalVoteReceiptsResponse, error) {
+	if req == nil {
+		req = &VoteReceiptRequest{}
+	}
+	if req.Account != "" && !common.IsHexAddress(req.Account) {
+		return nil, fmt.Errorf("invalid account address %q", req.Account)
+	}
+	response := &models.ProposalVoteReceiptsResponse{}
+	if err := c.getResponse(ctx, c.endpoint("/governance/proposal_vote_receipts", req.Query()), response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// VoteReceiptPages walks every page of vote receipts matching req, see AccountPages
+func (c *Client) VoteReceiptPages(ctx context.Context, req *VoteReceiptRequest, concurrency int) <-chan Page[*models.ProposalVoteReceiptsResponse] {
+	if req == nil {
+		req = &VoteReceiptRequest{}
+	}
+	return walkPages(ctx, req.PageNumber, concurrency, func(ctx context.Context, number uint32) (*models.ProposalVoteReceiptsResponse, uint32, error) {
+		pageReq := *req
+		pageReq.PageNumber = number
+		resp, err := c.GetProposalVoteReceipts(ctx, &pageReq)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, uint32(resp.PaginationSummary.TotalPages), nil
+	})
+}
+
+// AllVoteReceipts collects the vote receipts of every page matching req, see AllAccounts
+func (c *Client) AllVoteReceipts(ctx context.Context, req *VoteReceiptRequest, concurrency int) ([]models.ProposalVoteReceipt, error) {
+	pages, err := collectPages(ctx, c.VoteReceiptPages(ctx, req, concurrency))
+	var receipts []models.ProposalVoteReceipt
+	for _, page := range pages {
+		receipts = append(receipts, page.Response.ProposalVoteReceipts...)
+	}
+	return receipts, err
+}
+
+// GetGovernanceAccounts is used to retrieve the COMP balance, votes and delegate of accounts
+func (c *Client) GetGovernanceAccounts(ctx context.Context, req *GovernanceAccountRequest) (*models.GovernanceAccountsResponse, error) {
+	if req == nil {
+		req = &GovernanceAccountRequest{}
+	}
+	for _, address := range req.Addresses {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid account address %q", address)
+		}
+	}
+	response := &models.GovernanceAccountsResponse{}
+	if err := c.getResponse(ctx, c.endpoint("/governance/accounts", req.Query()), response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GovernanceAccountPages walks every page of governance accounts matching req, see AccountPages
+func (c *Client) GovernanceAccountPages(ctx context.Context, req *GovernanceAccountRequest, concurrency int) <-chan Page[*models.GovernanceAccountsResponse] {
+	if req == nil {
+		req = &GovernanceAccountRequest{}
+	}
+	return walkPages(ctx, req.PageNumber, concurrency, func(ctx context.Context, number uint32) (*models.GovernanceAccountsResponse, uint32, error) {
+		pageReq := *req
+		pageReq.PageNumber = number
+		resp, err := c.GetGovernanceAccounts(ctx, &pageReq)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, uint32(resp.PaginationSummary.TotalPages), nil
+	})
+}
+
+// AllGovernanceAccounts collects the governance accounts of every page matching req, see AllAccounts
+func (c *Client) AllGovernanceAccounts(ctx context.Context, req *GovernanceAccountRequest, concurrency int) ([]models.GovernanceAccount, error) {
+	pages, err := collectPages(ctx, c.GovernanceAccountPages(ctx, req, concurrency))
+	var accounts []models.GovernanceAccount
+	for _, page := range pages {
+		accounts = append(accounts, page.Response.Accounts...)
+	}
+	return accounts, err
+}
+
+// GetCompDistribution is used to retrieve the COMP distribution rate of every market
+func (c *Client) GetCompDistribution(ctx context.Context) (*models.CompDistributionResponse, error) {
+	response := &models.CompDistributionResponse{}
+	if err := c.getResponse(ctx, c.endpoint("/governance/comp", nil), response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Governance(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page_number"))
+		switch r.URL.Path {
+		case "/governance/proposals":
+			assert.Equal(t, "active", r.URL.Query().Get("state"))
+			fmt.Fprintf(w, `{"proposals":[{"id":%d,"title":"proposal %d","states":[
+				{"state":"pending","start_time":1},{"state":"active","start_time":2}],
+				"for_votes":"400000.0","against_votes":"0.0"}],
+				"pagination_summary":{"page_number":%d,"total_pages":3}}`, page, page, page)
+		case "/governance/proposal_vote_receipts":
+			assert.Equal(t, "false", r.URL.Query().Get("support"))
+			w.Write([]byte(`{"proposal_vote_receipts":[{"proposal_id":7,"support":false,"votes":"12.5",
+				"voter":{"address":"0x1"}}],"pagination_summary":{"page_number":1,"total_pages":1}}`))
+		case "/governance/accounts":
+			assert.Equal(t, []string{selfAccount}, r.URL.Query()["addresses[]"])
+			w.Write([]byte(`{"accounts":[{"address":"` + selfAccount + `","balance":"101.5","votes":"0.0",
+				"delegate":{"address":"` + account + `"}}],"pagination_summary":{"page_number":1,"total_pages":1}}`))
+		case "/governance/comp":
+			w.Write([]byte(`{"comp_rate":"0.5","daily_comp":"2880","markets":[{"address":"` + CompoundDAI.String() + `",
+				"symbol":"cDAI","comp_speed":"0.067","comp_supply_apy":{"value":"1.2"},"comp_borrow_apy":{"value":"3.4"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+	ctx := context.Background()
+
+	proposals, err := cl.AllProposals(ctx, &ProposalRequest{State: "active"}, 2)
+	require.NoError(t, err)
+	require.Len(t, proposals, 3)
+	for i, proposal := range proposals {
+		assert.Equal(t, i+1, proposal.ID)
+		assert.Equal(t, "active", proposal.State())
+	}
+
+	against := false
+	receipts, err := cl.AllVoteReceipts(ctx, &VoteReceiptRequest{ProposalID: 7, Support: &against}, 0)
+	require.NoError(t, err)
+	require.Len(t, receipts, 1)
+	assert.Equal(t, "12.5", receipts[0].Votes.String())
+
+	accounts, err := cl.GetGovernanceAccounts(ctx, &GovernanceAccountRequest{Addresses: []string{selfAccount}})
+	require.NoError(t, err)
+	require.Len(t, accounts.Accounts, 1)
+	assert.Equal(t, "101.5", accounts.Accounts[0].Balance.String())
+	assert.Equal(t, account, accounts.Accounts[0].Delegate.Address)
+	_, err = cl.GetGovernanceAccounts(ctx, &GovernanceAccountRequest{Addresses: []string{"nope"}})
+	assert.Error(t, err)
+
+	comp, err := cl.GetCompDistribution(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2880", comp.DailyComp.String())
+	assert.Equal(t, "1.2", comp.Markets[0].CompSupplyAPY.Value.String())
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/internal/apiutil"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// ReadOption is used to modify a read against the api
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	blockNumber    uint32
+	blockTimestamp uint32
+}
+
+// AtBlock requests the data as it was at the given block number
+func AtBlock(number uint32) ReadOption {
+	return func(o *readOptions) {
+		o.blockNumber = number
+		o.blockTimestamp = 0
+	}
+}
+
+// AtTime requests the data as it was at the given time
+func AtTime(t time.Time) ReadOption {
+	return func(o *readOptions) {
+		o.blockTimestamp = uint32(t.Unix())
+		o.blockNumber = 0
+	}
+}
+
+func applyReadOptions(opts []ReadOption) readOptions {
+	var o readOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o readOptions) account(req *AccountRequest) *AccountRequest {
+	if o.blockNumber != 0 {
+		req.BlockNumber(o.blockNumber)
+	}
+	if o.blockTimestamp != 0 {
+		req.BlockTimestamp(o.blockTimestamp)
+	}
+	return req
+}
+
+func (o readOptions) ctoken(req *CTokenRequest) *CTokenRequest {
+	if o.blockNumber != 0 {
+		req.BlockNumber(o.blockNumber)
+	}
+	if o.blockTimestamp != 0 {
+		req.BlockTimestamp(o.blockTimestamp)
+	}
+	return req
+}
+
+// CTokenRequest is used to build a query against the ctoken api,
+// exposing every filter of the CTokenRequest message in pb/ctoken.proto
+type CTokenRequest struct {
+	addresses      []string
+	blockNumber    uint32
+	blockTimestamp uint32
+}
+
+// NewCTokenRequest returns an empty request, which matches every ctoken
+func NewCTokenRequest() *CTokenRequest {
+	return &CTokenRequest{}
+}
+
+// Addresses restricts the request to the given ctoken addresses
+func (r *CTokenRequest) Addresses(addresses ...string) *CTokenRequest {
+	r.addresses = append(r.addresses, addresses...)
+	return r
+}
+
+// BlockNumber requests historical data as of the given block
+func (r *CTokenRequest) BlockNumber(number uint32) *CTokenRequest {
+	r.blockNumber = number
+	return r
+}
+
+// BlockTimestamp requests historical data as of the given unix timestamp
+func (r *CTokenRequest) BlockTimestamp(timestamp uint32) *CTokenRequest {
+	r.blockTimestamp = timestamp
+	return r
+}
+
+// Validate checks the addresses and block filters of the request
+func (r *CTokenRequest) Validate() error {
+	for _, address := range r.addresses {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid ctoken address %q", address)
+		}
+	}
+	if r.blockNumber != 0 && r.blockTimestamp != 0 {
+		return errors.New("only one of block_number or block_timestamp may be set")
+	}
+	return nil
+}
+
+// Query encodes the request as GET query parameters
+func (r *CTokenRequest) Query() url.Values {
+	query := url.Values{}
+	for _, address := range r.addresses {
+		query.Add("addresses[]", address)
+	}
+	setUint(query, "block_number", r.blockNumber)
+	setUint(query, "block_timestamp", r.blockTimestamp)
+	return query
+}
+
+// MarshalJSON encodes the request as the json body of a post_ctoken call
+func (r *CTokenRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ctokenRequestBody{
+		Addresses:      r.addresses,
+		BlockNumber:    r.blockNumber,
+		BlockTimestamp: r.blockTimestamp,
+	})
+}
+
+type ctokenRequestBody struct {
+	Addresses      []string `json:"addresses,omitempty"`
+	BlockNumber    uint32   `json:"block_number,omitempty"`
+	BlockTimestamp uint32   `json:"block_timestamp,omitempty"`
+}
+
+// QueryCTokens retrieves the ctokens matching the given request. Requests
+// for many addresses are sent as a post_ctoken call, everything else uses GET
+func (c *Client) QueryCTokens(ctx context.Context, req *CTokenRequest) (*models.CTokenResponse, error) {
+	if req == nil {
+		req = NewCTokenRequest()
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	response := &models.CTokenResponse{}
+	if len(req.addresses) > maxQueryAddresses {
+		body, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.postResponse(ctx, c.endpoint("/ctoken", nil), body, response); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+	if err := c.getResponse(ctx, c.endpoint("/ctoken", req.Query()), response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// CTokenSnapshot is the state of the ctoken market at a given block
+type CTokenSnapshot struct {
+	BlockNumber uint32
+	CToken      models.CToken
+}
+
+// CTokenHistory retrieves the state of a ctoken at each of the given blocks,
+// fetching up to concurrency blocks in parallel. Snapshots are returned in the
+// order of blocks, and the first failed request aborts the whole series
+func (c *Client) CTokenHistory(ctx context.Context, address string, blocks []uint32, concurrency int) ([]CTokenSnapshot, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid ctoken address %q", address)
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultPageConcurrency
+	}
+	snapshots := make([]CTokenSnapshot, len(blocks))
+	err := apiutil.Parallel(ctx, len(blocks), concurrency, func(ctx context.Context, i int) error {
+		resp, err := c.QueryCTokens(ctx, NewCTokenRequest().Addresses(address).BlockNumber(blocks[i]))
+		if err == nil && len(resp.CToken) == 0 {
+			err = errors.New("ctoken not found")
+		}
+		if err != nil {
+			return fmt.Errorf("block %d: %w", blocks[i], err)
+		}
+		snapshots[i] = CTokenSnapshot{BlockNumber: blocks[i], CToken: resp.CToken[0]}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
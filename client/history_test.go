@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadOptions(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("block_number")+"/"+r.URL.Query().Get("block_timestamp"))
+		w.Write([]byte(`{"accounts": [], "cToken": []}`))
+	}))
+	defer srv.Close()
+
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+	_, err := cl.GetAccount(account, AtBlock(9000000))
+	require.NoError(t, err)
+	_, err = cl.GetCToken(CompoundBAT.String(), AtTime(time.Unix(1571875200, 0)))
+	require.NoError(t, err)
+	// the last option wins
+	_, err = cl.GetCTokens(AtTime(time.Unix(1571875200, 0)), AtBlock(8830740))
+	require.NoError(t, err)
+	_, err = cl.GetAccounts("10", "1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"9000000/", "/1571875200", "8830740/", "/"}, queries)
+
+	// the helpers built on the reads are pinned too
+	queries = nil
+	ctx := context.Background()
+	_, err = cl.GetLiquidatableAccounts("10", "1", AtBlock(1))
+	assert.Error(t, err)
+	_, err = cl.ScanLiquidatableAccounts(ctx, 2, AtBlock(2))
+	require.NoError(t, err)
+	src := cl.Source(AtTime(time.Unix(1571875200, 0)))
+	_, err = SupplyInterestEarned(ctx, src, account, CompoundBAT)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	_, err = src.CToken(ctx, CompoundBAT.String())
+	assert.ErrorIs(t, err, ErrMarketNotFound)
+	_, err = cl.Source().AtRisk(ctx, liquidationHealth)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1/", "2/", "/1571875200", "/1571875200", "/"}, queries)
+}
+
+func Test_CTokenHistory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		block := r.URL.Query().Get("block_number")
+		if block == "3" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"cToken": [{"token_address": %q, "block_number": %s}]}`, r.URL.Query().Get("addresses[]"), block)
+	}))
+	defer srv.Close()
+
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+	snapshots, err := cl.CTokenHistory(context.Background(), CompoundBAT.String(), []uint32{1, 2, 4, 5}, 2)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 4)
+	for i, block := range []uint32{1, 2, 4, 5} {
+		assert.Equal(t, block, snapshots[i].BlockNumber)
+		assert.Equal(t, CompoundBAT.String(), snapshots[i].CToken.TokenAddress)
+	}
+
+	_, err = cl.CTokenHistory(context.Background(), CompoundBAT.String(), []uint32{1, 2, 3, 4}, 2)
+	assert.Error(t, err)
+	_, err = cl.CTokenHistory(context.Background(), "cBAT", []uint32{1}, 2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,331 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultURL is the base url of the public compound.finance api
+	DefaultURL = "https://api.compound.finance/api/v2"
+	// defaultTimeout is the per-attempt timeout used unless overridden with WithTimeout
+	defaultTimeout = time.Second * 30
+	// maxErrorBody caps how much of a non-2xx response body is kept on an HTTPError
+	maxErrorBody = 4096
+)
+
+// DefaultRetryPolicy is the retry policy used by clients created with NewClient
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: time.Millisecond * 500,
+	MaxBackoff: time.Second * 10,
+}
+
+// RetryPolicy controls how requests that fail with a network error,
+// a 429 or a 5xx status are retried. Backoff grows exponentially from
+// MinBackoff up to MaxBackoff, with full jitter applied to every wait
+type RetryPolicy struct {
+	// the number of retries after the first attempt, 0 disables retrying
+	MaxRetries int
+	// the base delay used for the first retry
+	MinBackoff time.Duration
+	// the upper bound of any single delay
+	MaxBackoff time.Duration
+}
+
+// backoff returns the jittered delay to wait before the given retry attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.MinBackoff <= 0 {
+		return 0
+	}
+	ceiling := float64(p.MinBackoff) * math.Pow(2, float64(attempt))
+	if p.MaxBackoff > 0 && ceiling > float64(p.MaxBackoff) {
+		ceiling = float64(p.MaxBackoff)
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// HTTPError is returned when the api responds with a non-2xx status code
+type HTTPError struct {
+	// the url that was requested
+	URL string
+	// the http status code, ex. 503
+	StatusCode int
+	// the http status line, ex. "503 Service Unavailable"
+	Status string
+	// the (possibly truncated) response body
+	Body []byte
+	// the delay requested by the server through the Retry-After header
+	retryAfter time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("compound api returned %s for %s", e.Status, e.URL)
+}
+
+// Temporary reports whether the request may succeed if retried
+func (e *HTTPError) Temporary() bool {
+	return retryableStatus(e.StatusCode)
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// endpoint joins the api url with the given path and query parameters
+func (c *Client) endpoint(path string, query url.Values) string {
+	apiURL := strings.TrimRight(c.url, "/") + path
+	if len(query) > 0 {
+		apiURL = apiURL + "?" + query.Encode()
+	}
+	return apiURL
+}
+
+// apiResponse is implemented by api response models carrying an error object
+type apiResponse interface {
+	Err() error
+}
+
+// getResponse sends a GET request, decodes the response body into out and
+// returns the api error reported in the body, if any
+func (c *Client) getResponse(ctx context.Context, apiURL string, out apiResponse) error {
+	return c.doResponse(ctx, "GET", apiURL, nil, out)
+}
+
+// postResponse is like getResponse, but POSTs the given json body
+func (c *Client) postResponse(ctx context.Context, apiURL string, body []byte, out apiResponse) error {
+	return c.doResponse(ctx, "POST", apiURL, body, out)
+}
+
+// doResponse sends a request and decodes the api response into out. Non-2xx
+// responses whose body carries an api error object are reported as that
+// error instead of the generic HTTPError
+func (c *Client) doResponse(ctx context.Context, method, apiURL string, body []byte, out apiResponse) error {
+	cacheable := c.cache != nil && method == "GET"
+	if cacheable {
+		if cached, ok := c.cache.Get(apiURL); ok && json.Unmarshal(cached, out) == nil {
+			return out.Err()
+		}
+	}
+	bodyBytes, err := c.sendRequest(ctx, method, apiURL, body)
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		if json.Unmarshal(httpErr.Body, out) == nil && out.Err() != nil {
+			return out.Err()
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(bodyBytes, out); err != nil {
+		return err
+	}
+	if err := out.Err(); err != nil {
+		return err
+	}
+	if cacheable {
+		c.cache.Set(apiURL, bodyBytes, c.cacheTTL)
+	}
+	return nil
+}
+
+// sendRequest is used to send a request, and return the given body bytes.
+// Requests are rate limited, and retried according to the client's retry policy
+func (c *Client) sendRequest(ctx context.Context, method, apiURL string, body []byte) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.retry.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.retry.backoff(attempt - 1)
+			if httpErr, ok := lastErr.(*HTTPError); ok && httpErr.retryAfter > delay {
+				delay = httpErr.retryAfter
+			}
+			if err := sleep(ctx, delay); err != nil {
+				return nil, err
+			}
+		}
+		if c.limiter != nil {
+			if err := c.limiter.wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		bodyBytes, err := c.do(ctx, method, apiURL, body)
+		if err == nil {
+			return bodyBytes, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		lastErr = err
+		if !retryable(err) {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// do performs a single request attempt
+func (c *Client) do(ctx context.Context, method, apiURL string, body []byte) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	request, err := http.NewRequestWithContext(ctx, method, apiURL, reader)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.header {
+		request.Header[key] = append([]string(nil), values...)
+	}
+	if body != nil {
+		request.Header.Set("content-type", "application/json")
+	}
+	for _, hook := range c.requestHooks {
+		hook(request)
+	}
+	start := time.Now()
+	resp, err := c.client.Do(request)
+	if err != nil {
+		c.afterResponse(request, nil, err, start)
+		return nil, &netError{err}
+	}
+	defer resp.Body.Close()
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	c.afterResponse(request, resp, err, start)
+	if err != nil {
+		return nil, &netError{err}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(bodyBytes) > maxErrorBody {
+			bodyBytes = bodyBytes[:maxErrorBody]
+		}
+		return nil, &HTTPError{
+			URL:        apiURL,
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       bodyBytes,
+			retryAfter: retryAfter(resp.Header),
+		}
+	}
+	return bodyBytes, nil
+}
+
+// afterResponse runs the response hooks of the client
+func (c *Client) afterResponse(req *http.Request, resp *http.Response, err error, start time.Time) {
+	if len(c.responseHooks) == 0 {
+		return
+	}
+	elapsed := time.Since(start)
+	for _, hook := range c.responseHooks {
+		hook(req, resp, err, elapsed)
+	}
+}
+
+// netError marks transport level failures, which are always retryable
+type netError struct {
+	err error
+}
+
+func (e *netError) Error() string { return e.err.Error() }
+func (e *netError) Unwrap() error { return e.err }
+
+func retryable(err error) bool {
+	switch e := err.(type) {
+	case *netError:
+		return true
+	case *HTTPError:
+		return e.Temporary()
+	default:
+		return false
+	}
+}
+
+// retryAfter parses the Retry-After header, which is either a
+// number of seconds or an http date
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// sleep waits for the given duration, returning early if the context is cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimiter is a token bucket shared by all requests of a client
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available or the context is cancelled
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/musinit/go-defi/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastRetry = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: time.Millisecond,
+	MaxBackoff: time.Millisecond * 5,
+}
+
+func Test_SendRequest_RetriesTemporaryErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte(`{"accounts":[{"address":"` + account + `"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+	resp, err := cl.GetAccountContext(context.Background(), account)
+	require.NoError(t, err)
+	assert.Equal(t, account, resp.Accounts[0].Address)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func Test_SendRequest_HTTPError(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("content-type", "text/html")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<html>not found</html>"))
+	}))
+	defer srv.Close()
+
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+	_, err := cl.GetCTokensContext(context.Background())
+	var httpErr *HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	assert.Equal(t, "<html>not found</html>", string(httpErr.Body))
+	assert.False(t, httpErr.Temporary())
+	// client errors are not retried
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func Test_SendRequest_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+	_, err := cl.GetCTokensContext(context.Background())
+	var httpErr *HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	assert.Equal(t, int32(fastRetry.MaxRetries+1), atomic.LoadInt32(&calls))
+}
+
+func Test_SendRequest_ContextCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cl := NewClient(srv.URL, WithRetryPolicy(RetryPolicy{MaxRetries: 10, MinBackoff: time.Second}))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	_, err := cl.GetCTokensContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func Test_RateLimiter(t *testing.T) {
+	limiter := newRateLimiter(100, 2)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, limiter.wait(ctx))
+	}
+	// two requests are served from the burst, the other two wait ~10ms each
+	assert.True(t, time.Since(start) >= time.Millisecond*15)
+
+	slow := newRateLimiter(0.001, 1)
+	require.NoError(t, slow.wait(ctx))
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, slow.wait(ctx), context.Canceled)
+}
+
+func Test_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Query().Get("page_number") == "999":
+			w.Write([]byte(`{"accounts":[],"error":{"error_code":2,"message":"page out of range"}}`))
+		case r.URL.Query().Get("page_size") == "5000":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"error_code":3,"field_errors":{"page_size":"too large"}}}`))
+		default:
+			w.Write([]byte(`{"accounts":[],"error":{"error_code":0}}`))
+		}
+	}))
+	defer srv.Close()
+
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+	_, err := cl.GetAccountsContext(context.Background(), "10", "1")
+	require.NoError(t, err)
+
+	_, err = cl.GetAccountsContext(context.Background(), "10", "999")
+	assert.ErrorIs(t, err, models.ErrInvalidPageNumber)
+	assert.False(t, errors.Is(err, models.ErrInvalidPageSize))
+	var apiErr *models.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "page out of range", apiErr.Message)
+
+	_, err = cl.GetAccountsContext(context.Background(), "5000", "1")
+	assert.ErrorIs(t, err, models.ErrInvalidPageSize)
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "too large", apiErr.FieldErrors["page_size"])
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// GetMarketHistory is used to retrieve the rate, exchange rate, totals and price
+// history of a ctoken between two unix timestamps, split into numBuckets data points
+func (c *Client) GetMarketHistory(asset string, minBlockTimestamp, maxBlockTimestamp, numBuckets uint32) (*models.MarketHistoryResponse, error) {
+	return c.GetMarketHistoryContext(context.Background(), asset, minBlockTimestamp, maxBlockTimestamp, numBuckets)
+}
+
+// GetMarketHistoryContext is like GetMarketHistory but uses the given context for the request
+func (c *Client) GetMarketHistoryContext(ctx context.Context, asset string, minBlockTimestamp, maxBlockTimestamp, numBuckets uint32) (*models.MarketHistoryResponse, error) {
+	if !common.IsHexAddress(asset) {
+		return nil, fmt.Errorf("invalid asset address %q", asset)
+	}
+	if maxBlockTimestamp < minBlockTimestamp {
+		return nil, errors.New("max block timestamp is before min block timestamp")
+	}
+	if numBuckets == 0 {
+		return nil, errors.New("number of buckets must be greater than 0")
+	}
+	apiURL := c.endpoint("/market_history/graph", url.Values{
+		"asset":               {asset},
+		"min_block_timestamp": {strconv.FormatUint(uint64(minBlockTimestamp), 10)},
+		"max_block_timestamp": {strconv.FormatUint(uint64(maxBlockTimestamp), 10)},
+		"num_buckets":         {strconv.FormatUint(uint64(numBuckets), 10)},
+	})
+	response := &models.MarketHistoryResponse{}
+	if err := c.getResponse(ctx, apiURL, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
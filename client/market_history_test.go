@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const marketHistoryJSON = `{
+	"borrow_rates": [{"block_number": 8830740, "block_timestamp": 1571875217, "rate": 0.0532}],
+	"exchange_rates": [{"block_number": 8830740, "block_timestamp": 1571875217, "rate": 0.02004}],
+	"prices_usd": [{"block_number": 8830740, "block_timestamp": 1571875217, "price": {"value": "0.2236"}}],
+	"supply_rates": [{"block_number": 8830740, "block_timestamp": 1571875217, "rate": 0.0175}],
+	"total_borrows_history": [{"block_number": 8830740, "block_timestamp": 1571875217, "total": {"value": "1932876.12"}}],
+	"total_supply_history": [{"block_number": 8830740, "block_timestamp": 1571875217, "total": {"value": "1248316420.82"}}]
+}`
+
+func Test_GetMarketHistory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/market_history/graph", r.URL.Path)
+		assert.Equal(t, CompoundBAT.String(), r.URL.Query().Get("asset"))
+		assert.Equal(t, "1571875200", r.URL.Query().Get("min_block_timestamp"))
+		assert.Equal(t, "1574467200", r.URL.Query().Get("max_block_timestamp"))
+		assert.Equal(t, "10", r.URL.Query().Get("num_buckets"))
+		w.Write([]byte(marketHistoryJSON))
+	}))
+	defer srv.Close()
+
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+	resp, err := cl.GetMarketHistoryContext(context.Background(), CompoundBAT.String(), 1571875200, 1574467200, 10)
+	require.NoError(t, err)
+	require.Len(t, resp.SupplyRates, 1)
+	assert.Equal(t, "0.0175", resp.SupplyRates[0].Rate.String())
+	assert.Equal(t, "0.0532", resp.BorrowRates[0].Rate.String())
+	assert.Equal(t, "0.02004", resp.ExchangeRates[0].Rate.String())
+	assert.Equal(t, 8830740, resp.PricesUSD[0].BlockNumber)
+	assert.Equal(t, "0.2236", resp.PricesUSD[0].Price.Value.String())
+	assert.Equal(t, "1248316420.82", resp.TotalSupplyHistory[0].Total.Value.String())
+	assert.Equal(t, "1932876.12", resp.TotalBorrowsHistory[0].Total.Value.String())
+
+	_, err = cl.GetMarketHistoryContext(context.Background(), CompoundBAT.String(), 2, 1, 10)
+	assert.Error(t, err)
+	_, err = cl.GetMarketHistoryContext(context.Background(), "cBAT", 1, 2, 10)
+	assert.Error(t, err)
+}
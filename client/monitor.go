@@ -0,0 +1,375 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	comptroller "github.com/musinit/go-defi/v2/bindings/comptroller"
+)
+
+const (
+	// DefaultPollInterval is how often a LiquidityMonitor polls for new blocks
+	// when the node does not support subscriptions, ex. over http
+	DefaultPollInterval = time.Second * 4
+	// DefaultBatchSize is the number of eth_calls sent in a single rpc batch
+	DefaultBatchSize = 100
+)
+
+// Liquidity is an account's liquidity and shortfall as reported by the
+// comptroller, in the unit of the price oracle scaled by 1e18. At most one of
+// them is non zero, a non zero shortfall means the account can be liquidated
+type Liquidity struct {
+	Account   common.Address
+	Liquidity *big.Int
+	Shortfall *big.Int
+}
+
+// LiquidityReader is used to read the liquidity of many accounts at a block
+type LiquidityReader interface {
+	// AccountLiquidities returns the liquidity of every account, in order, as
+	// of block, or the latest block when block is nil
+	AccountLiquidities(ctx context.Context, block *big.Int, accounts []common.Address) ([]Liquidity, error)
+}
+
+// BatchLiquidityReader is a LiquidityReader batching the comptroller's
+// getAccountLiquidity calls into as few rpc requests as possible
+type BatchLiquidityReader struct {
+	client      *rpc.Client
+	comptroller common.Address
+	batchSize   int
+	abi         abi.ABI
+}
+
+// NewBatchLiquidityReader returns a reader calling the comptroller at the
+// given address, usually the Unitroller, with up to batchSize calls per rpc
+// request. A batchSize of 0 uses DefaultBatchSize
+func NewBatchLiquidityReader(client *rpc.Client, comptrollerAddress common.Address, batchSize int) (*BatchLiquidityReader, error) {
+	parsed, err := abi.JSON(strings.NewReader(comptroller.BindingsABI))
+	if err != nil {
+		return nil, err
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &BatchLiquidityReader{client: client, comptroller: comptrollerAddress, batchSize: batchSize, abi: parsed}, nil
+}
+
+// AccountLiquidities returns the liquidity of every account as of block
+func (r *BatchLiquidityReader) AccountLiquidities(ctx context.Context, block *big.Int, accounts []common.Address) ([]Liquidity, error) {
+	blockArg := "latest"
+	if block != nil {
+		blockArg = hexutil.EncodeBig(block)
+	}
+	out := make([]Liquidity, 0, len(accounts))
+	for start := 0; start < len(accounts); start += r.batchSize {
+		end := start + r.batchSize
+		if end > len(accounts) {
+			end = len(accounts)
+		}
+		batch := make([]rpc.BatchElem, 0, end-start)
+		for _, account := range accounts[start:end] {
+			data, err := r.abi.Pack("getAccountLiquidity", account)
+			if err != nil {
+				return nil, err
+			}
+			batch = append(batch, rpc.BatchElem{
+				Method: "eth_call",
+				Args: []interface{}{
+					map[string]interface{}{"to": r.comptroller, "data": hexutil.Bytes(data)},
+					blockArg,
+				},
+				Result: new(hexutil.Bytes),
+			})
+		}
+		if err := r.client.BatchCallContext(ctx, batch); err != nil {
+			return nil, err
+		}
+		for i, elem := range batch {
+			account := accounts[start+i]
+			if elem.Error != nil {
+				return nil, fmt.Errorf("account %s: %w", account.Hex(), elem.Error)
+			}
+			values, err := r.abi.Unpack("getAccountLiquidity", *elem.Result.(*hexutil.Bytes))
+			if err != nil {
+				return nil, fmt.Errorf("account %s: %w", account.Hex(), err)
+			}
+			if values[0].(*big.Int).Sign() != 0 {
+				return nil, fmt.Errorf("account %s: smart contract return a non 0 error code", account.Hex())
+			}
+			out = append(out, Liquidity{Account: account, Liquidity: values[1].(*big.Int), Shortfall: values[2].(*big.Int)})
+		}
+	}
+	return out, nil
+}
+
+// HeadReader is the subset of an ethclient.Client used to follow new blocks
+type HeadReader interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// LiquidityEvent is emitted by a LiquidityMonitor when an account's liquidity
+// or shortfall changes, including the first time it is read. When reading a
+// block fails Err is set and only Block and Time are
+type LiquidityEvent struct {
+	Block             uint64
+	Account           common.Address
+	Liquidity         *big.Int
+	Shortfall         *big.Int
+	PreviousLiquidity *big.Int
+	PreviousShortfall *big.Int
+	Time              time.Time
+	Err               error
+}
+
+// Underwater returns whether the account can be liquidated
+func (e LiquidityEvent) Underwater() bool {
+	return e.Shortfall != nil && e.Shortfall.Sign() > 0
+}
+
+// String returns a one line description of the event, suitable for logging
+func (e LiquidityEvent) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("block %d read failed: %s", e.Block, e.Err)
+	}
+	return fmt.Sprintf("block %d account %s liquidity %s shortfall %s", e.Block, e.Account.Hex(), e.Liquidity, e.Shortfall)
+}
+
+// MonitorOption is used to configure a LiquidityMonitor
+type MonitorOption func(*LiquidityMonitor)
+
+// WithPollInterval sets how often the monitor polls for new blocks when the
+// node does not support subscriptions
+func WithPollInterval(interval time.Duration) MonitorOption {
+	return func(m *LiquidityMonitor) {
+		m.pollInterval = interval
+	}
+}
+
+// WithMonitorBuffer sets the size of the events channel
+func WithMonitorBuffer(size int) MonitorOption {
+	return func(m *LiquidityMonitor) {
+		m.events = make(chan LiquidityEvent, size)
+	}
+}
+
+// LiquidityMonitor is used to re-evaluate the liquidity of a set of accounts
+// on every new block, reporting changes within a block of them happening.
+// It subscribes to new heads, and falls back to polling for them when the
+// node does not support subscriptions. Like the PortfolioWatcher, events are
+// delivered without blocking and dropped when nobody reads them
+type LiquidityMonitor struct {
+	heads        HeadReader
+	reader       LiquidityReader
+	pollInterval time.Duration
+	events       chan LiquidityEvent
+
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+
+	mu       sync.Mutex
+	accounts map[common.Address]*Liquidity
+	running  bool
+}
+
+// NewLiquidityMonitor returns a monitor following blocks through heads and
+// reading liquidity through reader
+func NewLiquidityMonitor(heads HeadReader, reader LiquidityReader, opts ...MonitorOption) *LiquidityMonitor {
+	m := &LiquidityMonitor{
+		heads:        heads,
+		reader:       reader,
+		pollInterval: DefaultPollInterval,
+		events:       make(chan LiquidityEvent, DefaultEventBuffer),
+		accounts:     make(map[common.Address]*Liquidity),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// DialLiquidityMonitor connects to the node at url and returns a monitor of
+// the comptroller at the given address, usually the Unitroller
+func DialLiquidityMonitor(ctx context.Context, url string, comptrollerAddress common.Address, opts ...MonitorOption) (*LiquidityMonitor, error) {
+	rpcClient, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := NewBatchLiquidityReader(rpcClient, comptrollerAddress, DefaultBatchSize)
+	if err != nil {
+		rpcClient.Close()
+		return nil, err
+	}
+	return NewLiquidityMonitor(ethclient.NewClient(rpcClient), reader, opts...), nil
+}
+
+// Watch adds accounts to the monitored set, they are read on the next block
+func (m *LiquidityMonitor) Watch(accounts ...common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, account := range accounts {
+		if _, ok := m.accounts[account]; !ok {
+			m.accounts[account] = nil
+		}
+	}
+}
+
+// Unwatch removes accounts from the monitored set
+func (m *LiquidityMonitor) Unwatch(accounts ...common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, account := range accounts {
+		delete(m.accounts, account)
+	}
+}
+
+// Liquidity returns the last read liquidity of an account, or nil if it was
+// not read yet
+func (m *LiquidityMonitor) Liquidity(account common.Address) *Liquidity {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.accounts[account]
+}
+
+// Events returns the channel events are delivered on, it is closed once Run returns
+func (m *LiquidityMonitor) Events() <-chan LiquidityEvent {
+	return m.events
+}
+
+// Stats returns the number of delivered and dropped events
+func (m *LiquidityMonitor) Stats() WatcherStats {
+	return WatcherStats{Delivered: m.delivered.Load(), Dropped: m.dropped.Load()}
+}
+
+// Run follows new blocks until ctx is done, and then closes the events
+// channel. A monitor can only be run once
+func (m *LiquidityMonitor) Run(ctx context.Context) error {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return errors.New("monitor is already running")
+	}
+	m.running = true
+	m.mu.Unlock()
+	defer close(m.events)
+
+	headers := make(chan *types.Header, 16)
+	sub, err := m.heads.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		// http only nodes can not push new heads
+		m.poll(ctx)
+		return nil
+	}
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.Err():
+			// the subscription dropped, keep following blocks by polling
+			m.poll(ctx)
+			return nil
+		case header := <-headers:
+			m.check(ctx, header.Number)
+		}
+	}
+}
+
+// poll checks the latest block every poll interval, blocks mined in between
+// two polls are skipped
+func (m *LiquidityMonitor) poll(ctx context.Context) {
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+	var last uint64
+	for {
+		header, err := m.heads.HeaderByNumber(ctx, nil)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			m.emit(LiquidityEvent{Time: time.Now(), Err: err})
+		case header.Number.Uint64() > last:
+			last = header.Number.Uint64()
+			m.check(ctx, header.Number)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check reads every watched account at block, emitting an event for each
+// account whose liquidity or shortfall changed
+func (m *LiquidityMonitor) check(ctx context.Context, block *big.Int) {
+	m.mu.Lock()
+	accounts := make([]common.Address, 0, len(m.accounts))
+	for account := range m.accounts {
+		accounts = append(accounts, account)
+	}
+	m.mu.Unlock()
+	if len(accounts) == 0 {
+		return
+	}
+	liquidities, err := m.reader.AccountLiquidities(ctx, block, accounts)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		m.emit(LiquidityEvent{Block: block.Uint64(), Time: time.Now(), Err: err})
+		return
+	}
+	var events []LiquidityEvent
+	m.mu.Lock()
+	for i := range liquidities {
+		current := &liquidities[i]
+		previous, ok := m.accounts[current.Account]
+		if !ok {
+			// unwatched during the read
+			continue
+		}
+		m.accounts[current.Account] = current
+		if previous != nil && previous.Liquidity.Cmp(current.Liquidity) == 0 && previous.Shortfall.Cmp(current.Shortfall) == 0 {
+			continue
+		}
+		event := LiquidityEvent{
+			Block:     block.Uint64(),
+			Account:   current.Account,
+			Liquidity: current.Liquidity,
+			Shortfall: current.Shortfall,
+			Time:      time.Now(),
+		}
+		if previous != nil {
+			event.PreviousLiquidity, event.PreviousShortfall = previous.Liquidity, previous.Shortfall
+		}
+		events = append(events, event)
+	}
+	m.mu.Unlock()
+	for _, event := range events {
+		m.emit(event)
+	}
+}
+
+// emit delivers an event without blocking
+func (m *LiquidityMonitor) emit(event LiquidityEvent) {
+	select {
+	case m.events <- event:
+		m.delivered.Add(1)
+	default:
+		m.dropped.Add(1)
+	}
+}
@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+	comptroller "github.com/musinit/go-defi/v2/bindings/comptroller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// liquidityNode is the eth namespace of a node with a comptroller where every
+// account has 100 of liquidity, unless a shortfall is set for it
+type liquidityNode struct {
+	abi abi.ABI
+
+	mu        sync.Mutex
+	block     int64
+	shortfall map[common.Address]int64
+}
+
+type callArgs struct {
+	To   common.Address `json:"to"`
+	Data hexutil.Bytes  `json:"data"`
+}
+
+func (n *liquidityNode) Call(args callArgs, block string) (hexutil.Bytes, error) {
+	method := n.abi.Methods["getAccountLiquidity"]
+	in, err := method.Inputs.Unpack(args.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if shortfall, ok := n.shortfall[in[0].(common.Address)]; ok {
+		return method.Outputs.Pack(new(big.Int), new(big.Int), big.NewInt(shortfall))
+	}
+	return method.Outputs.Pack(new(big.Int), big.NewInt(100), new(big.Int))
+}
+
+func (n *liquidityNode) GetBlockByNumber(number string, full bool) (*types.Header, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return &types.Header{Number: big.NewInt(n.block), Difficulty: new(big.Int)}, nil
+}
+
+func (n *liquidityNode) mine(shortfalls map[common.Address]int64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.block++
+	for account, shortfall := range shortfalls {
+		n.shortfall[account] = shortfall
+	}
+}
+
+func nextLiquidityEvent(t *testing.T, m *LiquidityMonitor) LiquidityEvent {
+	t.Helper()
+	select {
+	case event := <-m.Events():
+		return event
+	case <-time.After(time.Second * 5):
+		t.Fatal("no event")
+		return LiquidityEvent{}
+	}
+}
+
+func Test_LiquidityMonitor_Polling(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(comptroller.BindingsABI))
+	require.NoError(t, err)
+	node := &liquidityNode{abi: parsed, block: 1, shortfall: map[common.Address]int64{}}
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("eth", node))
+	var requests atomic.Int64
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		srv.ServeHTTP(w, r)
+	}))
+	defer api.Close()
+	rpcClient, err := rpc.Dial(api.URL)
+	require.NoError(t, err)
+	defer rpcClient.Close()
+
+	accounts := make([]common.Address, 5)
+	for i := range accounts {
+		accounts[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+	}
+	reader, err := NewBatchLiquidityReader(rpcClient, Unitroller.EthAddress(), 2)
+	require.NoError(t, err)
+	liquidities, err := reader.AccountLiquidities(context.Background(), big.NewInt(1), accounts)
+	require.NoError(t, err)
+	require.Len(t, liquidities, 5)
+	assert.Equal(t, accounts[4], liquidities[4].Account)
+	assert.Equal(t, "100", liquidities[4].Liquidity.String())
+	// 5 calls in batches of 2
+	assert.Equal(t, int64(3), requests.Load())
+
+	monitor := NewLiquidityMonitor(ethclient.NewClient(rpcClient), reader, WithPollInterval(time.Millisecond*10))
+	monitor.Watch(accounts[0], accounts[1])
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- monitor.Run(ctx) }()
+
+	for i := 0; i < 2; i++ {
+		event := nextLiquidityEvent(t, monitor)
+		require.NoError(t, event.Err)
+		assert.Equal(t, uint64(1), event.Block)
+		assert.Nil(t, event.PreviousLiquidity)
+		assert.False(t, event.Underwater())
+	}
+
+	node.mine(map[common.Address]int64{accounts[1]: 7})
+	event := nextLiquidityEvent(t, monitor)
+	assert.Equal(t, uint64(2), event.Block)
+	assert.Equal(t, accounts[1], event.Account)
+	assert.True(t, event.Underwater())
+	assert.Equal(t, "7", event.Shortfall.String())
+	assert.Equal(t, "0", event.Liquidity.String())
+	assert.Equal(t, "100", event.PreviousLiquidity.String())
+	assert.Equal(t, "7", monitor.Liquidity(accounts[1]).Shortfall.String())
+
+	cancel()
+	require.NoError(t, <-done)
+	_, open := <-monitor.Events()
+	assert.False(t, open)
+	assert.Error(t, monitor.Run(context.Background()))
+}
+
+// headFeed is a HeadReader pushing the headers sent on it to subscribers
+type headFeed struct {
+	feed event.Feed
+}
+
+func (h *headFeed) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return h.feed.Subscribe(ch), nil
+}
+
+func (h *headFeed) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return nil, ethereum.NotFound
+}
+
+// shortfallReader reports a shortfall equal to the block number
+type shortfallReader struct{}
+
+func (shortfallReader) AccountLiquidities(ctx context.Context, block *big.Int, accounts []common.Address) ([]Liquidity, error) {
+	var out []Liquidity
+	for _, account := range accounts {
+		out = append(out, Liquidity{Account: account, Liquidity: new(big.Int), Shortfall: new(big.Int).Set(block)})
+	}
+	return out, nil
+}
+
+func Test_LiquidityMonitor_Subscription(t *testing.T) {
+	heads := &headFeed{}
+	monitor := NewLiquidityMonitor(heads, shortfallReader{})
+	monitor.Watch(common.HexToAddress(account))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- monitor.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		return heads.feed.Send(&types.Header{Number: big.NewInt(10)}) > 0
+	}, time.Second*5, time.Millisecond)
+	event := nextLiquidityEvent(t, monitor)
+	assert.Equal(t, uint64(10), event.Block)
+	assert.Equal(t, "10", event.Shortfall.String())
+
+	heads.feed.Send(&types.Header{Number: big.NewInt(11)})
+	event = nextLiquidityEvent(t, monitor)
+	assert.Equal(t, "10", event.PreviousShortfall.String())
+	assert.Equal(t, "11", event.Shortfall.String())
+
+	monitor.Unwatch(common.HexToAddress(account))
+	assert.Nil(t, monitor.Liquidity(common.HexToAddress(account)))
+	cancel()
+	require.NoError(t, <-done)
+}
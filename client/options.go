@@ -0,0 +1,118 @@
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/musinit/go-defi/v2/cache"
+)
+
+// Option is used to configure a Client
+type Option func(*Client)
+
+// RequestHook is called with every request attempt before it is sent,
+// and may modify it, ex. to sign it or add tracing headers
+type RequestHook func(req *http.Request)
+
+// ResponseHook is called after every request attempt with the response, or the
+// transport error, and how long the attempt took. The response body has already
+// been read and closed by the time the hook runs
+type ResponseHook func(req *http.Request, resp *http.Response, err error, elapsed time.Duration)
+
+// WithRetryPolicy overrides the retry policy used for failed requests
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithRateLimit limits the client to rps requests per second, allowing
+// bursts of up to burst requests. A rps of 0 or lower disables rate limiting
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Client) {
+		if rps <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = newRateLimiter(rps, burst)
+	}
+}
+
+// WithBaseURL overrides the api url the client was created with,
+// ex. to point it at a self hosted compound compatible api
+func WithBaseURL(url string) Option {
+	return func(c *Client) {
+		c.url = url
+	}
+}
+
+// WithHTTPClient sends every request through the given http client
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		if client != nil {
+			c.client = client
+		}
+	}
+}
+
+// WithTransport sends every request through the given round tripper, ex. a
+// proxying transport. The http client in use is copied, never modified
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		client := *c.client
+		client.Transport = transport
+		c.client = &client
+	}
+}
+
+// WithTimeout bounds each request attempt to the given duration, retries
+// get a fresh timeout. A timeout of 0 leaves attempts bounded only by the
+// context of the call and the http client
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithHeader adds a header sent with every request
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithAPIKey authenticates every request with the given compound api key
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.header.Set("compound-api-key", key)
+	}
+}
+
+// WithUserAgent overrides the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.header.Set("User-Agent", userAgent)
+	}
+}
+
+// WithRequestHook registers a hook called before every request attempt
+func WithRequestHook(hook RequestHook) Option {
+	return func(c *Client) {
+		c.requestHooks = append(c.requestHooks, hook)
+	}
+}
+
+// WithResponseHook registers a hook called after every request attempt
+func WithResponseHook(hook ResponseHook) Option {
+	return func(c *Client) {
+		c.responseHooks = append(c.responseHooks, hook)
+	}
+}
+
+// WithCache caches successful GET responses for ttl, keyed by their url
+func WithCache(c *cache.Cache, ttl time.Duration) Option {
+	return func(cl *Client) {
+		cl.cache = c
+		cl.cacheTTL = ttl
+	}
+}
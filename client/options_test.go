@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingTransport struct {
+	calls int32
+	next  http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.calls, 1)
+	return t.next.RoundTrip(req)
+}
+
+func Test_Options_Headers(t *testing.T) {
+	var gets, posts http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			gets = r.Header.Clone()
+		} else {
+			posts = r.Header.Clone()
+		}
+		w.Write([]byte(`{"accounts": []}`))
+	}))
+	defer srv.Close()
+
+	cl := NewClient("http://localhost:1",
+		WithBaseURL(srv.URL),
+		WithAPIKey("secret"),
+		WithUserAgent("gcomp-test"),
+		WithHeader("X-Trace", "a"),
+		WithHeader("X-Trace", "b"),
+	)
+	_, err := cl.GetAccountContext(context.Background(), account)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", gets.Get("compound-api-key"))
+	assert.Equal(t, "gcomp-test", gets.Get("User-Agent"))
+	assert.Equal(t, []string{"a", "b"}, gets.Values("X-Trace"))
+	assert.Empty(t, gets.Get("content-type"))
+
+	addresses := make([]string, maxQueryAddresses+1)
+	for i := range addresses {
+		addresses[i] = account
+	}
+	_, err = cl.QueryAccounts(context.Background(), NewAccountRequest().Addresses(addresses...))
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", posts.Get("content-type"))
+	assert.Equal(t, "secret", posts.Get("compound-api-key"))
+}
+
+func Test_Options_TransportAndHooks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "hooked", r.Header.Get("X-Hook"))
+		w.Write([]byte(`{"cToken": []}`))
+	}))
+	defer srv.Close()
+
+	base := &http.Client{}
+	transport := &countingTransport{next: http.DefaultTransport}
+	var statuses []int
+	cl := NewClient(srv.URL,
+		WithHTTPClient(base),
+		WithTransport(transport),
+		WithRequestHook(func(req *http.Request) { req.Header.Set("X-Hook", "hooked") }),
+		WithResponseHook(func(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+			require.NoError(t, err)
+			statuses = append(statuses, resp.StatusCode)
+		}),
+	)
+	_, err := cl.GetCTokensContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), transport.calls)
+	assert.Equal(t, []int{http.StatusOK}, statuses)
+	// the supplied client is left untouched
+	assert.Nil(t, base.Transport)
+}
+
+func Test_Options_Timeout(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(time.Millisecond * 200)
+		}
+		w.Write([]byte(`{"cToken": []}`))
+	}))
+	defer srv.Close()
+
+	// the first attempt times out, the retry gets a fresh timeout
+	cl := NewClient(srv.URL, WithTimeout(time.Millisecond*50), WithRetryPolicy(fastRetry))
+	_, err := cl.GetCTokensContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
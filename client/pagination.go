@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// DefaultPageConcurrency is the number of pages fetched in parallel
+// when a concurrency of 0 or lower is given to a page iterator
+const DefaultPageConcurrency = 4
+
+// Page is a single page delivered by a page iterator such as AccountPages.
+// Exactly one of Response and Err is set
+type Page[T any] struct {
+	// the page number, starting at 1
+	Number uint32
+	// the decoded api response for the page
+	Response T
+	// the error encountered while fetching the page
+	Err error
+}
+
+// PageError records the failure to fetch a single page
+type PageError struct {
+	Number uint32
+	Err    error
+}
+
+func (e *PageError) Error() string {
+	return fmt.Sprintf("page %d: %s", e.Number, e.Err)
+}
+
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+// PageErrors is returned by functions collecting every page when some
+// pages could not be fetched. The results of successful pages are still returned
+type PageErrors []*PageError
+
+func (e PageErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, pageErr := range e {
+		msgs = append(msgs, pageErr.Error())
+	}
+	return fmt.Sprintf("failed to fetch %d page(s): %s", len(e), strings.Join(msgs, "; "))
+}
+
+// pageFetcher fetches a single page, returning the response along with the
+// total number of pages reported by the api
+type pageFetcher[T any] func(ctx context.Context, number uint32) (T, uint32, error)
+
+// walkPages fetches the start page to learn the total number of pages, then
+// fetches the remaining pages with a pool of concurrency workers. Pages are
+// delivered in completion order, and the channel is closed once every page
+// has been delivered or the context is cancelled
+func walkPages[T any](ctx context.Context, start uint32, concurrency int, fetch pageFetcher[T]) <-chan Page[T] {
+	if start == 0 {
+		start = 1
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultPageConcurrency
+	}
+	out := make(chan Page[T], concurrency)
+	deliver := func(page Page[T]) bool {
+		select {
+		case out <- page:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	go func() {
+		defer close(out)
+		resp, totalPages, err := fetch(ctx, start)
+		if err != nil {
+			deliver(Page[T]{Number: start, Err: err})
+			return
+		}
+		if !deliver(Page[T]{Number: start, Response: resp}) {
+			return
+		}
+		jobs := make(chan uint32)
+		wg := &sync.WaitGroup{}
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for number := range jobs {
+					resp, _, err := fetch(ctx, number)
+					if ctx.Err() != nil {
+						return
+					}
+					if !deliver(Page[T]{Number: number, Response: resp, Err: err}) {
+						return
+					}
+				}
+			}()
+		}
+	loop:
+		for number := start + 1; number <= totalPages; number++ {
+			select {
+			case jobs <- number:
+			case <-ctx.Done():
+				break loop
+			}
+		}
+		close(jobs)
+		wg.Wait()
+	}()
+	return out
+}
+
+// AccountPages walks every page of accounts matching req, starting at the
+// request's page number. Pages after the first are fetched concurrently, and
+// a failed page is delivered with its error set without stopping the walk
+func (c *Client) AccountPages(ctx context.Context, req *AccountRequest, concurrency int) <-chan Page[*models.AccountResponse] {
+	if req == nil {
+		req = NewAccountRequest()
+	}
+	return walkPages(ctx, req.pageNumber, concurrency, func(ctx context.Context, number uint32) (*models.AccountResponse, uint32, error) {
+		pageReq := *req
+		pageReq.pageNumber = number
+		resp, err := c.QueryAccounts(ctx, &pageReq)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, uint32(resp.PaginationSummary.TotalPages), nil
+	})
+}
+
+// AllAccounts collects the accounts of every page matching req, ordered by page.
+// If some pages fail, the accounts of the other pages are returned along with
+// a PageErrors error
+func (c *Client) AllAccounts(ctx context.Context, req *AccountRequest, concurrency int) ([]models.Account, error) {
+	pages, err := collectPages(ctx, c.AccountPages(ctx, req, concurrency))
+	var accounts []models.Account
+	for _, page := range pages {
+		accounts = append(accounts, page.Response.Accounts...)
+	}
+	return accounts, err
+}
+
+// collectPages drains a page iterator, returning the successful pages
+// ordered by page number along with the errors of the failed ones
+func collectPages[T any](ctx context.Context, in <-chan Page[T]) ([]Page[T], error) {
+	var (
+		pages   []Page[T]
+		pageErr PageErrors
+	)
+	for page := range in {
+		if page.Err != nil {
+			pageErr = append(pageErr, &PageError{Number: page.Number, Err: page.Err})
+			continue
+		}
+		pages = append(pages, page)
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Number < pages[j].Number })
+	sort.Slice(pageErr, func(i, j int) bool { return pageErr[i].Number < pageErr[j].Number })
+	if err := ctx.Err(); err != nil {
+		return pages, err
+	}
+	if len(pageErr) > 0 {
+		return pages, pageErr
+	}
+	return pages, nil
+}
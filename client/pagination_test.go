@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedServer serves totalPages pages of one account each, failing the given page
+func pagedServer(t *testing.T, totalPages int, failPage int, inFlight, maxInFlight *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(inFlight, 1)
+		defer atomic.AddInt32(inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond * 5)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page_number"))
+		if page == failPage {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"accounts":[{"address":"0x%040x","health":{"value":"0.9"}}],
+			"pagination_summary":{"page_number":%d,"page_size":1,"total_entries":%d,"total_pages":%d}}`,
+			page, page, totalPages, totalPages)
+	}))
+}
+
+func Test_AllAccounts(t *testing.T) {
+	var inFlight, maxInFlight int32
+	srv := pagedServer(t, 12, -1, &inFlight, &maxInFlight)
+	defer srv.Close()
+
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+	accounts, err := cl.AllAccounts(context.Background(), NewAccountRequest().PageSize(1), 3)
+	require.NoError(t, err)
+	require.Len(t, accounts, 12)
+	for i, acct := range accounts {
+		assert.Equal(t, fmt.Sprintf("0x%040x", i+1), acct.Address)
+	}
+	assert.True(t, atomic.LoadInt32(&maxInFlight) <= 3)
+
+	liquidatable, err := cl.ScanLiquidatableAccounts(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Len(t, liquidatable, 12)
+}
+
+func Test_AllAccounts_PartialFailure(t *testing.T) {
+	var inFlight, maxInFlight int32
+	srv := pagedServer(t, 5, 3, &inFlight, &maxInFlight)
+	defer srv.Close()
+
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+	accounts, err := cl.AllAccounts(context.Background(), nil, 2)
+	assert.Len(t, accounts, 4)
+	var pageErrs PageErrors
+	require.True(t, errors.As(err, &pageErrs))
+	require.Len(t, pageErrs, 1)
+	assert.Equal(t, uint32(3), pageErrs[0].Number)
+	var httpErr *HTTPError
+	assert.True(t, errors.As(pageErrs[0], &httpErr))
+}
+
+func Test_AccountPages_Cancel(t *testing.T) {
+	var inFlight, maxInFlight int32
+	srv := pagedServer(t, 1000, -1, &inFlight, &maxInFlight)
+	defer srv.Close()
+
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var received int
+	for page := range cl.AccountPages(ctx, nil, 2) {
+		require.NoError(t, page.Err)
+		received++
+		if received == 3 {
+			cancel()
+		}
+	}
+	assert.True(t, received < 1000)
+}
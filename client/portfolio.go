@@ -0,0 +1,369 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/musinit/go-defi/v2/models"
+)
+
+const (
+	// DefaultWatchInterval is how often a PortfolioWatcher polls an account
+	// without an interval of its own
+	DefaultWatchInterval = time.Second * 15
+	// DefaultEventBuffer is the number of events a PortfolioWatcher buffers
+	// before dropping new ones
+	DefaultEventBuffer = 64
+)
+
+// DefaultThresholds are the thresholds of accounts added without their own
+var DefaultThresholds = Thresholds{
+	Warn:       models.MustParseDecimal("1.2"),
+	Risk:       models.NewDecimal(1),
+	Hysteresis: models.MustParseDecimal("0.02"),
+}
+
+// HealthState is the state of an account's health relative to its thresholds
+type HealthState int
+
+const (
+	// HealthUnknown is the state of an account before its first successful read
+	HealthUnknown HealthState = iota
+	// HealthSafe is above the warn threshold, or without any borrows
+	HealthSafe
+	// HealthWarn is at or below the warn threshold
+	HealthWarn
+	// HealthRisk is at or below the risk threshold
+	HealthRisk
+)
+
+// String returns the lowercase name of the state
+func (s HealthState) String() string {
+	switch s {
+	case HealthSafe:
+		return "safe"
+	case HealthWarn:
+		return "warn"
+	case HealthRisk:
+		return "risk"
+	default:
+		return "unknown"
+	}
+}
+
+// Thresholds are the health levels at which an account changes state. To
+// keep an account hovering around a threshold from flapping, an account only
+// leaves the warn or risk state once its health rises above the threshold
+// plus the Hysteresis
+type Thresholds struct {
+	Warn       models.Decimal
+	Risk       models.Decimal
+	Hysteresis models.Decimal
+}
+
+// State returns the state of an account with the given health, coming from
+// the previous state
+func (t Thresholds) State(previous HealthState, health models.Decimal) HealthState {
+	risk, warn := t.Risk, t.Warn
+	if previous == HealthRisk {
+		risk = risk.Add(t.Hysteresis)
+	}
+	if previous == HealthRisk || previous == HealthWarn {
+		warn = warn.Add(t.Hysteresis)
+	}
+	switch {
+	case health.Cmp(risk) <= 0:
+		return HealthRisk
+	case health.Cmp(warn) <= 0:
+		return HealthWarn
+	default:
+		return HealthSafe
+	}
+}
+
+// WatchedAccount is an account watched by a PortfolioWatcher, a nil
+// Thresholds or zero Interval use the watcher's defaults
+type WatchedAccount struct {
+	Address    string
+	Thresholds *Thresholds
+	Interval   time.Duration
+}
+
+// HealthEvent is emitted by a PortfolioWatcher when an account changes
+// state, or when reading it fails, in which case Err is set and the state
+// and health are left as they were
+type HealthEvent struct {
+	Address        string
+	Health         models.Decimal
+	PreviousHealth models.Decimal
+	State          HealthState
+	PreviousState  HealthState
+	Time           time.Time
+	// Source names where the account was read from, ex. api or chain
+	Source string
+	Err    error
+}
+
+// Transition returns whether the event is a change of state
+func (e HealthEvent) Transition() bool {
+	return e.Err == nil && e.State != e.PreviousState
+}
+
+// String returns a one line description of the event, suitable for logging
+func (e HealthEvent) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("account %s read from %s failed: %s", e.Address, e.Source, e.Err)
+	}
+	return fmt.Sprintf("account %s health %s (was %s) %s -> %s", e.Address, e.Health, e.PreviousHealth, e.PreviousState, e.State)
+}
+
+// WatcherStats counts the events of a PortfolioWatcher, events are dropped
+// when the events channel is full
+type WatcherStats struct {
+	Delivered uint64
+	Dropped   uint64
+}
+
+// WatcherOption is used to configure a PortfolioWatcher
+type WatcherOption func(*PortfolioWatcher)
+
+// WithDefaultThresholds sets the thresholds of accounts added without their own
+func WithDefaultThresholds(thresholds Thresholds) WatcherOption {
+	return func(w *PortfolioWatcher) {
+		w.thresholds = thresholds
+	}
+}
+
+// WithDefaultInterval sets the polling interval of accounts added without their own
+func WithDefaultInterval(interval time.Duration) WatcherOption {
+	return func(w *PortfolioWatcher) {
+		w.interval = interval
+	}
+}
+
+// WithEventBuffer sets the size of the events channel
+func WithEventBuffer(size int) WatcherOption {
+	return func(w *PortfolioWatcher) {
+		w.events = make(chan HealthEvent, size)
+	}
+}
+
+// WithSourceName sets the Source of emitted events, by default it is api for
+// an APISource, chain for a ChainSource and the type of src otherwise
+func WithSourceName(name string) WatcherOption {
+	return func(w *PortfolioWatcher) {
+		w.source = name
+	}
+}
+
+// PortfolioWatcher is used to watch the health of any number of accounts,
+// each polled on its own interval against its own thresholds. Events are
+// delivered without blocking, when nobody reads them fast enough they are
+// dropped and counted in Stats
+type PortfolioWatcher struct {
+	src        AccountSource
+	source     string
+	thresholds Thresholds
+	interval   time.Duration
+	events     chan HealthEvent
+
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+
+	mu       sync.Mutex
+	ctx      context.Context
+	stopped  bool
+	wg       sync.WaitGroup
+	accounts map[string]*watchedAccount
+}
+
+type watchedAccount struct {
+	WatchedAccount
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	state  HealthState
+	health models.Decimal
+}
+
+// NewPortfolioWatcher returns a watcher reading accounts from src
+func NewPortfolioWatcher(src AccountSource, opts ...WatcherOption) *PortfolioWatcher {
+	w := &PortfolioWatcher{
+		src:        src,
+		source:     sourceName(src),
+		thresholds: DefaultThresholds,
+		interval:   DefaultWatchInterval,
+		events:     make(chan HealthEvent, DefaultEventBuffer),
+		accounts:   make(map[string]*watchedAccount),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+func sourceName(src AccountSource) string {
+	switch src.(type) {
+	case *APISource:
+		return "api"
+	case *ChainSource:
+		return "chain"
+	default:
+		return fmt.Sprintf("%T", src)
+	}
+}
+
+// Events returns the channel events are delivered on, it is closed once Run returns
+func (w *PortfolioWatcher) Events() <-chan HealthEvent {
+	return w.events
+}
+
+// Stats returns the number of delivered and dropped events
+func (w *PortfolioWatcher) Stats() WatcherStats {
+	return WatcherStats{Delivered: w.delivered.Load(), Dropped: w.dropped.Load()}
+}
+
+// Add starts watching an account, replacing any previous settings for the
+// same address. Accounts can be added before or while the watcher runs
+func (w *PortfolioWatcher) Add(accounts ...WatchedAccount) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, acct := range accounts {
+		if acct.Thresholds == nil {
+			thresholds := w.thresholds
+			acct.Thresholds = &thresholds
+		}
+		if acct.Interval <= 0 {
+			acct.Interval = w.interval
+		}
+		key := strings.ToLower(acct.Address)
+		if old, ok := w.accounts[key]; ok && old.cancel != nil {
+			old.cancel()
+		}
+		watched := &watchedAccount{WatchedAccount: acct}
+		w.accounts[key] = watched
+		if w.ctx != nil && !w.stopped {
+			w.start(watched)
+		}
+	}
+}
+
+// Remove stops watching the given accounts
+func (w *PortfolioWatcher) Remove(addresses ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, address := range addresses {
+		key := strings.ToLower(address)
+		if watched, ok := w.accounts[key]; ok {
+			if watched.cancel != nil {
+				watched.cancel()
+			}
+			delete(w.accounts, key)
+		}
+	}
+}
+
+// State returns the last known state and health of an account
+func (w *PortfolioWatcher) State(address string) (HealthState, models.Decimal) {
+	w.mu.Lock()
+	watched, ok := w.accounts[strings.ToLower(address)]
+	w.mu.Unlock()
+	if !ok {
+		return HealthUnknown, models.Decimal{}
+	}
+	watched.mu.Lock()
+	defer watched.mu.Unlock()
+	return watched.state, watched.health
+}
+
+// Run watches the accounts until ctx is done, and then closes the events
+// channel. A watcher can only be run once
+func (w *PortfolioWatcher) Run(ctx context.Context) error {
+	w.mu.Lock()
+	if w.ctx != nil {
+		w.mu.Unlock()
+		return errors.New("watcher is already running")
+	}
+	w.ctx = ctx
+	for _, watched := range w.accounts {
+		w.start(watched)
+	}
+	w.mu.Unlock()
+
+	<-ctx.Done()
+	w.mu.Lock()
+	w.stopped = true
+	w.mu.Unlock()
+	w.wg.Wait()
+	close(w.events)
+	return nil
+}
+
+// start polls an account in the background, w.mu must be held
+func (w *PortfolioWatcher) start(watched *watchedAccount) {
+	ctx, cancel := context.WithCancel(w.ctx)
+	watched.cancel = cancel
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(watched.Interval)
+		defer ticker.Stop()
+		for {
+			w.poll(ctx, watched)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// poll reads an account and emits an event if its state changed
+func (w *PortfolioWatcher) poll(ctx context.Context, watched *watchedAccount) {
+	acct, err := w.src.Account(ctx, watched.Address)
+	if ctx.Err() != nil {
+		// the account was removed or the watcher stopped during the read
+		return
+	}
+	watched.mu.Lock()
+	event := HealthEvent{
+		Address:        watched.Address,
+		Health:         watched.health,
+		PreviousHealth: watched.health,
+		State:          watched.state,
+		PreviousState:  watched.state,
+		Time:           time.Now(),
+		Source:         w.source,
+		Err:            err,
+	}
+	if err == nil {
+		event.Health = acct.Health.Value
+		if acct.TotalBorrowValueInEth.Value.Sign() == 0 {
+			// an account without borrows can not be liquidated
+			event.State = HealthSafe
+		} else {
+			event.State = watched.Thresholds.State(watched.state, event.Health)
+		}
+		watched.state, watched.health = event.State, event.Health
+	}
+	watched.mu.Unlock()
+	if err != nil || event.Transition() {
+		w.emit(event)
+	}
+}
+
+// emit delivers an event without blocking
+func (w *PortfolioWatcher) emit(event HealthEvent) {
+	select {
+	case w.events <- event:
+		w.delivered.Add(1)
+	default:
+		w.dropped.Add(1)
+	}
+}
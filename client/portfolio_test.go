@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/musinit/go-defi/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthSource is an AccountSource where every account borrows, with a
+// health set by the test
+type healthSource struct {
+	mu     sync.Mutex
+	health map[string]models.Decimal
+	err    error
+}
+
+func (s *healthSource) set(address, health string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health[strings.ToLower(address)] = models.MustParseDecimal(health)
+}
+
+func (s *healthSource) Account(ctx context.Context, address string) (*models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	health, ok := s.health[strings.ToLower(address)]
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+	return &models.Account{
+		Address:               address,
+		Health:                models.Value{Value: health},
+		TotalBorrowValueInEth: models.Value{Value: models.NewDecimal(1)},
+	}, nil
+}
+
+func (s *healthSource) AtRisk(ctx context.Context, maxHealth models.Decimal) ([]models.Account, error) {
+	return nil, nil
+}
+
+func Test_Thresholds(t *testing.T) {
+	th := DefaultThresholds
+	for _, tt := range []struct {
+		previous HealthState
+		health   string
+		want     HealthState
+	}{
+		{HealthUnknown, "1.5", HealthSafe},
+		{HealthUnknown, "1.2", HealthWarn},
+		{HealthSafe, "1", HealthRisk},
+		{HealthRisk, "1.01", HealthRisk},
+		{HealthRisk, "1.03", HealthWarn},
+		{HealthWarn, "1.21", HealthWarn},
+		{HealthWarn, "1.23", HealthSafe},
+		{HealthSafe, "1.21", HealthSafe},
+	} {
+		assert.Equal(t, tt.want, th.State(tt.previous, models.MustParseDecimal(tt.health)), "%s from %s", tt.health, tt.previous)
+	}
+}
+
+func nextEvent(t *testing.T, w *PortfolioWatcher) HealthEvent {
+	t.Helper()
+	select {
+	case event := <-w.Events():
+		return event
+	case <-time.After(time.Second * 5):
+		t.Fatal("no event")
+		return HealthEvent{}
+	}
+}
+
+func Test_PortfolioWatcher(t *testing.T) {
+	src := &healthSource{health: map[string]models.Decimal{}}
+	src.set(account, "1.5")
+	src.set(selfAccount, "3")
+	w := NewPortfolioWatcher(src, WithDefaultInterval(time.Millisecond*10), WithSourceName("test"))
+	w.Add(
+		WatchedAccount{Address: account},
+		WatchedAccount{Address: selfAccount, Thresholds: &Thresholds{Warn: models.NewDecimal(4), Risk: models.NewDecimal(2)}, Interval: time.Hour},
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	// the first read of each account is a transition out of unknown
+	states := map[string]HealthState{}
+	for i := 0; i < 2; i++ {
+		event := nextEvent(t, w)
+		assert.Equal(t, HealthUnknown, event.PreviousState)
+		assert.Equal(t, "test", event.Source)
+		states[event.Address] = event.State
+	}
+	assert.Equal(t, HealthSafe, states[account])
+	assert.Equal(t, HealthWarn, states[selfAccount])
+
+	src.set(account, "0.99")
+	event := nextEvent(t, w)
+	assert.Equal(t, account, event.Address)
+	assert.Equal(t, HealthRisk, event.State)
+	assert.Equal(t, HealthSafe, event.PreviousState)
+	assert.Equal(t, "0.99", event.Health.String())
+	assert.Equal(t, "1.5", event.PreviousHealth.String())
+
+	// recovering within the hysteresis does not leave the risk state
+	src.set(account, "1.01")
+	time.Sleep(time.Millisecond * 50)
+	state, health := w.State(account)
+	assert.Equal(t, HealthRisk, state)
+	assert.Equal(t, "1.01", health.String())
+	src.set(account, "1.1")
+	event = nextEvent(t, w)
+	assert.Equal(t, HealthWarn, event.State)
+
+	src.mu.Lock()
+	src.err = errors.New("node down")
+	src.mu.Unlock()
+	event = nextEvent(t, w)
+	require.Error(t, event.Err)
+	assert.False(t, event.Transition())
+	assert.Equal(t, HealthWarn, event.State)
+
+	cancel()
+	require.NoError(t, <-done)
+	for range w.Events() {
+	}
+	assert.NotZero(t, w.Stats().Delivered)
+}
+
+func Test_PortfolioWatcher_Drops(t *testing.T) {
+	src := &healthSource{health: map[string]models.Decimal{}, err: errors.New("node down")}
+	w := NewPortfolioWatcher(src, WithDefaultInterval(time.Millisecond), WithEventBuffer(1))
+	w.Add(WatchedAccount{Address: account})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	require.Eventually(t, func() bool { return w.Stats().Dropped > 0 }, time.Second*5, time.Millisecond)
+	assert.Equal(t, uint64(1), w.Stats().Delivered)
+
+	w.Remove(account)
+	state, _ := w.State(account)
+	assert.Equal(t, HealthUnknown, state)
+	cancel()
+	require.NoError(t, <-done)
+	assert.Error(t, w.Run(context.Background()))
+}
@@ -0,0 +1,226 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// maxQueryAddresses is the largest number of addresses sent in a GET query
+// string, requests for more addresses use the post_account form instead
+const maxQueryAddresses = 50
+
+// AccountRequest is used to build a query against the account api.
+// It exposes every filter of the AccountRequest message in pb/account.proto,
+// ex. all accounts with a health below 1.1 borrowing more than 1 eth:
+//
+//	req := NewAccountRequest().MaxHealth("1.1").MinBorrowValueInEth("1")
+//	resp, err := client.QueryAccounts(ctx, req)
+type AccountRequest struct {
+	addresses           []string
+	minBorrowValueInEth string
+	maxHealth           string
+	blockNumber         uint32
+	blockTimestamp      uint32
+	pageSize            uint32
+	pageNumber          uint32
+}
+
+// NewAccountRequest returns an empty request, which matches every account
+func NewAccountRequest() *AccountRequest {
+	return &AccountRequest{}
+}
+
+// Addresses restricts the request to the given account addresses
+func (r *AccountRequest) Addresses(addresses ...string) *AccountRequest {
+	r.addresses = append(r.addresses, addresses...)
+	return r
+}
+
+// MinBorrowValueInEth filters for accounts whose total outstanding borrows
+// exceed the given amount of eth, ex. "0.002"
+func (r *AccountRequest) MinBorrowValueInEth(value string) *AccountRequest {
+	r.minBorrowValueInEth = value
+	return r
+}
+
+// MaxHealth filters for accounts with a health below the given value, ex. "1.0"
+func (r *AccountRequest) MaxHealth(value string) *AccountRequest {
+	r.maxHealth = value
+	return r
+}
+
+// BlockNumber requests historical data as of the given block
+func (r *AccountRequest) BlockNumber(number uint32) *AccountRequest {
+	r.blockNumber = number
+	return r
+}
+
+// BlockTimestamp requests historical data as of the given unix timestamp
+func (r *AccountRequest) BlockTimestamp(timestamp uint32) *AccountRequest {
+	r.blockTimestamp = timestamp
+	return r
+}
+
+// PageSize sets the number of accounts per page, the api defaults to 10
+func (r *AccountRequest) PageSize(size uint32) *AccountRequest {
+	r.pageSize = size
+	return r
+}
+
+// PageNumber sets the page to return, pages start at 1
+func (r *AccountRequest) PageNumber(number uint32) *AccountRequest {
+	r.pageNumber = number
+	return r
+}
+
+// Validate checks the addresses and numeric filters of the request
+func (r *AccountRequest) Validate() error {
+	for _, address := range r.addresses {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid account address %q", address)
+		}
+	}
+	if err := validatePrecise("min_borrow_value_in_eth", r.minBorrowValueInEth); err != nil {
+		return err
+	}
+	if err := validatePrecise("max_health", r.maxHealth); err != nil {
+		return err
+	}
+	if r.blockNumber != 0 && r.blockTimestamp != 0 {
+		return errors.New("only one of block_number or block_timestamp may be set")
+	}
+	return nil
+}
+
+// Query encodes the request as GET query parameters, Precise values
+// are encoded with the nested form used by the api, ex. max_health[value]=1.0
+func (r *AccountRequest) Query() url.Values {
+	query := url.Values{}
+	for _, address := range r.addresses {
+		query.Add("addresses[]", address)
+	}
+	if r.minBorrowValueInEth != "" {
+		query.Set("min_borrow_value_in_eth[value]", r.minBorrowValueInEth)
+	}
+	if r.maxHealth != "" {
+		query.Set("max_health[value]", r.maxHealth)
+	}
+	setUint(query, "block_number", r.blockNumber)
+	setUint(query, "block_timestamp", r.blockTimestamp)
+	setUint(query, "page_size", r.pageSize)
+	setUint(query, "page_number", r.pageNumber)
+	return query
+}
+
+// MarshalJSON encodes the request as the json body of a post_account call
+func (r *AccountRequest) MarshalJSON() ([]byte, error) {
+	minBorrowValueInEth, err := preciseOrNil("min_borrow_value_in_eth", r.minBorrowValueInEth)
+	if err != nil {
+		return nil, err
+	}
+	maxHealth, err := preciseOrNil("max_health", r.maxHealth)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(accountRequestBody{
+		Addresses:           r.addresses,
+		MinBorrowValueInEth: minBorrowValueInEth,
+		MaxHealth:           maxHealth,
+		BlockNumber:         r.blockNumber,
+		BlockTimestamp:      r.blockTimestamp,
+		PageSize:            r.pageSize,
+		PageNumber:          r.pageNumber,
+	})
+}
+
+// usePost reports whether the request is too large for a query string
+func (r *AccountRequest) usePost() bool {
+	return len(r.addresses) > maxQueryAddresses
+}
+
+type accountRequestBody struct {
+	Addresses           []string      `json:"addresses,omitempty"`
+	MinBorrowValueInEth *models.Value `json:"min_borrow_value_in_eth,omitempty"`
+	MaxHealth           *models.Value `json:"max_health,omitempty"`
+	BlockNumber         uint32        `json:"block_number,omitempty"`
+	BlockTimestamp      uint32        `json:"block_timestamp,omitempty"`
+	PageSize            uint32        `json:"page_size,omitempty"`
+	PageNumber          uint32        `json:"page_number,omitempty"`
+}
+
+// QueryAccounts retrieves the accounts matching the given request. Requests
+// for many addresses are sent as a post_account call, everything else uses GET
+func (c *Client) QueryAccounts(ctx context.Context, req *AccountRequest) (*models.AccountResponse, error) {
+	if req == nil {
+		req = NewAccountRequest()
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	response := &models.AccountResponse{}
+	if req.usePost() {
+		body, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.postResponse(ctx, c.endpoint("/account", nil), body, response); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+	if err := c.getResponse(ctx, c.endpoint("/account", req.Query()), response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func validatePrecise(name, value string) error {
+	_, err := preciseOrNil(name, value)
+	return err
+}
+
+// preciseOrNil parses an optional non-negative Precise filter
+func preciseOrNil(name, value string) (*models.Value, error) {
+	if value == "" {
+		return nil, nil
+	}
+	number, err := models.ParseDecimal(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q is not a number", name, value)
+	}
+	if number.Sign() < 0 {
+		return nil, fmt.Errorf("%s %q must not be negative", name, value)
+	}
+	return &models.Value{Value: number}, nil
+}
+
+func setUint(query url.Values, key string, value uint32) {
+	if value != 0 {
+		query.Set(key, strconv.FormatUint(uint64(value), 10))
+	}
+}
+
+// parsePagination converts the string pagination parameters used by
+// GetAccounts, treating empty strings as unset
+func parsePagination(pageSize, pageNum string) (uint32, uint32, error) {
+	var size, num uint64
+	var err error
+	if pageSize != "" {
+		if size, err = strconv.ParseUint(pageSize, 10, 32); err != nil {
+			return 0, 0, fmt.Errorf("invalid page size %q", pageSize)
+		}
+	}
+	if pageNum != "" {
+		if num, err = strconv.ParseUint(pageNum, 10, 32); err != nil {
+			return 0, 0, fmt.Errorf("invalid page number %q", pageNum)
+		}
+	}
+	return uint32(size), uint32(num), nil
+}
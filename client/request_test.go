@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AccountRequest_Query(t *testing.T) {
+	req := NewAccountRequest().
+		Addresses(account, selfAccount).
+		MinBorrowValueInEth("1").
+		MaxHealth("1.1").
+		BlockNumber(9000000).
+		PageSize(100).
+		PageNumber(2)
+	require.NoError(t, req.Validate())
+
+	query := req.Query()
+	assert.Equal(t, []string{account, selfAccount}, query["addresses[]"])
+	assert.Equal(t, "1", query.Get("min_borrow_value_in_eth[value]"))
+	assert.Equal(t, "1.1", query.Get("max_health[value]"))
+	assert.Equal(t, "9000000", query.Get("block_number"))
+	assert.Equal(t, "", query.Get("block_timestamp"))
+	assert.Equal(t, "100", query.Get("page_size"))
+	assert.Equal(t, "2", query.Get("page_number"))
+
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{
+		"addresses": [%q, %q],
+		"min_borrow_value_in_eth": {"value": "1"},
+		"max_health": {"value": "1.1"},
+		"block_number": 9000000,
+		"page_size": 100,
+		"page_number": 2
+	}`, account, selfAccount), string(body))
+}
+
+func Test_AccountRequest_Validate(t *testing.T) {
+	assert.Error(t, NewAccountRequest().Addresses("0xnope").Validate())
+	assert.Error(t, NewAccountRequest().MaxHealth("one").Validate())
+	assert.Error(t, NewAccountRequest().MinBorrowValueInEth("-1").Validate())
+	assert.Error(t, NewAccountRequest().BlockNumber(1).BlockTimestamp(1).Validate())
+	assert.NoError(t, NewAccountRequest().Validate())
+}
+
+func Test_QueryAccounts_PostsLargeRequests(t *testing.T) {
+	var (
+		method string
+		body   []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		body, _ = ioutil.ReadAll(r.Body)
+		w.Write([]byte(`{"accounts":[]}`))
+	}))
+	defer srv.Close()
+	cl := NewClient(srv.URL, WithRetryPolicy(fastRetry))
+
+	_, err := cl.QueryAccounts(context.Background(), NewAccountRequest().Addresses(account))
+	require.NoError(t, err)
+	assert.Equal(t, "GET", method)
+
+	req := NewAccountRequest().MaxHealth("1.0")
+	for i := 0; i <= maxQueryAddresses; i++ {
+		req.Addresses(fmt.Sprintf("0x%040x", i))
+	}
+	_, err = cl.QueryAccounts(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "POST", method)
+	var decoded accountRequestBody
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Len(t, decoded.Addresses, maxQueryAddresses+1)
+	assert.Equal(t, "1", decoded.MaxHealth.Value.String())
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	comptroller "github.com/musinit/go-defi/v2/bindings/comptroller"
+	oracle "github.com/musinit/go-defi/v2/bindings/price_oracle"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// MarketSnapshot is the position of an account in a single market
+type MarketSnapshot struct {
+	Market Market
+	// CTokenBalance is in ctokens, Supplied and Borrowed in the underlying token
+	CTokenBalance models.Decimal
+	Supplied      models.Decimal
+	Borrowed      models.Decimal
+	// ExchangeRate is the amount of the underlying token a ctoken is worth
+	ExchangeRate     models.Decimal
+	CollateralFactor models.Decimal
+	// Price is the oracle price of the underlying token, and the values are
+	// in the unit of the oracle. CollateralValue is the supply value
+	// weighted by the collateral factor
+	Price           models.Decimal
+	SupplyValue     models.Decimal
+	BorrowValue     models.Decimal
+	CollateralValue models.Decimal
+}
+
+// AccountSnapshot is the position of an account across every market it
+// entered, read at a single block
+type AccountSnapshot struct {
+	Account Address
+	Block   uint64
+	Markets []MarketSnapshot
+	// SupplyValue, BorrowValue and CollateralValue are the totals of the
+	// markets, in the unit of the oracle
+	SupplyValue     models.Decimal
+	BorrowValue     models.Decimal
+	CollateralValue models.Decimal
+}
+
+// Health returns the collateral value over the borrow value, an account
+// with a health below 1 can be liquidated. Accounts without borrows have a
+// health of 0
+func (s *AccountSnapshot) Health() models.Decimal {
+	if s.BorrowValue.Sign() == 0 {
+		return models.Decimal{}
+	}
+	return s.CollateralValue.Quo(s.BorrowValue).Round(18)
+}
+
+// GetAccountSnapshot returns the position of the account in every market it
+// entered through the comptroller of the client, read at the latest block.
+// Markets missing from the registry, or without their decimals, are
+// discovered and registered
+func (bc *BClient) GetAccountSnapshot(ctx context.Context, address Address) (*AccountSnapshot, error) {
+	block, err := bc.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(block)}
+	contract, err := comptroller.NewBindings(bc.comptroller.EthAddress(), bc.client)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := contract.GetAssetsIn(opts, address.EthAddress())
+	if err != nil {
+		return nil, err
+	}
+	oracleAddress, err := contract.Oracle(opts)
+	if err != nil {
+		return nil, err
+	}
+	priceOracle, err := oracle.NewBindings(oracleAddress, bc.client)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &AccountSnapshot{Account: address, Block: block, Markets: make([]MarketSnapshot, 0, len(assets))}
+	for _, asset := range assets {
+		m, err := bc.marketSnapshot(opts, contract, priceOracle, address.EthAddress(), asset)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Markets = append(snapshot.Markets, *m)
+		snapshot.SupplyValue = snapshot.SupplyValue.Add(m.SupplyValue)
+		snapshot.BorrowValue = snapshot.BorrowValue.Add(m.BorrowValue)
+		snapshot.CollateralValue = snapshot.CollateralValue.Add(m.CollateralValue)
+	}
+	return snapshot, nil
+}
+
+// marketSnapshot reads the position of account in the market at address
+func (bc *BClient) marketSnapshot(opts *bind.CallOpts, contract *comptroller.Bindings, priceOracle *oracle.Bindings, account, address common.Address) (*MarketSnapshot, error) {
+	market, err := bc.snapshotMarket(opts, contract, address)
+	if err != nil {
+		return nil, err
+	}
+	ctoken, err := NewCToken(market, bc.client)
+	if err != nil {
+		return nil, err
+	}
+	errCode, tokens, borrowed, rate, err := ctoken.GetAccountSnapshot(opts, account)
+	if err != nil {
+		return nil, fmt.Errorf("%s snapshot: %w", market.Symbol, err)
+	}
+	if errCode.Sign() != 0 {
+		return nil, fmt.Errorf("%s snapshot returned error code %s", market.Symbol, errCode)
+	}
+	listing, err := contract.Markets(opts, address)
+	if err != nil {
+		return nil, fmt.Errorf("%s listing: %w", market.Symbol, err)
+	}
+	price, err := priceOracle.GetUnderlyingPrice(opts, address)
+	if err != nil {
+		return nil, fmt.Errorf("%s price: %w", market.Symbol, err)
+	}
+	decimals, underlyingDecimals := int(market.Decimals), int(market.UnderlyingDecimals)
+	m := &MarketSnapshot{
+		Market:        market,
+		CTokenBalance: models.NewDecimalFromInt(tokens, decimals),
+		Borrowed:      models.NewDecimalFromInt(borrowed, underlyingDecimals),
+		// the exchange rate is scaled by 1e(18 + underlying decimals - decimals)
+		ExchangeRate:     models.NewDecimalFromInt(rate, 18+underlyingDecimals-decimals),
+		CollateralFactor: models.NewDecimalFromInt(listing.CollateralFactorMantissa, 18),
+		// the price is scaled by 1e(36 - underlying decimals)
+		Price: models.NewDecimalFromInt(price, 36-underlyingDecimals),
+	}
+	m.Supplied = m.CTokenBalance.Mul(m.ExchangeRate)
+	m.SupplyValue = m.Supplied.Mul(m.Price)
+	m.BorrowValue = m.Borrowed.Mul(m.Price)
+	m.CollateralValue = m.SupplyValue.Mul(m.CollateralFactor)
+	return m, nil
+}
+
+// snapshotMarket returns the registered market at address, discovering and
+// registering it when it is unknown or its decimals are not known. Ether
+// markets are only known as such once registered
+func (bc *BClient) snapshotMarket(opts *bind.CallOpts, contract *comptroller.Bindings, address common.Address) (Market, error) {
+	market, err := bc.markets.Market(Address(address.Hex()))
+	if err == nil && market.Decimals != 0 && market.UnderlyingDecimals != 0 {
+		return market, nil
+	}
+	var ether common.Address
+	if err == nil && market.Ether {
+		ether = address
+	}
+	chainID := market.ChainID
+	if err != nil {
+		id, err := bc.client.ChainID(opts.Context)
+		if err != nil {
+			return Market{}, err
+		}
+		chainID = id.Int64()
+	}
+	discovered, err := discoverMarket(opts, bc.client, contract, address, ether)
+	if err != nil {
+		return Market{}, err
+	}
+	discovered.ChainID = chainID
+	bc.markets.Register(discovered)
+	return discovered, nil
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	cerc20 "github.com/musinit/go-defi/v2/bindings/cbat"
+	comptroller "github.com/musinit/go-defi/v2/bindings/comptroller"
+	oracle "github.com/musinit/go-defi/v2/bindings/price_oracle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetAccountSnapshot(t *testing.T) {
+	var (
+		comptrollerAddress = common.HexToAddress("0xc0")
+		oracleAddress      = common.HexToAddress("0xe0")
+		cETH               = CompoundETH.EthAddress()
+		cUSDT              = common.HexToAddress("0xc2")
+		usdt               = common.HexToAddress("0xd2")
+		account            = common.HexToAddress("0xa1")
+		mantissa           = func(m string) *big.Int { v, _ := new(big.Int).SetString(m, 10); return v }
+	)
+	node := newContractNode(t, map[common.Address]map[string][]interface{}{
+		comptrollerAddress: {
+			"getAssetsIn": {[]common.Address{cETH, cUSDT}},
+			"oracle":      {oracleAddress},
+		},
+		cETH: {
+			"symbol":   {"cETH"},
+			"decimals": {big.NewInt(8)},
+			// 50 cETH at 0.02 ETH each
+			"getAccountSnapshot": {new(big.Int), big.NewInt(50e8), new(big.Int), mantissa("200000000000000000000000000")},
+		},
+		cUSDT: {
+			"symbol":     {"cUSDT"},
+			"decimals":   {big.NewInt(8)},
+			"underlying": {usdt},
+			// 1000 USDT borrowed
+			"getAccountSnapshot": {new(big.Int), new(big.Int), big.NewInt(1000e6), mantissa("200000000000000")},
+		},
+		usdt: {
+			"decimals": {big.NewInt(6)},
+		},
+	}, cerc20.BindingsABI, comptroller.BindingsABI, oracle.BindingsABI)
+	node.handlers["markets"] = func(to common.Address, in []interface{}) []interface{} {
+		if in[0].(common.Address) == cETH {
+			return []interface{}{true, mantissa("750000000000000000")}
+		}
+		return []interface{}{true, new(big.Int)}
+	}
+	node.handlers["getUnderlyingPrice"] = func(to common.Address, in []interface{}) []interface{} {
+		if in[0].(common.Address) == cETH {
+			return []interface{}{mantissa("2000000000000000000000")}
+		}
+		return []interface{}{mantissa("1000000000000000000000000000000")}
+	}
+	node.head = 42
+	bc := NewBClient(nil, node.dial(t), WithComptroller(Address(comptrollerAddress.Hex())))
+	ctx := context.Background()
+
+	snapshot, err := bc.GetAccountSnapshot(ctx, Address(account.Hex()))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), snapshot.Block)
+	require.Len(t, snapshot.Markets, 2)
+	eth, tether := snapshot.Markets[0], snapshot.Markets[1]
+	assert.True(t, eth.Market.Ether)
+	assert.Equal(t, "50", eth.CTokenBalance.String())
+	assert.Equal(t, "0.02", eth.ExchangeRate.String())
+	assert.Equal(t, "1", eth.Supplied.String())
+	assert.Equal(t, "2000", eth.Price.String())
+	assert.Equal(t, "0.75", eth.CollateralFactor.String())
+	assert.Equal(t, "1500", eth.CollateralValue.String())
+	assert.Equal(t, "cUSDT", tether.Market.Symbol)
+	assert.Equal(t, "1000", tether.Borrowed.String())
+	assert.Equal(t, "1", tether.Price.String())
+	assert.Equal(t, "2000", snapshot.SupplyValue.String())
+	assert.Equal(t, "1000", snapshot.BorrowValue.String())
+	assert.Equal(t, "1500", snapshot.CollateralValue.String())
+	assert.Equal(t, "1.5", snapshot.Health().String())
+
+	// unknown markets are discovered and registered
+	market, err := bc.Markets().Market(Address(cUSDT.Hex()))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(6), market.UnderlyingDecimals)
+	assert.Equal(t, int64(1337), market.ChainID)
+	market, err = bc.Markets().Market(CompoundETH)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), market.ChainID)
+
+	node.outputs[cUSDT]["getAccountSnapshot"] = []interface{}{big.NewInt(9), new(big.Int), new(big.Int), new(big.Int)}
+	_, err = bc.GetAccountSnapshot(ctx, Address(account.Hex()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cUSDT snapshot returned error code 9")
+
+	node.outputs[comptrollerAddress]["getAssetsIn"] = []interface{}{[]common.Address{}}
+	snapshot, err = bc.GetAccountSnapshot(ctx, Address(account.Hex()))
+	require.NoError(t, err)
+	assert.Empty(t, snapshot.Markets)
+	assert.True(t, snapshot.Health().IsZero())
+}
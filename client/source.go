@@ -0,0 +1,248 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/compound"
+	"github.com/musinit/go-defi/v2/internal/apiutil"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// ErrAccountNotFound is returned by an AccountSource for unknown accounts
+var ErrAccountNotFound = errors.New("account not found")
+
+// AccountSource is used to read account positions, health and per token
+// balances. The APISource reads them from the compound api, and the
+// ChainSource from the contracts, so that the watchers and helpers built on
+// an AccountSource run with or without the api
+type AccountSource interface {
+	// Account returns the position of a single account
+	Account(ctx context.Context, address string) (*models.Account, error)
+	// AtRisk returns the accounts with a health at or below maxHealth
+	AtRisk(ctx context.Context, maxHealth models.Decimal) ([]models.Account, error)
+}
+
+// MarketSource is used to read ctoken markets, ex. for their prices and
+// collateral factors. Both the APISource and the ChainSource implement it
+type MarketSource interface {
+	// CToken returns the market of the ctoken at address
+	CToken(ctx context.Context, address string) (*models.CToken, error)
+}
+
+// ErrMarketNotFound is returned by a MarketSource for unknown ctokens
+var ErrMarketNotFound = errors.New("market not found")
+
+// APISource is an AccountSource reading from the compound api
+type APISource struct {
+	client      *Client
+	concurrency int
+	read        readOptions
+}
+
+// Source returns an AccountSource reading through the client, scans for
+// accounts at risk fetch up to DefaultPageConcurrency pages in parallel.
+// Every read of the source is made as of the block or time of opts, so the
+// helpers taking a source, such as SupplyInterestEarned, can be pinned too
+func (c *Client) Source(opts ...ReadOption) *APISource {
+	return &APISource{client: c, concurrency: DefaultPageConcurrency, read: applyReadOptions(opts)}
+}
+
+// Account returns the position of a single account
+func (s *APISource) Account(ctx context.Context, address string) (*models.Account, error) {
+	resp, err := s.client.QueryAccounts(ctx, s.read.account(NewAccountRequest().Addresses(address)))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Accounts) == 0 {
+		return nil, ErrAccountNotFound
+	}
+	return &resp.Accounts[0], nil
+}
+
+// AtRisk returns the accounts with a health at or below maxHealth, walking
+// every page of the api. If some pages fail, the accounts found on the other
+// pages are returned along with a PageErrors error
+func (s *APISource) AtRisk(ctx context.Context, maxHealth models.Decimal) ([]models.Account, error) {
+	req := s.read.account(NewAccountRequest().MaxHealth(maxHealth.String()).PageSize(scanPageSize))
+	accounts, err := s.client.AllAccounts(ctx, req, s.concurrency)
+	return atRisk(accounts, maxHealth), err
+}
+
+// CToken returns the market of the ctoken at address
+func (s *APISource) CToken(ctx context.Context, address string) (*models.CToken, error) {
+	resp, err := s.client.QueryCTokens(ctx, s.read.ctoken(NewCTokenRequest().Addresses(address)))
+	if err != nil {
+		return nil, err
+	}
+	return findCToken(resp, address)
+}
+
+// ChainSource is an AccountSource reading the comptroller and ctoken
+// contracts through a compound.Reader. The chain does not track lifetime
+// interest, so those fields of the returned accounts are always 0, and
+// accounts can not be listed, so AtRisk only checks the accounts the source
+// was created with
+type ChainSource struct {
+	reader   *compound.Reader
+	accounts []string
+}
+
+// NewChainSource returns an AccountSource reading from backend, AtRisk
+// checks the given accounts
+func NewChainSource(backend compound.Backend, accounts ...string) *ChainSource {
+	return &ChainSource{reader: compound.NewReader(backend), accounts: accounts}
+}
+
+// AccountSource returns a ChainSource reading the compound deployment of the
+// client's comptroller through the client's connection, AtRisk checks the
+// given accounts. The ether market is only known for the Deployments
+func (bc *BClient) AccountSource(accounts ...string) (*ChainSource, error) {
+	var ether common.Address
+	for _, d := range Deployments {
+		if d.Comptroller.EthAddress() == bc.comptroller.EthAddress() {
+			ether = d.Ether.EthAddress()
+		}
+	}
+	backend, err := compound.NewChainBackend(bc.client, bc.comptroller.EthAddress(), ether)
+	if err != nil {
+		return nil, err
+	}
+	return NewChainSource(backend, accounts...), nil
+}
+
+// Account returns the position of a single account
+func (s *ChainSource) Account(ctx context.Context, address string) (*models.Account, error) {
+	if !common.IsHexAddress(address) {
+		return nil, errors.New("invalid account address")
+	}
+	st, err := s.reader.State(ctx, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.reader.Account(ctx, st, common.HexToAddress(address))
+}
+
+// CToken returns the market of the ctoken at address
+func (s *ChainSource) CToken(ctx context.Context, address string) (*models.CToken, error) {
+	if !common.IsHexAddress(address) {
+		return nil, errors.New("invalid ctoken address")
+	}
+	st, err := s.reader.State(ctx, 0, []string{address})
+	if err != nil {
+		return nil, err
+	}
+	return findCToken(&models.CTokenResponse{CToken: st.CTokens}, address)
+}
+
+func findCToken(resp *models.CTokenResponse, address string) (*models.CToken, error) {
+	for i := range resp.CToken {
+		if strings.EqualFold(resp.CToken[i].TokenAddress, address) {
+			return &resp.CToken[i], nil
+		}
+	}
+	return nil, ErrMarketNotFound
+}
+
+// AtRisk returns the accounts the source was created with that have a health
+// at or below maxHealth, read at a single block. Accounts without borrows have
+// no health and are skipped
+func (s *ChainSource) AtRisk(ctx context.Context, maxHealth models.Decimal) ([]models.Account, error) {
+	st, err := s.reader.State(ctx, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]models.Account, len(s.accounts))
+	err = apiutil.Parallel(ctx, len(s.accounts), compound.DefaultConcurrency, func(ctx context.Context, i int) error {
+		acct, err := s.reader.Account(ctx, st, common.HexToAddress(s.accounts[i]))
+		if err != nil {
+			return fmt.Errorf("account %s: %w", s.accounts[i], err)
+		}
+		accounts[i] = *acct
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var borrowing []models.Account
+	for _, acct := range accounts {
+		if !acct.TotalBorrowValueInEth.Value.IsZero() {
+			borrowing = append(borrowing, acct)
+		}
+	}
+	return atRisk(borrowing, maxHealth), nil
+}
+
+// atRisk filters accounts with a health at or below maxHealth
+func atRisk(accounts []models.Account, maxHealth models.Decimal) []models.Account {
+	var out []models.Account
+	for _, acct := range accounts {
+		if acct.Health.Value.Cmp(maxHealth) <= 0 {
+			out = append(out, acct)
+		}
+	}
+	return out
+}
+
+// LiquidatableAccounts returns the accounts read from src with a health
+// below 1.0, keyed by address. Accounts found before an error are returned
+// along with it
+func LiquidatableAccounts(ctx context.Context, src AccountSource) (map[string]models.Decimal, error) {
+	accounts, err := src.AtRisk(ctx, liquidationHealth)
+	out := make(map[string]models.Decimal)
+	for _, acct := range accounts {
+		if acct.Health.Value.LessThan(liquidationHealth) {
+			out[acct.Address] = acct.Health.Value
+		}
+	}
+	return out, err
+}
+
+// SupplyInterestEarned returns the interest earned by an account supplying
+// token, read from src
+func SupplyInterestEarned(ctx context.Context, src AccountSource, address string, token Address) (models.Decimal, error) {
+	tkn, err := accountToken(ctx, src, address, token)
+	if err != nil {
+		return models.Decimal{}, err
+	}
+	return tkn.LifetimeSupplyInterestAccrued.Value, nil
+}
+
+// TotalSupplyInterestEarned returns the interest earned by an account across
+// every market, read from src
+func TotalSupplyInterestEarned(ctx context.Context, src AccountSource, address string) (models.Decimal, error) {
+	acct, err := src.Account(ctx, address)
+	if err != nil {
+		return models.Decimal{}, err
+	}
+	return totalSupplyInterest(acct), nil
+}
+
+// BorrowInterestAccrued returns the interest owed by an account borrowing
+// token, read from src
+func BorrowInterestAccrued(ctx context.Context, src AccountSource, address string, token Address) (models.Decimal, error) {
+	tkn, err := accountToken(ctx, src, address, token)
+	if err != nil {
+		return models.Decimal{}, err
+	}
+	return tkn.LifetimeBorrowInterestAccrued.Value, nil
+}
+
+func accountToken(ctx context.Context, src AccountSource, address string, token Address) (models.AccountToken, error) {
+	acct, err := src.Account(ctx, address)
+	if err != nil {
+		return models.AccountToken{}, err
+	}
+	return models.GetTokenByAddress(token.String(), &models.AccountResponse{Accounts: []models.Account{*acct}})
+}
+
+func totalSupplyInterest(acct *models.Account) models.Decimal {
+	var value models.Decimal
+	for _, tk := range acct.Tokens {
+		value = value.Add(tk.LifetimeSupplyInterestAccrued.Value)
+	}
+	return value
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/compound"
+	"github.com/musinit/go-defi/v2/mockapi"
+	"github.com/musinit/go-defi/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainBackend is a deployment with an ether market priced at 2000 and a dai
+// market priced at 1, where every account supplies 1 ether and borrows the
+// amount of dai set in borrows
+type chainBackend struct {
+	borrows map[common.Address]int64
+}
+
+func scaled(x int64, decimals int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(x), new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil))
+}
+
+func (b *chainBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return 1, nil
+}
+
+func (b *chainBackend) Comptroller(ctx context.Context, block uint64) (*compound.ComptrollerState, error) {
+	return &compound.ComptrollerState{
+		CloseFactor:          scaled(5, 17),
+		LiquidationIncentive: scaled(108, 16),
+		Markets:              []common.Address{CompoundETH.EthAddress(), CompoundDAI.EthAddress()},
+	}, nil
+}
+
+func (b *chainBackend) Market(ctx context.Context, block uint64, address common.Address) (*compound.Market, error) {
+	m := &compound.Market{
+		Address:            address,
+		Decimals:           8,
+		UnderlyingDecimals: 18,
+		TotalSupply:        new(big.Int),
+		TotalBorrows:       new(big.Int),
+		Reserves:           new(big.Int),
+		Cash:               new(big.Int),
+		ExchangeRate:       scaled(2, 26),
+		SupplyRatePerBlock: new(big.Int),
+		BorrowRatePerBlock: new(big.Int),
+		CollateralFactor:   scaled(75, 16),
+		UnderlyingPrice:    scaled(1, 18),
+	}
+	if address == CompoundETH.EthAddress() {
+		m.Symbol = "cETH"
+		m.UnderlyingPrice = scaled(2000, 18)
+	} else {
+		m.Symbol = "cDAI"
+		m.Underlying = common.HexToAddress("0x6b175474e89094c44da98b954eedeac495271d0f")
+	}
+	return m, nil
+}
+
+func (b *chainBackend) AssetsIn(ctx context.Context, block uint64, account common.Address) ([]common.Address, error) {
+	return []common.Address{CompoundETH.EthAddress(), CompoundDAI.EthAddress()}, nil
+}
+
+func (b *chainBackend) AccountSnapshot(ctx context.Context, block uint64, account, ctoken common.Address) (*compound.AccountSnapshot, error) {
+	snapshot := &compound.AccountSnapshot{CTokenBalance: new(big.Int), BorrowBalance: new(big.Int), ExchangeRate: scaled(2, 26)}
+	if ctoken == CompoundETH.EthAddress() {
+		snapshot.CTokenBalance = scaled(50, 8)
+	} else {
+		snapshot.BorrowBalance = scaled(b.borrows[account], 18)
+	}
+	return snapshot, nil
+}
+
+func Test_APISource(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+	src := NewClient(srv.URL).Source()
+	ctx := context.Background()
+
+	acct, err := src.Account(ctx, account)
+	require.NoError(t, err)
+	assert.Equal(t, "1.52", acct.Health.Value.String())
+	_, err = src.Account(ctx, "0x0000000000000000000000000000000000000001")
+	assert.True(t, errors.Is(err, ErrAccountNotFound))
+
+	risky, err := src.AtRisk(ctx, models.MustParseDecimal("1.2"))
+	require.NoError(t, err)
+	require.NotEmpty(t, risky)
+	for _, acct := range risky {
+		assert.True(t, acct.Health.Value.Cmp(models.MustParseDecimal("1.2")) <= 0)
+	}
+
+	liquidatable, err := LiquidatableAccounts(ctx, src)
+	require.NoError(t, err)
+	assert.NotEmpty(t, liquidatable)
+	assert.Less(t, len(liquidatable), len(risky))
+
+	interest, err := SupplyInterestEarned(ctx, src, account, CompoundETH)
+	require.NoError(t, err)
+	assert.Equal(t, "0.02736", interest.String())
+	interest, err = TotalSupplyInterestEarned(ctx, src, account)
+	require.NoError(t, err)
+	assert.Equal(t, "0.02736", interest.String())
+	interest, err = BorrowInterestAccrued(ctx, src, account, CompoundDAI)
+	require.NoError(t, err)
+	assert.Equal(t, "83.7", interest.String())
+}
+
+func Test_ChainSource(t *testing.T) {
+	safe := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	risky := common.HexToAddress(account)
+	backend := &chainBackend{borrows: map[common.Address]int64{safe: 500, risky: 1600}}
+	src := NewChainSource(backend, safe.Hex(), risky.Hex())
+	ctx := context.Background()
+
+	acct, err := src.Account(ctx, safe.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "0.75", acct.TotalCollateralValueInEth.Value.String())
+	assert.Equal(t, "0.25", acct.TotalBorrowValueInEth.Value.String())
+	assert.Equal(t, "3", acct.Health.Value.String())
+	_, err = src.Account(ctx, "not an address")
+	assert.Error(t, err)
+
+	liquidatable, err := LiquidatableAccounts(ctx, src)
+	require.NoError(t, err)
+	require.Len(t, liquidatable, 1)
+	assert.Equal(t, "0.9375", liquidatable[account].String())
+
+	defer func(interval time.Duration) { watchInterval = interval }(watchInterval)
+	watchInterval = time.Millisecond * 10
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+	riskChan := make(chan models.Decimal, 1)
+	errChan := make(chan error, 1)
+	go func() { errChan <- WatchAccountHealth(ctx, src, account, riskChan, make(chan models.Decimal, 1)) }()
+	select {
+	case health := <-riskChan:
+		assert.Equal(t, "0.9375", health.String())
+	case <-ctx.Done():
+		t.Fatal("no risk signal")
+	}
+	cancel()
+	require.NoError(t, <-errChan)
+}
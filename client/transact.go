@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MaxRepay is the repay amount that repays a whole borrow, interest included
+var MaxRepay = new(big.Int).Set(math.MaxBig256)
+
+// ErrTxFailed is returned for transactions that reverted, or whose compound
+// contract reported a failure
+var ErrTxFailed = errors.New("tx receipt status is not 1, indicating a failure occurred")
+
+// FailureError is returned for transactions that were mined but emitted a
+// compound Failure event, as most failures of the comptroller and of older
+// ctokens return an error code instead of reverting
+type FailureError struct {
+	TxHash   common.Hash
+	Contract common.Address
+	// Code, Info and Detail are the error, failure info and detail of the
+	// event, see ErrorReporter.sol
+	Code, Info, Detail uint64
+}
+
+func (e *FailureError) Error() string {
+	return fmt.Sprintf("tx %s: %s reported failure with error %d, info %d, detail %d",
+		e.TxHash.Hex(), e.Contract.Hex(), e.Code, e.Info, e.Detail)
+}
+
+// Unwrap returns ErrTxFailed
+func (e *FailureError) Unwrap() error {
+	return ErrTxFailed
+}
+
+// failureTopic is the topic of the Failure(uint256,uint256,uint256) event
+// of the comptroller and the ctokens
+var failureTopic = crypto.Keccak256Hash([]byte("Failure(uint256,uint256,uint256)"))
+
+// marketEnteredTopic is the topic of the MarketEntered(address,address)
+// event of the comptroller
+var marketEnteredTopic = crypto.Keccak256Hash([]byte("MarketEntered(address,address)"))
+
+// transact sends the transaction built by send with the auth of the client,
+// waits for it to be mined and checks its receipt
+func (bc *BClient) transact(ctx context.Context, send func(auth *bind.TransactOpts) (*types.Transaction, error)) error {
+	_, err := bc.transactReceipt(ctx, send)
+	return err
+}
+
+// transactReceipt is like transact, and returns the checked receipt
+func (bc *BClient) transactReceipt(ctx context.Context, send func(auth *bind.TransactOpts) (*types.Transaction, error)) (*types.Receipt, error) {
+	tx, err := send(bc.auth)
+	if err != nil {
+		return nil, err
+	}
+	rcpt, err := bind.WaitMined(ctx, bc.client, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReceipt(rcpt); err != nil {
+		return nil, err
+	}
+	return rcpt, nil
+}
+
+// checkReceipt returns an error for reverted transactions, and for those
+// with a Failure event
+func checkReceipt(rcpt *types.Receipt) error {
+	if rcpt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("tx %s: %w", rcpt.TxHash.Hex(), ErrTxFailed)
+	}
+	for _, log := range rcpt.Logs {
+		if len(log.Topics) == 0 || log.Topics[0] != failureTopic || len(log.Data) != 96 {
+			continue
+		}
+		return &FailureError{
+			TxHash:   rcpt.TxHash,
+			Contract: log.Address,
+			Code:     new(big.Int).SetBytes(log.Data[:32]).Uint64(),
+			Info:     new(big.Int).SetBytes(log.Data[32:64]).Uint64(),
+			Detail:   new(big.Int).SetBytes(log.Data[64:]).Uint64(),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	cerc20 "github.com/musinit/go-defi/v2/bindings/cbat"
+	ceth "github.com/musinit/go-defi/v2/bindings/ceth"
+	comptroller "github.com/musinit/go-defi/v2/bindings/comptroller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckReceipt(t *testing.T) {
+	hash := common.HexToHash("0x01")
+	assert.NoError(t, checkReceipt(&types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: hash}))
+
+	err := checkReceipt(&types.Receipt{Status: types.ReceiptStatusFailed, TxHash: hash})
+	assert.True(t, errors.Is(err, ErrTxFailed))
+
+	data := make([]byte, 96)
+	big.NewInt(14).FillBytes(data[:32])
+	big.NewInt(3).FillBytes(data[32:64])
+	err = checkReceipt(&types.Receipt{
+		Status: types.ReceiptStatusSuccessful,
+		TxHash: hash,
+		Logs: []*types.Log{
+			{Address: common.HexToAddress("0xc1"), Topics: []common.Hash{common.HexToHash("0x02")}},
+			{Address: common.HexToAddress("0xc0"), Topics: []common.Hash{failureTopic}, Data: data},
+		},
+	})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTxFailed))
+	var failure *FailureError
+	require.True(t, errors.As(err, &failure))
+	assert.Equal(t, common.HexToAddress("0xc0"), failure.Contract)
+	assert.Equal(t, uint64(14), failure.Code)
+	assert.Equal(t, uint64(3), failure.Info)
+	assert.Zero(t, failure.Detail)
+}
+
+func Test_BClient_Lifecycle(t *testing.T) {
+	bc := NewBClient(nil, nil)
+	ctx := context.Background()
+	unknown := Address("0x0000000000000000000000000000000000000001")
+	for _, err := range []error{
+		bc.Redeem(ctx, unknown, big.NewInt(1)),
+		bc.RepayBorrowBehalf(ctx, unknown, unknown, big.NewInt(1)),
+		bc.EnterMarkets(ctx, CompoundDAI, unknown),
+		bc.ExitMarket(ctx, unknown),
+	} {
+		assert.True(t, errors.Is(err, ErrMarketNotFound))
+	}
+	assert.Equal(t, ErrEtherMaxRepay, bc.RepayBorrowBehalf(ctx, CompoundETH, unknown, MaxRepay))
+	assert.Equal(t, 256, MaxRepay.BitLen())
+}
+
+func Test_BClient_EtherMarket(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(ceth.BindingsABI))
+	require.NoError(t, err)
+	node := newContractNode(t, nil)
+	bc := NewBClient(testAuth(), node.dial(t))
+	ctx := context.Background()
+
+	decimals, err := bc.UnderlyingDecimals(ctx, CompoundETH)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(18), decimals)
+
+	amount := big.NewInt(1e18)
+	require.NoError(t, bc.Mint(ctx, CompoundETH, amount))
+	require.NoError(t, bc.RepayBorrow(ctx, CompoundETH, amount))
+	require.NoError(t, bc.RedeemUnderlying(ctx, CompoundETH, amount))
+	sent := node.transactions()
+	require.Len(t, sent, 3)
+	for i, tt := range []struct {
+		method string
+		args   []interface{}
+		value  *big.Int
+	}{
+		// the payable methods of cETH take the amount as the value
+		{"mint", nil, amount},
+		{"repayBorrow", nil, amount},
+		{"redeemUnderlying", []interface{}{amount}, new(big.Int)},
+	} {
+		data, err := parsed.Pack(tt.method, tt.args...)
+		require.NoError(t, err)
+		assert.Equal(t, data, sent[i].Data(), tt.method)
+		assert.Equal(t, tt.value, sent[i].Value(), tt.method)
+		assert.Equal(t, CompoundETH.EthAddress(), *sent[i].To(), tt.method)
+	}
+}
+
+func Test_BClient_EnterMarkets(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(comptroller.BindingsABI))
+	require.NoError(t, err)
+	var (
+		comptrollerAddress = common.HexToAddress("0xc0")
+		auth               = testAuth()
+		members            = map[common.Address]bool{}
+		unlisted           = map[common.Address]bool{}
+	)
+	node := newContractNode(t, nil, comptroller.BindingsABI)
+	// the comptroller enters every listed market the account is not a member of
+	node.receipts = func(tx *types.Transaction) []*types.Log {
+		in, err := parsed.Methods["enterMarkets"].Inputs.Unpack(tx.Data()[4:])
+		require.NoError(t, err)
+		var logs []*types.Log
+		for _, market := range in[0].([]common.Address) {
+			if members[market] || unlisted[market] {
+				continue
+			}
+			data, err := parsed.Events["MarketEntered"].Inputs.Pack(market, auth.From)
+			require.NoError(t, err)
+			logs = append(logs, &types.Log{Address: comptrollerAddress, Topics: []common.Hash{marketEnteredTopic}, Data: data})
+		}
+		return logs
+	}
+	node.handlers["checkMembership"] = func(to common.Address, in []interface{}) []interface{} {
+		return []interface{}{members[in[1].(common.Address)]}
+	}
+	bc := NewBClient(auth, node.dial(t), WithComptroller(Address(comptrollerAddress.Hex())))
+	ctx := context.Background()
+
+	require.NoError(t, bc.EnterMarkets(ctx, CompoundDAI, CompoundETH))
+	sent := node.transactions()
+	require.Len(t, sent, 1)
+	data, err := parsed.Pack("enterMarkets", []common.Address{CompoundDAI.EthAddress(), CompoundETH.EthAddress()})
+	require.NoError(t, err)
+	assert.Equal(t, data, sent[0].Data())
+	assert.Equal(t, comptrollerAddress, *sent[0].To())
+
+	// markets already entered emit no event
+	members[CompoundDAI.EthAddress()] = true
+	require.NoError(t, bc.EnterMarkets(ctx, CompoundDAI, CompoundETH))
+
+	unlisted[CompoundETH.EthAddress()] = true
+	err = bc.EnterMarkets(ctx, CompoundDAI, CompoundETH)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTxFailed))
+	assert.Contains(t, err.Error(), CompoundETH.EthAddress().Hex())
+}
+
+func Test_BClient_Reads(t *testing.T) {
+	var (
+		comptrollerAddress = common.HexToAddress("0xc0")
+		cUSDC              = CompoundUSDC.EthAddress()
+		account            = common.HexToAddress("0xa1")
+	)
+	node := newContractNode(t, map[common.Address]map[string][]interface{}{
+		comptrollerAddress: {
+			"getAccountLiquidity": {new(big.Int), new(big.Int), big.NewInt(5)},
+		},
+		cUSDC: {
+			"balanceOfUnderlying": {big.NewInt(1500)},
+			"exchangeRateCurrent": {big.NewInt(2e14)},
+		},
+	}, cerc20.BindingsABI, comptroller.BindingsABI)
+	bc := NewBClient(testAuth(), node.dial(t), WithComptroller(Address(comptrollerAddress.Hex())))
+	ctx := context.Background()
+
+	// the configured comptroller is read
+	ok, err := bc.CanLiquidate(ctx, account)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// values accrued up to the latest block are read without a transaction
+	balance, err := bc.BalanceOfUnderlying(ctx, CompoundUSDC, Address(account.Hex()))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1500), balance)
+	rate, err := bc.ExchangeRateCurrent(ctx, CompoundUSDC)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2e14), rate)
+	assert.Empty(t, node.transactions())
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = bc.CanLiquidate(cancelled, account)
+	assert.Error(t, err)
+}
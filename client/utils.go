@@ -1,11 +1,8 @@
 package client
 
 import (
+	"context"
 	"errors"
-	"io/ioutil"
-	"net/http"
-
-	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/musinit/go-defi/v2/models"
@@ -49,6 +46,9 @@ const (
 
 	// GOERLI
 	CompoundUSDC_goerli = Address("0x73506770799Eb04befb5AaE4734e58C2C624F493")
+	CompoundDAI_goerli  = Address("0x0545a8eaF7ff6bB6F708CbB544EA55DBc2ad7b2a")
+	CompoundETH_goerli  = Address("0x64078a6189Bf45f80091c6Ff2fCEe1B15Ac8dbde")
+	Comptroller_goerli  = Address("0x05Df6C772A563FfB37fD3E04C1A279Fb30228621")
 
 	// Common
 	USDC        = Address("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
@@ -95,7 +95,8 @@ const (
 )
 
 var (
-	// CompoundTokens is map containing the name, and address of all compound tokens
+	// CompoundTokens is map containing the name, and address of all compound tokens.
+	// Markets listed after it was written are found through a MarketRegistry
 	CompoundTokens = map[string]Address{
 		"cBAT":         CompoundBAT,
 		"cDAI":         CompoundDAI,
@@ -105,75 +106,65 @@ var (
 		"cUSDC":        CompoundUSDC,
 		"cWBTC":        CompoundWBTC,
 		"cZRX":         CompoundZRX,
-		"сUSDT":        CompoundUSDT,
-		"сUSDT_goerli": CompoundUSDC_goerli,
+		"cUSDT":        CompoundUSDT,
+		"cDAI_goerli":  CompoundDAI_goerli,
+		"cETH_goerli":  CompoundETH_goerli,
+		"cUSDC_goerli": CompoundUSDC_goerli,
 		"aUSDCv3":      AaveUSDCv3,
 		"aUSDTv3":      AaveUSDTv3,
 		"aUSDTv2":      AaveUSDTv2,
 	}
 )
 
-// GetTotalSupplyInterestedEarned is used to return the total supply interest earned for a particular account
-func (c *Client) GetTotalSupplyInterestedEarned(resp *models.AccountResponse) (float64, error) {
+// GetTotalSupplyInterestedEarned is used to return the total supply interest earned for a particular account.
+// The interest helpers only read resp, which can be read as of a past block with AtBlock or AtTime
+func (c *Client) GetTotalSupplyInterestedEarned(resp *models.AccountResponse) (models.Decimal, error) {
 	if len(resp.Accounts) == 0 {
-		return 0, errors.New("no accounts found")
-	}
-	var (
-		account = resp.Accounts[0]
-		value   float64
-	)
-	for _, tk := range account.Tokens {
-		// skip tokens with no balance
-		if tk.LifetimeSupplyInterestAccrued.Value == "0" ||
-			tk.LifetimeSupplyInterestAccrued.Value == "0.0" {
-			continue
-		}
-		val, err := strconv.ParseFloat(tk.LifetimeSupplyInterestAccrued.Value, 64)
-		if err != nil {
-			return 0, err
-		}
-		value = value + val
+		return models.Decimal{}, errors.New("no accounts found")
 	}
-	return value, nil
+	return totalSupplyInterest(&resp.Accounts[0]), nil
 }
 
 // GetSupplyInterestEarned is used to retrieve the interest earned by supply a particular token
-func (c *Client) GetSupplyInterestEarned(token Address, resp *models.AccountResponse) (float64, error) {
+func (c *Client) GetSupplyInterestEarned(token Address, resp *models.AccountResponse) (models.Decimal, error) {
 	tkn, err := models.GetTokenByAddress(token.String(), resp)
 	if err != nil {
-		return 0, err
+		return models.Decimal{}, err
 	}
-	return strconv.ParseFloat(tkn.LifetimeSupplyInterestAccrued.Value, 64)
+	return tkn.LifetimeSupplyInterestAccrued.Value, nil
 }
 
 // GetBorrowInterestedAccrued is used to retrieve the interest you owe for borrowing
-func (c *Client) GetBorrowInterestedAccrued(token Address, resp *models.AccountResponse) (string, error) {
+func (c *Client) GetBorrowInterestedAccrued(token Address, resp *models.AccountResponse) (models.Decimal, error) {
 	tkn, err := models.GetTokenByAddress(token.String(), resp)
 	if err != nil {
-		return "", err
+		return models.Decimal{}, err
 	}
 	return tkn.LifetimeBorrowInterestAccrued.Value, nil
 }
 
 // GetLiquidatableAccounts is used to return all accounts with health below 1.0
 // indicating they can be liquidated. The keys of the map are the addresses
-// and the values are their health
-func (c *Client) GetLiquidatableAccounts(pageSize, pageNum string) (map[string]float64, error) {
-	resp, err := c.GetAccounts(pageSize, pageNum)
+// and the values are their health. Accounts are read as of the block or time
+// of opts, see AtBlock and AtTime
+func (c *Client) GetLiquidatableAccounts(pageSize, pageNum string, opts ...ReadOption) (map[string]models.Decimal, error) {
+	return c.GetLiquidatableAccountsContext(context.Background(), pageSize, pageNum, opts...)
+}
+
+// GetLiquidatableAccountsContext is like GetLiquidatableAccounts but uses
+// the given context for the underlying request
+func (c *Client) GetLiquidatableAccountsContext(ctx context.Context, pageSize, pageNum string, opts ...ReadOption) (map[string]models.Decimal, error) {
+	resp, err := c.GetAccountsContext(ctx, pageSize, pageNum, opts...)
 	if err != nil {
 		return nil, err
 	}
 	if len(resp.Accounts) == 0 {
 		return nil, errors.New("an unexpected error occurred")
 	}
-	out := make(map[string]float64)
+	out := make(map[string]models.Decimal)
 	for _, acct := range resp.Accounts {
-		health, err := strconv.ParseFloat(acct.Health.Value, 64)
-		if err != nil {
-			return nil, err
-		}
-		if health < 1.0 {
-			out[acct.Address] = health
+		if acct.Health.Value.LessThan(liquidationHealth) {
+			out[acct.Address] = acct.Health.Value
 		}
 	}
 	if len(out) == 0 {
@@ -182,21 +173,20 @@ func (c *Client) GetLiquidatableAccounts(pageSize, pageNum string) (map[string]f
 	return out, nil
 }
 
-// sendRequest is used to send a request, and return the given body bytes
-func (c *Client) sendRequest(url string) ([]byte, error) {
-	request, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	request.Header.Set("content-type", "application/json")
-	resp, err := c.client.Do(request)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+// scanPageSize is the page size used when scanning every account
+const scanPageSize = 100
+
+// liquidationHealth is the health below which an account can be liquidated
+var liquidationHealth = models.NewDecimal(1)
+
+// ScanLiquidatableAccounts is like GetLiquidatableAccounts, but walks every page
+// of accounts with a health below 1.0 instead of a single page, fetching up to
+// concurrency pages in parallel. If some pages fail, the accounts found on the
+// other pages are returned along with a PageErrors error
+func (c *Client) ScanLiquidatableAccounts(ctx context.Context, concurrency int, opts ...ReadOption) (map[string]models.Decimal, error) {
+	accounts, err := LiquidatableAccounts(ctx, &APISource{client: c, concurrency: concurrency, read: applyReadOptions(opts)})
+	if len(accounts) == 0 && err != nil {
 		return nil, err
 	}
-	return bodyBytes, nil
+	return accounts, err
 }
@@ -4,18 +4,37 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"time"
+
+	"github.com/musinit/go-defi/v2/models"
 )
 
 // provides account monitoring utilities
 
+var (
+	// watchInterval is how often WatchHealth polls the account
+	watchInterval = time.Second * 15
+	// riskHealth and warnHealth are the thresholds signalled by WatchHealth
+	riskHealth = models.NewDecimal(1)
+	warnHealth = models.MustParseDecimal("1.2")
+)
+
 // WatchHealth is a helper function used to watch account health and send signals on different states.
 // the riskChan is a channel used to signal when an account health is at 1.0 or lower, which means it is at risk of liquidation
 // warnChan is a channel used to signal when an account is nearing liquidation risk, and has a health of 1.2 or lower
-func (c *Client) WatchHealth(ctx context.Context, address string, riskChan, warnChan chan float64) error {
+//
+// Deprecated: use a PortfolioWatcher, which watches many accounts with their own thresholds and never blocks
+func (c *Client) WatchHealth(ctx context.Context, address string, riskChan, warnChan chan models.Decimal) error {
+	return WatchAccountHealth(ctx, c.Source(), address, riskChan, warnChan)
+}
+
+// WatchAccountHealth is like Client.WatchHealth, but reads the account from src,
+// ex. a ChainSource to watch an account without the api
+//
+// Deprecated: use a PortfolioWatcher
+func WatchAccountHealth(ctx context.Context, src AccountSource, address string, riskChan, warnChan chan models.Decimal) error {
 	var (
-		ticker   = time.NewTicker(time.Second * 15)
+		ticker   = time.NewTicker(watchInterval)
 		errCount int
 	)
 	defer ticker.Stop()
@@ -28,26 +47,30 @@ func (c *Client) WatchHealth(ctx context.Context, address string, riskChan, warn
 			if errCount > 3 {
 				return errors.New("too many errors")
 			}
-			resp, err := c.GetAccount(address)
+			acct, err := src.Account(ctx, address)
 			if err != nil {
 				fmt.Println("got error", err.Error())
 				errCount++
 				continue
 			}
-			if len(resp.Accounts) == 0 {
-				fmt.Println("unexpected error")
-				errCount++
+			if acct.TotalBorrowValueInEth.Value.Sign() == 0 {
+				// an account without borrows can not be liquidated
 				continue
 			}
-			health, err := strconv.ParseFloat(resp.Accounts[0].Health.Value, 64)
-			if err != nil {
-				return err
-			}
-			if health <= 1.0 {
-				riskChan <- health
+			health := acct.Health.Value
+			if health.Cmp(riskHealth) <= 0 {
+				select {
+				case riskChan <- health:
+				case <-ctx.Done():
+					return nil
+				}
 				fmt.Println("account at risk of liquidation")
-			} else if health <= 1.2 {
-				warnChan <- health
+			} else if health.Cmp(warnHealth) <= 0 {
+				select {
+				case warnChan <- health:
+				case <-ctx.Done():
+					return nil
+				}
 				fmt.Println("account nearing liquidation risk")
 			}
 		}
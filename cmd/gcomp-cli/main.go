@@ -8,16 +8,22 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
 
-	"strconv"
-
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/musinit/go-defi/v2/alert"
 	"github.com/musinit/go-defi/v2/client"
+	"github.com/musinit/go-defi/v2/compound"
 	"github.com/musinit/go-defi/v2/config"
+	"github.com/musinit/go-defi/v2/models"
 	"github.com/urfave/cli"
 )
 
 var (
-	url string
+	url    string
+	apiKey string
 )
 
 func main() {
@@ -47,14 +53,33 @@ func loadFlags() []cli.Flag {
 		cli.StringFlag{
 			Name:        "api.url, au",
 			Usage:       "the compound api url",
-			Value:       "https://api.compound.finance/api/v2",
+			Value:       client.DefaultURL,
 			Destination: &url,
 		},
+		cli.StringFlag{
+			Name:        "api.key, ak",
+			Usage:       "the api key sent with every api request",
+			EnvVar:      "COMPOUND_API_KEY",
+			Destination: &apiKey,
+		},
 		cli.StringFlag{
 			Name:  "eth.rpc, er",
 			Usage: "endpoint for JSON-RPC access",
 			Value: "http://localhost:8500",
 		},
+		cli.StringFlag{
+			Name:  "source, s",
+			Usage: "where account data is read from, api or chain",
+			Value: "api",
+		},
+		cli.StringSliceFlag{
+			Name:  "accounts",
+			Usage: "accounts checked by liquidatable when reading from the chain",
+		},
+		cli.StringFlag{
+			Name:  "alerts",
+			Usage: "path to a config file whose alerts section routes the alerts of watch and monitor",
+		},
 		cli.StringFlag{
 			Name:  "key.file, kf",
 			Usage: "path to ethereum key file",
@@ -66,8 +91,263 @@ func loadFlags() []cli.Flag {
 	}
 }
 
+// newClient returns an api client configured from the global flags
+func newClient() *client.Client {
+	var opts []client.Option
+	if apiKey != "" {
+		opts = append(opts, client.WithAPIKey(apiKey))
+	}
+	return client.NewClient(url, opts...)
+}
+
+// newSource returns the account source selected by the global flags, the api
+// source reads as of the block.number flag of the command when it is set
+func newSource(c *cli.Context) (client.AccountSource, error) {
+	block := c.Uint("block.number")
+	switch c.GlobalString("source") {
+	case "api":
+		if block != 0 {
+			return newClient().Source(client.AtBlock(uint32(block))), nil
+		}
+		return newClient().Source(), nil
+	case "chain":
+		if block != 0 {
+			return nil, errors.New("block.number is only supported by the api source")
+		}
+		eth, err := ethclient.Dial(c.GlobalString("eth.rpc"))
+		if err != nil {
+			return nil, err
+		}
+		backend, err := compound.NewChainBackend(eth, client.Unitroller.EthAddress(), client.CompoundETH.EthAddress())
+		if err != nil {
+			return nil, err
+		}
+		return client.NewChainSource(backend, c.GlobalStringSlice("accounts")...), nil
+	default:
+		return nil, fmt.Errorf("unknown source %q, must be api or chain", c.GlobalString("source"))
+	}
+}
+
+// newRouter returns the alert router configured by the alerts flag, or nil
+// when it is not set
+func newRouter(c *cli.Context) (*alert.Router, error) {
+	if c.GlobalString("alerts") == "" {
+		return nil, nil
+	}
+	cfg, err := config.Load(c.GlobalString("alerts"))
+	if err != nil {
+		return nil, err
+	}
+	return alert.NewRouterFromConfig(cfg.Alerts)
+}
+
+// sendAlert logs an alert, and sends it through router when there is one
+func sendAlert(ctx context.Context, router *alert.Router, a alert.Alert) {
+	log.Printf("[%s] %s: %s\n", a.Severity, a.Title, a.Message)
+	if router == nil {
+		return
+	}
+	if err := router.Send(ctx, a); err != nil {
+		log.Println("error sending alert: ", err.Error())
+	}
+}
+
+// newMarkets returns the known markets of the deployment flag, or those
+// discovered from its comptroller when the discover flag is set
+func newMarkets(c *cli.Context) (*client.MarketRegistry, error) {
+	deployment, ok := client.Deployments[c.String("deployment")]
+	if !ok {
+		return nil, fmt.Errorf("unknown deployment %q, must be mainnet or goerli", c.String("deployment"))
+	}
+	if !c.Bool("discover") {
+		return client.NewDefaultMarketRegistry().OnChain(deployment.ChainID), nil
+	}
+	eth, err := ethclient.Dial(c.GlobalString("eth.rpc"))
+	if err != nil {
+		return nil, err
+	}
+	defer eth.Close()
+	markets, err := client.DiscoverMarkets(context.Background(), eth, deployment, client.WithLogRange(c.Uint64("log.range")))
+	if err != nil {
+		return nil, err
+	}
+	return client.NewMarketRegistry(markets...), nil
+}
+
+// lookupToken returns the address of the token named by a CompoundTokens key,
+// or by the symbol, address or underlying token of a known mainnet market
+func lookupToken(name string) (client.Address, error) {
+	if address, ok := client.CompoundTokens[name]; ok {
+		return address, nil
+	}
+	market, err := client.NewDefaultMarketRegistry().OnChain(client.CompoundMainnet.ChainID).Lookup(name)
+	if err != nil {
+		return "", err
+	}
+	return client.Address(strings.ToLower(market.Address.String())), nil
+}
+
+// printMarket prints a market on a single line, with its listing when it
+// was discovered
+func printMarket(m client.Market) {
+	underlying := m.Underlying.String()
+	switch {
+	case m.Ether:
+		underlying = "ether"
+	case underlying == "":
+		underlying = "-"
+	}
+	line := fmt.Sprintf("%-8s chain %-5d %s underlying %s", m.Symbol, m.ChainID, m.Address, underlying)
+	if m.UnderlyingDecimals != 0 {
+		line += fmt.Sprintf(" decimals %d listed %t collateral factor %s", m.UnderlyingDecimals, m.Listed, m.CollateralFactor)
+	}
+	fmt.Println(line)
+}
+
 func loadCommands() cli.Commands {
-	return append(loadAccountCommands(), loadPriceCommands()...)
+	commands := append(loadAccountCommands(), loadPriceCommands()...)
+	commands = append(commands, loadMarketCommands()...)
+	return append(commands, loadAaveCommands()...)
+}
+
+func loadMarketCommands() cli.Commands {
+	flags := []cli.Flag{
+		cli.BoolFlag{
+			Name:  "discover",
+			Usage: "discover the markets from the comptroller instead of using the known ones",
+		},
+		cli.StringFlag{
+			Name:  "deployment",
+			Usage: "the compound deployment markets are discovered from, mainnet or goerli",
+			Value: client.CompoundMainnet.Name,
+		},
+		cli.Uint64Flag{
+			Name:  "log.range",
+			Usage: "the most blocks queried for listings at once, 0 for no limit",
+		},
+	}
+	return cli.Commands{
+		cli.Command{
+			Name:  "markets",
+			Usage: "compound market related functionality",
+			Subcommands: cli.Commands{
+				cli.Command{
+					Name:  "list",
+					Usage: "list the markets",
+					Action: func(c *cli.Context) error {
+						markets, err := newMarkets(c)
+						if err != nil {
+							return err
+						}
+						for _, m := range markets.Markets() {
+							printMarket(m)
+						}
+						return nil
+					},
+					Flags: flags,
+				},
+				cli.Command{
+					Name:      "get",
+					Usage:     "get a market by symbol, ctoken address or underlying token address",
+					ArgsUsage: "<symbol|address>",
+					Action: func(c *cli.Context) error {
+						if c.NArg() != 1 {
+							return errors.New("a single symbol or address is required")
+						}
+						markets, err := newMarkets(c)
+						if err != nil {
+							return err
+						}
+						m, err := markets.Lookup(c.Args().First())
+						if err != nil {
+							return err
+						}
+						printMarket(m)
+						return nil
+					},
+					Flags: flags,
+				},
+			},
+		},
+	}
+}
+
+func loadAaveCommands() cli.Commands {
+	return cli.Commands{
+		cli.Command{
+			Name:  "aave",
+			Usage: "aave related functionality",
+			Subcommands: cli.Commands{
+				cli.Command{
+					Name:  "watch",
+					Usage: "watch the health factor and available borrows of aave accounts",
+					Action: func(c *cli.Context) error {
+						addresses := c.StringSlice("eth.address")
+						if len(addresses) == 0 {
+							return errors.New("eth.address flag is empty")
+						}
+						market, ok := client.AaveMarkets[c.String("market")]
+						if !ok {
+							return fmt.Errorf("unknown aave market %q", c.String("market"))
+						}
+						minBorrows, err := models.ParseDecimal(c.String("min.borrows"))
+						if err != nil {
+							return err
+						}
+						router, err := newRouter(c)
+						if err != nil {
+							return err
+						}
+						eth, err := ethclient.Dial(c.GlobalString("eth.rpc"))
+						if err != nil {
+							return err
+						}
+						pool, err := client.NewAavePool(eth, market)
+						if err != nil {
+							return err
+						}
+						thresholds := client.DefaultAaveThresholds
+						thresholds.MinAvailableBorrows = minBorrows
+						watcher := client.NewAaveWatcher(pool,
+							client.WithAaveInterval(c.Duration("interval")),
+							client.WithAaveThresholds(thresholds),
+						)
+						for _, address := range addresses {
+							watcher.Watch(common.HexToAddress(address), nil)
+						}
+						ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+						defer cancel()
+						go watcher.Run(ctx)
+						for event := range watcher.Events() {
+							sendAlert(ctx, router, alert.FromAaveEvent(event))
+						}
+						return nil
+					},
+					Flags: []cli.Flag{
+						cli.StringSliceFlag{
+							Name:  "eth.address, ea",
+							Usage: "the addresses to watch",
+						},
+						cli.StringFlag{
+							Name:  "market",
+							Usage: "the aave market, one of polygon, polygon-v2 or mumbai",
+							Value: client.AavePolygonV3.Name,
+						},
+						cli.StringFlag{
+							Name:  "min.borrows",
+							Usage: "flag accounts that can borrow at most this much more, in the base currency of the market",
+							Value: "0",
+						},
+						cli.DurationFlag{
+							Name:  "interval",
+							Usage: "how often each account is read",
+							Value: client.DefaultWatchInterval,
+						},
+					},
+				},
+			},
+		},
+	}
 }
 
 func loadPriceCommands() cli.Commands {
@@ -91,8 +371,12 @@ func loadPriceCommands() cli.Commands {
 						if err != nil {
 							return err
 						}
+						token, err := lookupToken(c.Parent().String("eth.address"))
+						if err != nil {
+							return err
+						}
 						bclient := client.NewBClient(auth, ethclient)
-						price, err := bclient.GetPrice(ctx, client.CompoundTokens[c.GlobalString("eth.address")])
+						price, err := bclient.GetPrice(ctx, token)
 						if err != nil {
 							return err
 						}
@@ -121,7 +405,7 @@ func loadAccountCommands() cli.Commands {
 				if c.String("eth.address") == "" {
 					return errors.New("eth.address flag is empty")
 				}
-				cl := client.NewClient(url)
+				cl := newClient()
 				resp, err := cl.GetAccount(c.String("eth.address"))
 				if err != nil {
 					return err
@@ -150,8 +434,14 @@ func loadAccountCommands() cli.Commands {
 					Hidden: true,
 					Usage:  "return all accounts in a paginated format",
 					Action: func(c *cli.Context) error {
-						cl := client.NewClient(url)
-						accts, err := cl.GetAccounts(c.String("page.size"), c.String("page.num"))
+						cl := newClient()
+						req := client.NewAccountRequest().
+							MaxHealth(c.String("max.health")).
+							MinBorrowValueInEth(c.String("min.borrow")).
+							BlockNumber(uint32(c.Uint("block.number"))).
+							PageSize(uint32(c.Uint("page.size"))).
+							PageNumber(uint32(c.Uint("page.num")))
+						accts, err := cl.QueryAccounts(context.Background(), req)
 						if err != nil {
 							return err
 						}
@@ -159,14 +449,26 @@ func loadAccountCommands() cli.Commands {
 						return nil
 					},
 					Flags: []cli.Flag{
-						&cli.StringFlag{
+						&cli.UintFlag{
 							Name:  "page.size",
 							Usage: "number of entries per page",
 						},
-						&cli.StringFlag{
+						&cli.UintFlag{
 							Name:  "page.num",
 							Usage: "page number to display",
 						},
+						&cli.StringFlag{
+							Name:  "max.health",
+							Usage: "only return accounts with a health below this value",
+						},
+						&cli.StringFlag{
+							Name:  "min.borrow",
+							Usage: "only return accounts borrowing more than this value in eth",
+						},
+						&cli.UintFlag{
+							Name:  "block.number",
+							Usage: "return historical data as of this block",
+						},
 					},
 				},
 				cli.Command{
@@ -176,12 +478,15 @@ func loadAccountCommands() cli.Commands {
 						if c.String("eth.address") == "" {
 							return errors.New("eth.address flag is empty")
 						}
-						cl := client.NewClient(url)
-						value, err := cl.GetTotalCollateralValueInEth(c.String("eth.address"))
+						src, err := newSource(c)
 						if err != nil {
 							return err
 						}
-						fmt.Println("collateral value: ", value)
+						acct, err := src.Account(context.Background(), c.String("eth.address"))
+						if err != nil {
+							return err
+						}
+						fmt.Println("collateral value: ", acct.TotalCollateralValueInEth.Value)
 						return nil
 					},
 					Flags: []cli.Flag{
@@ -198,12 +503,15 @@ func loadAccountCommands() cli.Commands {
 						if c.String("eth.address") == "" {
 							return errors.New("eth.address flag is empty")
 						}
-						cl := client.NewClient(url)
-						value, err := cl.GetTotalBorrowValueInEth(c.String("eth.address"))
+						src, err := newSource(c)
+						if err != nil {
+							return err
+						}
+						acct, err := src.Account(context.Background(), c.String("eth.address"))
 						if err != nil {
 							return err
 						}
-						fmt.Println("borrow value: ", value)
+						fmt.Println("borrow value: ", acct.TotalBorrowValueInEth.Value)
 						return nil
 					},
 					Flags: []cli.Flag{
@@ -220,19 +528,15 @@ func loadAccountCommands() cli.Commands {
 						if c.String("eth.address") == "" {
 							return errors.New("eth.address flag is empty")
 						}
-						cl := client.NewClient(url)
-						resp, err := cl.GetAccount(c.String("eth.address"))
+						src, err := newSource(c)
 						if err != nil {
 							return err
 						}
-						if len(resp.Accounts) == 0 {
-							return errors.New("an unexpected error occurred")
-						}
-						health, err := strconv.ParseFloat(resp.Accounts[0].Health.Value, 64)
+						acct, err := src.Account(context.Background(), c.String("eth.address"))
 						if err != nil {
 							return err
 						}
-						fmt.Println("account health: ", health)
+						fmt.Println("account health: ", acct.Health.Value)
 						return nil
 					},
 					Flags: []cli.Flag{
@@ -242,6 +546,80 @@ func loadAccountCommands() cli.Commands {
 						},
 					},
 				},
+				cli.Command{
+					Name:  "watch",
+					Usage: "watch the health of accounts, logging every change of state",
+					Action: func(c *cli.Context) error {
+						addresses := c.StringSlice("eth.address")
+						if len(addresses) == 0 {
+							return errors.New("eth.address flag is empty")
+						}
+						src, err := newSource(c)
+						if err != nil {
+							return err
+						}
+						router, err := newRouter(c)
+						if err != nil {
+							return err
+						}
+						watcher := client.NewPortfolioWatcher(src, client.WithDefaultInterval(c.Duration("interval")))
+						for _, address := range addresses {
+							watcher.Add(client.WatchedAccount{Address: address})
+						}
+						ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+						defer cancel()
+						go watcher.Run(ctx)
+						for event := range watcher.Events() {
+							sendAlert(ctx, router, alert.FromHealthEvent(event))
+						}
+						return nil
+					},
+					Flags: []cli.Flag{
+						cli.StringSliceFlag{
+							Name:  "eth.address, ea",
+							Usage: "the addresses to watch",
+						},
+						cli.DurationFlag{
+							Name:  "interval",
+							Usage: "how often each account is read",
+							Value: client.DefaultWatchInterval,
+						},
+					},
+				},
+				cli.Command{
+					Name:  "monitor",
+					Usage: "monitor the liquidity of accounts on every new block, reading the comptroller directly",
+					Action: func(c *cli.Context) error {
+						addresses := c.StringSlice("eth.address")
+						if len(addresses) == 0 {
+							return errors.New("eth.address flag is empty")
+						}
+						ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+						defer cancel()
+						router, err := newRouter(c)
+						if err != nil {
+							return err
+						}
+						monitor, err := client.DialLiquidityMonitor(ctx, c.GlobalString("eth.rpc"), client.Unitroller.EthAddress())
+						if err != nil {
+							return err
+						}
+						for _, address := range addresses {
+							monitor.Watch(common.HexToAddress(address))
+						}
+						go monitor.Run(ctx)
+						for event := range monitor.Events() {
+							sendAlert(ctx, router, alert.FromLiquidityEvent(event))
+						}
+						return nil
+					},
+					Flags: []cli.Flag{
+						cli.StringSliceFlag{
+							Name:  "eth.address, ea",
+							Usage: "the addresses to monitor",
+						},
+					},
+				},
 				cli.Command{
 					Name:  "supply-interest",
 					Usage: "get supply-interest earned",
@@ -254,22 +632,23 @@ func loadAccountCommands() cli.Commands {
 								return errors.New("token.name flag is empty")
 							}
 						}
-						cl := client.NewClient(url)
-						resp, err := cl.GetAccount(c.String("eth.address"))
+						src, err := newSource(c)
 						if err != nil {
 							return err
 						}
-						if len(resp.Accounts) == 0 {
-							return errors.New("an unexpected error occurred")
-						}
-						var interest float64
+						ctx := context.Background()
+						var interest models.Decimal
 						if !c.Bool("total") {
-							interest, err = cl.GetSupplyInterestEarned(
-								client.CompoundTokens[c.String("token.name")],
-								resp,
+							var token client.Address
+							if token, err = lookupToken(c.String("token.name")); err != nil {
+								return err
+							}
+							interest, err = client.SupplyInterestEarned(
+								ctx, src, c.String("eth.address"),
+								token,
 							)
 						} else {
-							interest, err = cl.GetTotalSupplyInterestedEarned(resp)
+							interest, err = client.TotalSupplyInterestEarned(ctx, src, c.String("eth.address"))
 						}
 						if err != nil {
 							return err
@@ -284,12 +663,16 @@ func loadAccountCommands() cli.Commands {
 						},
 						cli.StringFlag{
 							Name:  "token.name, tn",
-							Usage: "the compound token being supplied",
+							Usage: "the compound token being supplied, by name, symbol or address",
 						},
 						cli.BoolFlag{
 							Name:  "total",
 							Usage: "whether or not to collect total interest",
 						},
+						cli.UintFlag{
+							Name:  "block.number",
+							Usage: "return historical data as of this block",
+						},
 					},
 				},
 				cli.Command{
@@ -302,17 +685,17 @@ func loadAccountCommands() cli.Commands {
 						if c.String("token.name") == "" {
 							return errors.New("token.name flag is empty")
 						}
-						cl := client.NewClient(url)
-						resp, err := cl.GetAccount(c.String("eth.address"))
+						src, err := newSource(c)
 						if err != nil {
 							return err
 						}
-						if len(resp.Accounts) == 0 {
-							return errors.New("an unexpected error occurred")
+						token, err := lookupToken(c.String("token.name"))
+						if err != nil {
+							return err
 						}
-						interest, err := cl.GetBorrowInterestedAccrued(
-							client.CompoundTokens[c.String("token.name")],
-							resp,
+						interest, err := client.BorrowInterestAccrued(
+							context.Background(), src, c.String("eth.address"),
+							token,
 						)
 						if err != nil {
 							return err
@@ -327,7 +710,11 @@ func loadAccountCommands() cli.Commands {
 						},
 						cli.StringFlag{
 							Name:  "token.name, tn",
-							Usage: "the compound token being supplied",
+							Usage: "the compound token being supplied, by name, symbol or address",
+						},
+						cli.UintFlag{
+							Name:  "block.number",
+							Usage: "return historical data as of this block",
 						},
 					},
 				},
@@ -336,8 +723,11 @@ func loadAccountCommands() cli.Commands {
 					Aliases: []string{"liqqable"},
 					Usage:   "get all liquidatable accounts",
 					Action: func(c *cli.Context) error {
-						cl := client.NewClient(url)
-						accts, err := cl.GetLiquidatableAccounts("", "")
+						src, err := newSource(c)
+						if err != nil {
+							return err
+						}
+						accts, err := client.LiquidatableAccounts(context.Background(), src)
 						if err != nil {
 							return err
 						}
@@ -346,6 +736,12 @@ func loadAccountCommands() cli.Commands {
 						}
 						return nil
 					},
+					Flags: []cli.Flag{
+						cli.UintFlag{
+							Name:  "block.number",
+							Usage: "return historical data as of this block",
+						},
+					},
 				},
 			},
 			Flags: []cli.Flag{
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/musinit/go-defi/v2/mockapi"
+	"github.com/urfave/cli"
+)
+
+// gcomp-mockapi serves the mock compound api over http, so that gcomp-cli and
+// other clients can be run locally against deterministic data, ex.
+//
+//	gcomp-mockapi --listen 127.0.0.1:8080 &
+//	gcomp-cli --api.url http://127.0.0.1:8080 account health --eth.address 0x...
+func main() {
+	app := cli.NewApp()
+	app.Name = "gcomp-mockapi"
+	app.Usage = "serve the mock compound api from fixture files"
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "listen, l",
+			Usage: "the address to listen on",
+			Value: "127.0.0.1:8080",
+		},
+		cli.StringFlag{
+			Name:  "accounts",
+			Usage: "an AccountResponse json file replacing the embedded account fixtures",
+		},
+		cli.StringFlag{
+			Name:  "ctokens",
+			Usage: "a CTokenResponse json file replacing the embedded ctoken fixtures",
+		},
+	}
+	app.Action = func(c *cli.Context) error {
+		handler := mockapi.NewHandler()
+		if err := loadFile(c.String("accounts"), handler.LoadAccounts); err != nil {
+			return err
+		}
+		if err := loadFile(c.String("ctokens"), handler.LoadCTokens); err != nil {
+			return err
+		}
+		log.Println("serving mock compound api on", c.String("listen"))
+		return http.ListenAndServe(c.String("listen"), handler)
+	}
+	if err := app.Run(os.Args); err != nil {
+		log.Println("error running application: ", err.Error())
+		os.Exit(1)
+	}
+}
+
+func loadFile(path string, load func(r io.Reader) error) error {
+	if path == "" {
+		return nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return load(file)
+}
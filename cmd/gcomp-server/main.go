@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/musinit/go-defi/v2/client"
+	"github.com/musinit/go-defi/v2/compound"
+	"github.com/musinit/go-defi/v2/server"
+	"github.com/urfave/cli"
+	"google.golang.org/grpc"
+)
+
+// gcomp-server serves the compound AccountService and CTokenService computed
+// from on chain reads, over REST and gRPC, ex.
+//
+//	gcomp-server --eth.rpc http://localhost:8545 --accounts 0x... &
+//	gcomp-cli --api.url http://127.0.0.1:8080 account health --eth.address 0x...
+func main() {
+	app := cli.NewApp()
+	app.Name = "gcomp-server"
+	app.Usage = "serve a compound compatible api from on chain data"
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "listen, l",
+			Usage: "the address to serve the REST api on",
+			Value: "127.0.0.1:8080",
+		},
+		cli.StringFlag{
+			Name:  "grpc.listen, gl",
+			Usage: "the address to serve the gRPC api on, disabled if empty",
+			Value: "127.0.0.1:9090",
+		},
+		cli.StringFlag{
+			Name:  "eth.rpc, er",
+			Usage: "endpoint for JSON-RPC access, an archive node is needed for block_number requests",
+			Value: "http://localhost:8545",
+		},
+		cli.StringFlag{
+			Name:  "comptroller",
+			Usage: "the address of the comptroller, or its unitroller proxy",
+			Value: client.Unitroller.String(),
+		},
+		cli.StringFlag{
+			Name:  "ether",
+			Usage: "the address of the ether market of the comptroller",
+			Value: client.CompoundETH.String(),
+		},
+		cli.StringSliceFlag{
+			Name:  "accounts",
+			Usage: "accounts served by requests that do not filter on addresses",
+		},
+		cli.Int64Flag{
+			Name:  "blocks.per.year",
+			Usage: "the number of blocks used to annualize supply and borrow rates",
+			Value: server.DefaultBlocksPerYear,
+		},
+	}
+	app.Action = func(c *cli.Context) error {
+		if !common.IsHexAddress(c.String("comptroller")) {
+			return errors.New("invalid comptroller address")
+		}
+		if !common.IsHexAddress(c.String("ether")) {
+			return errors.New("invalid ether market address")
+		}
+		for _, address := range c.StringSlice("accounts") {
+			if !common.IsHexAddress(address) {
+				return errors.New("invalid account address " + address)
+			}
+		}
+		eth, err := ethclient.Dial(c.String("eth.rpc"))
+		if err != nil {
+			return err
+		}
+		defer eth.Close()
+		backend, err := compound.NewChainBackend(eth, common.HexToAddress(c.String("comptroller")), common.HexToAddress(c.String("ether")))
+		if err != nil {
+			return err
+		}
+		srv := server.New(
+			backend,
+			server.WithAccounts(c.StringSlice("accounts")...),
+			server.WithBlocksPerYear(c.Int64("blocks.per.year")),
+		)
+		errs := make(chan error, 2)
+		if addr := c.String("grpc.listen"); addr != "" {
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			g := grpc.NewServer()
+			srv.Register(g)
+			defer g.Stop()
+			log.Println("serving compound gRPC api on", addr)
+			go func() { errs <- g.Serve(listener) }()
+		}
+		log.Println("serving compound REST api on", c.String("listen"))
+		go func() { errs <- http.ListenAndServe(c.String("listen"), srv) }()
+		return <-errs
+	}
+	if err := app.Run(os.Args); err != nil {
+		log.Println("error running application: ", err.Error())
+		os.Exit(1)
+	}
+}
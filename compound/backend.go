@@ -0,0 +1,276 @@
+package compound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ctoken "github.com/musinit/go-defi/v2/bindings/cbat"
+	comptroller "github.com/musinit/go-defi/v2/bindings/comptroller"
+	oracle "github.com/musinit/go-defi/v2/bindings/price_oracle"
+	erc20 "github.com/musinit/go-defi/v2/bindings/usdc"
+)
+
+// Backend is used to read the state of a compound deployment at a given
+// block. Every amount is returned as the raw integer stored on chain, the
+// Reader takes care of scaling them by the relevant decimals
+type Backend interface {
+	// BlockNumber returns the latest block number
+	BlockNumber(ctx context.Context) (uint64, error)
+	// Comptroller returns the protocol wide parameters and listed markets
+	Comptroller(ctx context.Context, block uint64) (*ComptrollerState, error)
+	// Market returns the state of a single ctoken market
+	Market(ctx context.Context, block uint64, ctoken common.Address) (*Market, error)
+	// AssetsIn returns the markets the account entered, ie. uses as collateral
+	// or borrows from
+	AssetsIn(ctx context.Context, block uint64, account common.Address) ([]common.Address, error)
+	// AccountSnapshot returns the balances of the account in a single market
+	AccountSnapshot(ctx context.Context, block uint64, account, ctoken common.Address) (*AccountSnapshot, error)
+}
+
+// ComptrollerState is the protocol wide state held by the comptroller
+type ComptrollerState struct {
+	// CloseFactor and LiquidationIncentive are mantissas scaled by 1e18
+	CloseFactor          *big.Int
+	LiquidationIncentive *big.Int
+	Markets              []common.Address
+}
+
+// Market is the on chain state of a ctoken market
+type Market struct {
+	Address           common.Address
+	Symbol            string
+	Name              string
+	Decimals          uint8
+	InterestRateModel common.Address
+	// Underlying is the zero address for the ether market
+	Underlying         common.Address
+	UnderlyingSymbol   string
+	UnderlyingName     string
+	UnderlyingDecimals uint8
+	// TotalSupply is denominated in ctokens, TotalBorrows, Reserves and
+	// Cash in the underlying token
+	TotalSupply  *big.Int
+	TotalBorrows *big.Int
+	Reserves     *big.Int
+	Cash         *big.Int
+	// ExchangeRate is scaled by 1e(18 + UnderlyingDecimals - Decimals)
+	ExchangeRate *big.Int
+	// SupplyRatePerBlock, BorrowRatePerBlock and CollateralFactor are
+	// mantissas scaled by 1e18
+	SupplyRatePerBlock *big.Int
+	BorrowRatePerBlock *big.Int
+	CollateralFactor   *big.Int
+	// UnderlyingPrice is the oracle price, scaled by 1e(36 - UnderlyingDecimals)
+	UnderlyingPrice *big.Int
+}
+
+// AccountSnapshot is an account's balances in a single market
+type AccountSnapshot struct {
+	// CTokenBalance is denominated in ctokens, BorrowBalance in the underlying token
+	CTokenBalance *big.Int
+	BorrowBalance *big.Int
+	// ExchangeRate is scaled like Market.ExchangeRate
+	ExchangeRate *big.Int
+}
+
+// ChainClient is the subset of an ethclient.Client used by the ChainBackend
+type ChainClient interface {
+	bind.ContractBackend
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// ChainBackend is a Backend reading through the comptroller, ctoken, price
+// oracle and erc20 bindings
+type ChainBackend struct {
+	client      ChainClient
+	comptroller *comptroller.Bindings
+	ether       common.Address
+
+	mu      sync.Mutex
+	details map[common.Address]*marketDetails
+}
+
+// marketDetails are the market fields that never change once listed
+type marketDetails struct {
+	symbol, name                     string
+	decimals                         uint8
+	underlying                       common.Address
+	underlyingSymbol, underlyingName string
+	underlyingDecimals               uint8
+}
+
+// NewChainBackend returns a backend reading the deployment whose comptroller,
+// usually the unitroller proxy, is at the given address, and whose ether
+// market is at etherAddress
+func NewChainBackend(client ChainClient, comptrollerAddress, etherAddress common.Address) (*ChainBackend, error) {
+	contract, err := comptroller.NewBindings(comptrollerAddress, client)
+	if err != nil {
+		return nil, err
+	}
+	return &ChainBackend{
+		client:      client,
+		comptroller: contract,
+		ether:       etherAddress,
+		details:     make(map[common.Address]*marketDetails),
+	}, nil
+}
+
+func callOpts(ctx context.Context, block uint64) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(block)}
+}
+
+// BlockNumber returns the latest block number
+func (b *ChainBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return b.client.BlockNumber(ctx)
+}
+
+// Comptroller returns the protocol wide parameters and listed markets
+func (b *ChainBackend) Comptroller(ctx context.Context, block uint64) (*ComptrollerState, error) {
+	opts := callOpts(ctx, block)
+	closeFactor, err := b.comptroller.CloseFactorMantissa(opts)
+	if err != nil {
+		return nil, err
+	}
+	incentive, err := b.comptroller.LiquidationIncentiveMantissa(opts)
+	if err != nil {
+		return nil, err
+	}
+	markets, err := b.comptroller.GetAllMarkets(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ComptrollerState{CloseFactor: closeFactor, LiquidationIncentive: incentive, Markets: markets}, nil
+}
+
+// Market returns the state of a single ctoken market
+func (b *ChainBackend) Market(ctx context.Context, block uint64, address common.Address) (*Market, error) {
+	opts := callOpts(ctx, block)
+	contract, err := ctoken.NewBindings(address, b.client)
+	if err != nil {
+		return nil, err
+	}
+	details, err := b.marketDetails(opts, address, contract)
+	if err != nil {
+		return nil, err
+	}
+	market := &Market{
+		Address:            address,
+		Symbol:             details.symbol,
+		Name:               details.name,
+		Decimals:           details.decimals,
+		Underlying:         details.underlying,
+		UnderlyingSymbol:   details.underlyingSymbol,
+		UnderlyingName:     details.underlyingName,
+		UnderlyingDecimals: details.underlyingDecimals,
+	}
+	if market.InterestRateModel, err = contract.InterestRateModel(opts); err != nil {
+		return nil, err
+	}
+	for _, read := range []struct {
+		dest **big.Int
+		call func(*bind.CallOpts) (*big.Int, error)
+	}{
+		{&market.TotalSupply, contract.TotalSupply},
+		{&market.TotalBorrows, contract.TotalBorrows},
+		{&market.Reserves, contract.TotalReserves},
+		{&market.Cash, contract.GetCash},
+		{&market.ExchangeRate, contract.ExchangeRateStored},
+		{&market.SupplyRatePerBlock, contract.SupplyRatePerBlock},
+		{&market.BorrowRatePerBlock, contract.BorrowRatePerBlock},
+	} {
+		if *read.dest, err = read.call(opts); err != nil {
+			return nil, err
+		}
+	}
+	listing, err := b.comptroller.Markets(opts, address)
+	if err != nil {
+		return nil, err
+	}
+	market.CollateralFactor = listing.CollateralFactorMantissa
+	oracleAddress, err := b.comptroller.Oracle(opts)
+	if err != nil {
+		return nil, err
+	}
+	priceOracle, err := oracle.NewBindings(oracleAddress, b.client)
+	if err != nil {
+		return nil, err
+	}
+	if market.UnderlyingPrice, err = priceOracle.GetUnderlyingPrice(opts, address); err != nil {
+		return nil, err
+	}
+	return market, nil
+}
+
+// marketDetails returns the fixed details of a market, reading them only once
+func (b *ChainBackend) marketDetails(opts *bind.CallOpts, address common.Address, contract *ctoken.Bindings) (*marketDetails, error) {
+	b.mu.Lock()
+	details, ok := b.details[address]
+	b.mu.Unlock()
+	if ok {
+		return details, nil
+	}
+	details = &marketDetails{}
+	var err error
+	if details.symbol, err = contract.Symbol(opts); err != nil {
+		return nil, err
+	}
+	if details.name, err = contract.Name(opts); err != nil {
+		return nil, err
+	}
+	decimals, err := contract.Decimals(opts)
+	if err != nil {
+		return nil, err
+	}
+	details.decimals = uint8(decimals.Uint64())
+	if address == b.ether {
+		// the ether market has no underlying token
+		details.underlyingSymbol, details.underlyingName, details.underlyingDecimals = "ETH", "Ether", 18
+	} else {
+		underlying, err := contract.Underlying(opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s underlying: %w", details.symbol, err)
+		}
+		token, err := erc20.NewBindings(underlying, b.client)
+		if err != nil {
+			return nil, err
+		}
+		details.underlying = underlying
+		if details.underlyingDecimals, err = token.Decimals(opts); err != nil {
+			return nil, err
+		}
+		// some tokens, ex. MKR, return their symbol and name as bytes32,
+		// these are left empty rather than failing the whole market
+		details.underlyingSymbol, _ = token.Symbol(opts)
+		details.underlyingName, _ = token.Name(opts)
+	}
+	b.mu.Lock()
+	b.details[address] = details
+	b.mu.Unlock()
+	return details, nil
+}
+
+// AssetsIn returns the markets the account entered
+func (b *ChainBackend) AssetsIn(ctx context.Context, block uint64, account common.Address) ([]common.Address, error) {
+	return b.comptroller.GetAssetsIn(callOpts(ctx, block), account)
+}
+
+// AccountSnapshot returns the balances of the account in a single market
+func (b *ChainBackend) AccountSnapshot(ctx context.Context, block uint64, account, address common.Address) (*AccountSnapshot, error) {
+	contract, err := ctoken.NewBindings(address, b.client)
+	if err != nil {
+		return nil, err
+	}
+	errCode, balance, borrow, rate, err := contract.GetAccountSnapshot(callOpts(ctx, block), account)
+	if err != nil {
+		return nil, err
+	}
+	if errCode.Sign() != 0 {
+		return nil, errors.New("smart contract return a non 0 error code")
+	}
+	return &AccountSnapshot{CTokenBalance: balance, BorrowBalance: borrow, ExchangeRate: rate}, nil
+}
@@ -0,0 +1,236 @@
+// Package compound reads the accounts and markets of a compound v2 deployment
+// from the chain, in the shape of the compound api models. It is shared by the
+// api server and the chain backed AccountSource of the client
+package compound
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/internal/apiutil"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+const (
+	// DefaultBlocksPerYear is used to annualize per block rates, assuming 12s blocks
+	DefaultBlocksPerYear = 2628000
+	// DefaultConcurrency is the number of markets read in parallel
+	DefaultConcurrency = 8
+)
+
+// ReaderOption is used to configure a Reader
+type ReaderOption func(*Reader)
+
+// WithBlocksPerYear sets the number of blocks used to annualize the per block
+// supply and borrow rates
+func WithBlocksPerYear(blocks int64) ReaderOption {
+	return func(r *Reader) {
+		r.blocksPerYear = blocks
+	}
+}
+
+// WithConcurrency sets the number of markets read in parallel
+func WithConcurrency(n int) ReaderOption {
+	return func(r *Reader) {
+		r.concurrency = n
+	}
+}
+
+// Reader derives the api models of accounts and ctoken markets from the raw
+// reads of a Backend. Fields without an on chain equivalent, ex. lifetime
+// interest accrued or the number of suppliers, are left at 0
+type Reader struct {
+	backend       Backend
+	blocksPerYear int64
+	concurrency   int
+}
+
+// NewReader returns a reader of backend
+func NewReader(backend Backend, opts ...ReaderOption) *Reader {
+	r := &Reader{
+		backend:       backend,
+		blocksPerYear: DefaultBlocksPerYear,
+		concurrency:   DefaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.concurrency <= 0 {
+		r.concurrency = DefaultConcurrency
+	}
+	return r
+}
+
+// State is the state of the deployment at the block a request is served at
+type State struct {
+	Block       uint64
+	Comptroller *ComptrollerState
+	// CTokens are the markets read, in the order listed by the comptroller
+	CTokens []models.CToken
+	// EthPrice is the oracle price of ether, values are denominated in ether
+	EthPrice models.Decimal
+
+	markets []*market
+}
+
+// market is a Market along with its scaled values
+type market struct {
+	raw              *Market
+	ctoken           models.CToken
+	exchangeRateUnit int
+}
+
+// market returns the market at address, or nil if it was not read
+func (st *State) market(address common.Address) *market {
+	for _, m := range st.markets {
+		if m.raw.Address == address {
+			return m
+		}
+	}
+	return nil
+}
+
+// State returns the state at the given block, or the latest one if block is
+// 0, with the markets restricted to the given addresses if any
+func (r *Reader) State(ctx context.Context, block uint64, only []string) (*State, error) {
+	if block == 0 {
+		latest, err := r.backend.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		block = latest
+	}
+	comptroller, err := r.backend.Comptroller(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	raw := make([]*Market, len(comptroller.Markets))
+	err = apiutil.Parallel(ctx, len(comptroller.Markets), r.concurrency, func(ctx context.Context, i int) error {
+		m, err := r.backend.Market(ctx, block, comptroller.Markets[i])
+		if err != nil {
+			return fmt.Errorf("market %s: %w", hexAddress(comptroller.Markets[i]), err)
+		}
+		raw[i] = m
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// prices are reported in the unit of the oracle, usually usd, while the
+	// api denominates them in ether
+	st := &State{Block: block, Comptroller: comptroller, EthPrice: models.NewDecimal(1)}
+	for _, m := range raw {
+		if m.Underlying == (common.Address{}) && m.UnderlyingPrice != nil && m.UnderlyingPrice.Sign() > 0 {
+			st.EthPrice = models.NewDecimalFromInt(m.UnderlyingPrice, 18)
+		}
+	}
+	for _, m := range raw {
+		if len(only) > 0 && !containsAddress(only, m.Address) {
+			continue
+		}
+		market := r.market(m, st.EthPrice)
+		st.markets = append(st.markets, market)
+		st.CTokens = append(st.CTokens, market.ctoken)
+	}
+	return st, nil
+}
+
+func (r *Reader) market(m *Market, ethPrice models.Decimal) *market {
+	rateUnit := 18 + int(m.UnderlyingDecimals) - int(m.Decimals)
+	underlying := ""
+	if m.Underlying != (common.Address{}) {
+		underlying = hexAddress(m.Underlying)
+	}
+	blocksPerYear := models.NewDecimal(r.blocksPerYear)
+	price := models.NewDecimalFromInt(m.UnderlyingPrice, 36-int(m.UnderlyingDecimals)).Quo(ethPrice)
+	return &market{
+		raw:              m,
+		exchangeRateUnit: rateUnit,
+		ctoken: models.CToken{
+			TokenAddress:             hexAddress(m.Address),
+			Symbol:                   m.Symbol,
+			Name:                     m.Name,
+			InterestRateModelAddress: hexAddress(m.InterestRateModel),
+			UnderlyingAddress:        underlying,
+			UnderlyingSymbol:         m.UnderlyingSymbol,
+			UnderlyingName:           m.UnderlyingName,
+			TotalSupply:              value(models.NewDecimalFromInt(m.TotalSupply, int(m.Decimals))),
+			TotalBorrows:             value(models.NewDecimalFromInt(m.TotalBorrows, int(m.UnderlyingDecimals))),
+			Reserves:                 value(models.NewDecimalFromInt(m.Reserves, int(m.UnderlyingDecimals))),
+			Cash:                     value(models.NewDecimalFromInt(m.Cash, int(m.UnderlyingDecimals))),
+			ExchangeRate:             value(models.NewDecimalFromInt(m.ExchangeRate, rateUnit)),
+			SupplyRate:               value(models.NewDecimalFromInt(m.SupplyRatePerBlock, 18).Mul(blocksPerYear)),
+			BorrowRate:               value(models.NewDecimalFromInt(m.BorrowRatePerBlock, 18).Mul(blocksPerYear)),
+			CollateralFactor:         value(models.NewDecimalFromInt(m.CollateralFactor, 18)),
+			UnderlyingPrice:          value(price.Round(18)),
+		},
+	}
+}
+
+// Account reads the balances of an account in the markets it entered, and
+// derives its collateral and borrow values. Borrowing enters a market, so
+// every borrow is read, while supplies to markets the account did not enter
+// are not collateral and are not read. The state must hold every market
+func (r *Reader) Account(ctx context.Context, st *State, address common.Address) (*models.Account, error) {
+	assetsIn, err := r.backend.AssetsIn(ctx, st.Block, address)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		blockUpdated = int(st.Block)
+		acct         = &models.Account{Address: hexAddress(address), BlockUpdated: &blockUpdated, Tokens: []models.AccountToken{}}
+		collateral   models.Decimal
+		borrowed     models.Decimal
+	)
+	for _, asset := range assetsIn {
+		m := st.market(asset)
+		if m == nil {
+			return nil, fmt.Errorf("entered market %s is not listed", hexAddress(asset))
+		}
+		snapshot, err := r.backend.AccountSnapshot(ctx, st.Block, address, asset)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.raw.Symbol, err)
+		}
+		if snapshot.CTokenBalance.Sign() == 0 && snapshot.BorrowBalance.Sign() == 0 {
+			continue
+		}
+		cTokens := models.NewDecimalFromInt(snapshot.CTokenBalance, int(m.raw.Decimals))
+		token := models.AccountToken{
+			Address:                 m.ctoken.TokenAddress,
+			Symbol:                  m.raw.Symbol,
+			SupplyBalanceUnderlying: value(cTokens.Mul(models.NewDecimalFromInt(snapshot.ExchangeRate, m.exchangeRateUnit))),
+			BorrowBalanceUnderlying: value(models.NewDecimalFromInt(snapshot.BorrowBalance, int(m.raw.UnderlyingDecimals))),
+		}
+		acct.Tokens = append(acct.Tokens, token)
+		borrowed = borrowed.Add(token.BorrowValueInEth(m.ctoken))
+		collateral = collateral.Add(token.CollateralValueInEth(m.ctoken))
+	}
+	acct.TotalCollateralValueInEth = value(collateral)
+	acct.TotalBorrowValueInEth = value(borrowed)
+	// accounts without borrows can not be liquidated, their health is left at 0
+	if !borrowed.IsZero() {
+		acct.Health = value(collateral.Quo(borrowed).Round(18))
+	}
+	return acct, nil
+}
+
+func value(d models.Decimal) models.Value {
+	return models.Value{Value: d}
+}
+
+// hexAddress formats an address as lower case hex, like the compound api
+func hexAddress(address common.Address) string {
+	return strings.ToLower(address.Hex())
+}
+
+func containsAddress(addresses []string, address common.Address) bool {
+	for _, a := range addresses {
+		if common.HexToAddress(a) == address {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package compound
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	cETH = common.HexToAddress("0xc1")
+	cDAI = common.HexToAddress("0xc2")
+)
+
+// exp returns x * 10^n
+func exp(x int64, n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(x), new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil))
+}
+
+// fakeBackend lists an ether market priced at 2000 and a dai market priced
+// at 1, the account supplies 1 ether to each market and borrows 500 dai
+type fakeBackend struct {
+	assetsIn  []common.Address
+	snapshots []common.Address
+}
+
+func (f *fakeBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return 7, nil
+}
+
+func (f *fakeBackend) Comptroller(ctx context.Context, block uint64) (*ComptrollerState, error) {
+	return &ComptrollerState{CloseFactor: exp(5, 17), LiquidationIncentive: exp(108, 16), Markets: []common.Address{cETH, cDAI}}, nil
+}
+
+func (f *fakeBackend) Market(ctx context.Context, block uint64, address common.Address) (*Market, error) {
+	m := &Market{
+		Address:            address,
+		Symbol:             "cDAI",
+		Decimals:           8,
+		UnderlyingDecimals: 18,
+		Underlying:         common.HexToAddress("0xd2"),
+		TotalSupply:        new(big.Int),
+		TotalBorrows:       new(big.Int),
+		Reserves:           new(big.Int),
+		Cash:               new(big.Int),
+		ExchangeRate:       exp(2, 26),
+		SupplyRatePerBlock: new(big.Int),
+		BorrowRatePerBlock: new(big.Int),
+		CollateralFactor:   exp(75, 16),
+		UnderlyingPrice:    exp(1, 18),
+	}
+	if address == cETH {
+		m.Symbol, m.Underlying, m.UnderlyingPrice = "cETH", common.Address{}, exp(2000, 18)
+	}
+	return m, nil
+}
+
+func (f *fakeBackend) AssetsIn(ctx context.Context, block uint64, account common.Address) ([]common.Address, error) {
+	return f.assetsIn, nil
+}
+
+func (f *fakeBackend) AccountSnapshot(ctx context.Context, block uint64, account, ctoken common.Address) (*AccountSnapshot, error) {
+	f.snapshots = append(f.snapshots, ctoken)
+	snapshot := &AccountSnapshot{CTokenBalance: exp(50, 8), BorrowBalance: new(big.Int), ExchangeRate: exp(2, 26)}
+	if ctoken == cDAI {
+		snapshot.BorrowBalance = exp(500, 18)
+	}
+	return snapshot, nil
+}
+
+func Test_Reader(t *testing.T) {
+	backend := &fakeBackend{assetsIn: []common.Address{cETH}}
+	r := NewReader(backend, WithBlocksPerYear(1000))
+	ctx := context.Background()
+
+	st, err := r.State(ctx, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), st.Block)
+	assert.Equal(t, "2000", st.EthPrice.String())
+	require.Len(t, st.CTokens, 2)
+	assert.Equal(t, "0.0005", st.CTokens[1].UnderlyingPrice.Value.String())
+
+	// only the entered markets are read
+	acct, err := r.Account(ctx, st, common.HexToAddress("0xa1"))
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{cETH}, backend.snapshots)
+	require.Len(t, acct.Tokens, 1)
+	assert.Equal(t, "0.75", acct.TotalCollateralValueInEth.Value.String())
+	assert.True(t, acct.Health.Value.IsZero())
+
+	backend.assetsIn, backend.snapshots = []common.Address{cETH, cDAI}, nil
+	acct, err = r.Account(ctx, st, common.HexToAddress("0xa1"))
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{cETH, cDAI}, backend.snapshots)
+	assert.Equal(t, "0.25", acct.TotalBorrowValueInEth.Value.String())
+	assert.Equal(t, "3.0015", acct.Health.Value.String())
+
+	only, err := r.State(ctx, 0, []string{cETH.Hex()})
+	require.NoError(t, err)
+	require.Len(t, only.CTokens, 1)
+	_, err = r.Account(ctx, only, common.HexToAddress("0xa1"))
+	assert.Error(t, err)
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // Config is used to configure the go-compound api client
 type Config struct {
 	// the ethereum address you use for compound
@@ -8,6 +10,7 @@ type Config struct {
 	// ex. cSAI key could be used to return the cSAI contract address
 	Contracts  map[string]string `yaml:"contracts"`
 	Blockchain `yaml:"blockchain"`
+	Alerts     Alerts `yaml:"alerts"`
 }
 
 // Blockchain provides configuration information for any blockchian
@@ -20,3 +23,85 @@ type Blockchain struct {
 	// the password to unlock the json keyfile
 	KeyPass string `yaml:"key_pass"`
 }
+
+// Alerts configures where watcher alerts are sent
+type Alerts struct {
+	// Sinks are the destinations of alerts, every alert is routed to all
+	// sinks accepting its severity
+	Sinks []AlertSink `yaml:"sinks"`
+	// Escalation raises warn alerts that are not resolved in time to critical
+	Escalation Escalation `yaml:"escalation"`
+}
+
+// AlertSink configures a single alert destination
+type AlertSink struct {
+	// Name identifies the sink in errors and stats
+	Name string `yaml:"name"`
+	// Type is one of webhook, smtp, command or file
+	Type string `yaml:"type"`
+	// MinSeverity is the lowest severity sent to the sink, one of info, warn
+	// or critical, defaults to info
+	MinSeverity string `yaml:"min_severity"`
+	// RateLimit caps the alerts sent to the sink, alerts over the limit are dropped
+	RateLimit RateLimit `yaml:"rate_limit"`
+	// Dedup suppresses alerts with the same key and severity as one sent
+	// within this window, ex. 10m
+	Dedup time.Duration `yaml:"dedup"`
+
+	Webhook Webhook `yaml:"webhook"`
+	SMTP    SMTP    `yaml:"smtp"`
+	Command Command `yaml:"command"`
+	File    File    `yaml:"file"`
+}
+
+// RateLimit allows up to Events alerts per Interval, a zero Events is unlimited.
+// Interval must be positive when Events is set
+type RateLimit struct {
+	Events   int           `yaml:"events"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// Escalation configures the escalation of warn alerts to critical. An alert
+// key still at warn after After, or warned about Count times, is escalated.
+// Zero values disable each of them
+type Escalation struct {
+	After time.Duration `yaml:"after"`
+	Count int           `yaml:"count"`
+}
+
+// Webhook posts alerts to an http endpoint
+type Webhook struct {
+	URL string `yaml:"url"`
+	// Method defaults to POST
+	Method  string            `yaml:"method"`
+	Headers map[string]string `yaml:"headers"`
+	// Template is a text/template rendering the json payload from the alert,
+	// the alert itself is sent as json when empty
+	Template string `yaml:"template"`
+}
+
+// SMTP emails alerts
+type SMTP struct {
+	// Addr is the host:port of the mail server
+	Addr     string   `yaml:"addr"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+	// Timeout bounds a whole smtp session, it defaults to 30s
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Command runs a program for every alert, with the alert as json on its
+// standard input and in ALERT_* environment variables
+type Command struct {
+	Path string   `yaml:"path"`
+	Args []string `yaml:"args"`
+	// Timeout defaults to 30s
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// File appends alerts as json lines to a local file
+type File struct {
+	Path string `yaml:"path"`
+}
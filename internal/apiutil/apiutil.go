@@ -0,0 +1,184 @@
+// Package apiutil contains the request handling shared by the clients and the
+// servers of the compound api, so that the mock api and the self hosted server
+// paginate and parse requests the same way
+package apiutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/musinit/go-defi/v2/models"
+)
+
+const (
+	// DefaultPageSize is the page size used when a request does not set one
+	DefaultPageSize = 10
+	// MaxPageSize is the largest page size accepted, larger sizes are
+	// rejected with an INVALID_PAGE_SIZE error
+	MaxPageSize = 1000
+)
+
+// Paginate returns the summary and bounds of the requested page of total
+// entries, or an api error if the page size or number is out of range
+func Paginate(total, size, number int) (models.PaginationSummary, int, int, *models.APIError) {
+	if size == 0 {
+		size = DefaultPageSize
+	}
+	if number == 0 {
+		number = 1
+	}
+	if size < 0 || size > MaxPageSize {
+		return models.PaginationSummary{}, 0, 0, &models.APIError{
+			Code:        models.InvalidPageSize,
+			FieldErrors: map[string]string{"page_size": fmt.Sprintf("must be between 1 and %d", MaxPageSize)},
+		}
+	}
+	pages := (total + size - 1) / size
+	if number < 0 || (number > pages && !(number == 1 && pages == 0)) {
+		return models.PaginationSummary{}, 0, 0, &models.APIError{
+			Code:        models.InvalidPageNumber,
+			FieldErrors: map[string]string{"page_number": fmt.Sprintf("must be between 1 and %d", pages)},
+		}
+	}
+	start := (number - 1) * size
+	end := start + size
+	if end > total {
+		end = total
+	}
+	return models.PaginationSummary{
+		PageNumber:   number,
+		PageSize:     size,
+		TotalEntries: total,
+		TotalPages:   pages,
+	}, start, end, nil
+}
+
+// ParseInt parses the query parameter key into dest, leaving dest as is when
+// the parameter is not set
+func ParseInt(query url.Values, key string, dest *int) error {
+	value := query.Get(key)
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	*dest = n
+	return nil
+}
+
+// Parallel calls fn for 0 <= i < n, with at most concurrency calls running at
+// once. The first error cancels the context of the other calls and is
+// returned, no call is started once the context is done
+func Parallel(ctx context.Context, n, concurrency int, fn func(ctx context.Context, i int) error) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		sem      = make(chan struct{}, concurrency)
+		wg       = &sync.WaitGroup{}
+		once     sync.Once
+		firstErr error
+	)
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// ParseAccountRequest reads an account request from the json body of a POST,
+// or the query of a GET, ex. ?addresses[]=0x..&max_health[value]=1.0. The
+// returned field errors are keyed by the invalid parameter
+func ParseAccountRequest(r *http.Request) (*models.Request, map[string]string) {
+	req := &models.Request{}
+	fieldErrors := map[string]string{}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			fieldErrors["body"] = err.Error()
+		}
+		return req, fieldErrors
+	}
+	query := r.URL.Query()
+	req.Addresses = query["addresses[]"]
+	for key, dest := range map[string]**models.Value{
+		"min_borrow_value_in_eth": &req.MinBorrowValueInEth,
+		"max_health":              &req.MaxHealth,
+	} {
+		value := query.Get(key + "[value]")
+		if value == "" {
+			continue
+		}
+		number, err := models.ParseDecimal(value)
+		if err != nil {
+			fieldErrors[key] = err.Error()
+			continue
+		}
+		*dest = &models.Value{Value: number}
+	}
+	for key, dest := range map[string]*int{
+		"block_number":    &req.BlockNumber,
+		"block_timestamp": &req.BlockTimestamp,
+		"page_size":       &req.PageSize,
+		"page_number":     &req.PageNumber,
+	} {
+		if err := ParseInt(query, key, dest); err != nil {
+			fieldErrors[key] = err.Error()
+		}
+	}
+	return req, fieldErrors
+}
+
+// ParseCTokenRequest reads a ctoken request from the json body of a POST, or
+// the query of a GET
+func ParseCTokenRequest(r *http.Request) (*models.CTokenRequest, map[string]string) {
+	req := &models.CTokenRequest{}
+	fieldErrors := map[string]string{}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			fieldErrors["body"] = err.Error()
+		}
+		return req, fieldErrors
+	}
+	query := r.URL.Query()
+	req.Addresses = query["addresses[]"]
+	for key, dest := range map[string]*int{
+		"block_number":    &req.BlockNumber,
+		"block_timestamp": &req.BlockTimestamp,
+	} {
+		if err := ParseInt(query, key, dest); err != nil {
+			fieldErrors[key] = err.Error()
+		}
+	}
+	return req, fieldErrors
+}
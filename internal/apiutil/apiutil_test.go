@@ -0,0 +1,119 @@
+package apiutil
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/musinit/go-defi/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Paginate(t *testing.T) {
+	summary, start, end, apiErr := Paginate(25, 0, 0)
+	require.Nil(t, apiErr)
+	assert.Equal(t, models.PaginationSummary{PageNumber: 1, PageSize: DefaultPageSize, TotalEntries: 25, TotalPages: 3}, summary)
+	assert.Equal(t, []int{0, 10}, []int{start, end})
+	_, start, end, apiErr = Paginate(25, 10, 3)
+	require.Nil(t, apiErr)
+	assert.Equal(t, []int{20, 25}, []int{start, end})
+	// an empty result still has a first page
+	_, start, end, apiErr = Paginate(0, 10, 1)
+	require.Nil(t, apiErr)
+	assert.Equal(t, []int{0, 0}, []int{start, end})
+
+	_, _, _, apiErr = Paginate(25, MaxPageSize+1, 1)
+	require.NotNil(t, apiErr)
+	assert.Equal(t, models.InvalidPageSize, apiErr.Code)
+	_, _, _, apiErr = Paginate(25, 10, 4)
+	require.NotNil(t, apiErr)
+	assert.Equal(t, models.InvalidPageNumber, apiErr.Code)
+}
+
+func Test_ParseInt(t *testing.T) {
+	query := url.Values{"page_size": {"25"}, "page_number": {"two"}}
+	size, number, block := 0, 1, 7
+	require.NoError(t, ParseInt(query, "page_size", &size))
+	assert.Equal(t, 25, size)
+	assert.Error(t, ParseInt(query, "page_number", &number))
+	assert.Equal(t, 1, number)
+	require.NoError(t, ParseInt(query, "block_number", &block))
+	assert.Equal(t, 7, block)
+}
+
+func Test_Parallel(t *testing.T) {
+	var running, peak, calls int32
+	err := Parallel(context.Background(), 20, 3, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&calls, 1)
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(20), calls)
+	assert.LessOrEqual(t, peak, int32(3))
+
+	failure := errors.New("failed")
+	calls = 0
+	err = Parallel(context.Background(), 100, 1, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 2 {
+			return failure
+		}
+		return nil
+	})
+	assert.Equal(t, failure, err)
+	// no call is started once a call failed
+	assert.Equal(t, int32(3), calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = Parallel(ctx, 5, 2, func(ctx context.Context, i int) error { return nil })
+	assert.Equal(t, context.Canceled, err)
+}
+
+func Test_ParseAccountRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/account?addresses[]=0xa1&addresses[]=0xa2&max_health[value]=1.5&page_size=20", nil)
+	req, fieldErrors := ParseAccountRequest(r)
+	require.Empty(t, fieldErrors)
+	assert.Equal(t, []string{"0xa1", "0xa2"}, req.Addresses)
+	require.NotNil(t, req.MaxHealth)
+	assert.Equal(t, "1.5", req.MaxHealth.Value.String())
+	assert.Nil(t, req.MinBorrowValueInEth)
+	assert.Equal(t, 20, req.PageSize)
+
+	r = httptest.NewRequest(http.MethodGet, "/account?min_borrow_value_in_eth[value]=x&block_number=y", nil)
+	_, fieldErrors = ParseAccountRequest(r)
+	assert.Contains(t, fieldErrors, "min_borrow_value_in_eth")
+	assert.Contains(t, fieldErrors, "block_number")
+
+	r = httptest.NewRequest(http.MethodPost, "/account", strings.NewReader(`{"addresses":["0xa1"],"page_number":2}`))
+	req, fieldErrors = ParseAccountRequest(r)
+	require.Empty(t, fieldErrors)
+	assert.Equal(t, []string{"0xa1"}, req.Addresses)
+	assert.Equal(t, 2, req.PageNumber)
+}
+
+func Test_ParseCTokenRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/ctoken?addresses[]=0xc1&block_number=9000000", nil)
+	req, fieldErrors := ParseCTokenRequest(r)
+	require.Empty(t, fieldErrors)
+	assert.Equal(t, []string{"0xc1"}, req.Addresses)
+	assert.Equal(t, 9000000, req.BlockNumber)
+
+	r = httptest.NewRequest(http.MethodPost, "/ctoken", strings.NewReader(`{`))
+	_, fieldErrors = ParseCTokenRequest(r)
+	assert.Contains(t, fieldErrors, "body")
+}
@@ -0,0 +1,260 @@
+// Package mockapi provides an in-process mock of the compound.finance api,
+// serving the AccountService and CTokenService from fixture files so that
+// clients can be exercised without network access
+package mockapi
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/musinit/go-defi/v2/internal/apiutil"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+const (
+	// DefaultPageSize is the page size used when a request does not set one
+	DefaultPageSize = apiutil.DefaultPageSize
+	// MaxPageSize is the largest page size accepted, larger sizes are
+	// rejected with an INVALID_PAGE_SIZE error
+	MaxPageSize = apiutil.MaxPageSize
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Server is a running mock api, its URL is used as the base url of a client
+type Server struct {
+	*httptest.Server
+	*Handler
+}
+
+// New starts a mock api serving the embedded fixtures
+func New() *Server {
+	h := NewHandler()
+	return &Server{Server: httptest.NewServer(h), Handler: h}
+}
+
+// Handler serves the mock api. It is safe for concurrent use, and its data
+// may be replaced or modified while it is serving requests
+type Handler struct {
+	mu       sync.Mutex
+	accounts models.AccountResponse
+	ctokens  models.CTokenResponse
+	failures []int
+	requests int
+}
+
+// NewHandler returns a handler serving the embedded fixtures
+func NewHandler() *Handler {
+	h := &Handler{}
+	for name, load := range map[string]func(io.Reader) error{
+		"fixtures/account.json": h.LoadAccounts,
+		"fixtures/ctoken.json":  h.LoadCTokens,
+	} {
+		file, err := fixtures.Open(name)
+		if err != nil {
+			panic(err)
+		}
+		if err := load(file); err != nil {
+			panic(fmt.Sprintf("mockapi: bad fixture %s: %s", name, err))
+		}
+		file.Close()
+	}
+	return h
+}
+
+// LoadAccounts replaces the served accounts with an AccountResponse read from r
+func (h *Handler) LoadAccounts(r io.Reader) error {
+	var resp models.AccountResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	h.accounts = resp
+	h.mu.Unlock()
+	return nil
+}
+
+// LoadCTokens replaces the served ctokens with a CTokenResponse read from r
+func (h *Handler) LoadCTokens(r io.Reader) error {
+	var resp models.CTokenResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	h.ctokens = resp
+	h.mu.Unlock()
+	return nil
+}
+
+// SetAccounts replaces the served accounts
+func (h *Handler) SetAccounts(accounts []models.Account) {
+	h.mu.Lock()
+	h.accounts.Accounts = append([]models.Account(nil), accounts...)
+	h.mu.Unlock()
+}
+
+// Accounts returns a copy of the served accounts
+func (h *Handler) Accounts() []models.Account {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]models.Account(nil), h.accounts.Accounts...)
+}
+
+// UpdateAccount calls update with the served account matching address,
+// reporting whether the account exists
+func (h *Handler) UpdateAccount(address string, update func(*models.Account)) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := range h.accounts.Accounts {
+		if strings.EqualFold(h.accounts.Accounts[i].Address, address) {
+			update(&h.accounts.Accounts[i])
+			return true
+		}
+	}
+	return false
+}
+
+// SetHealth sets the health of the served account matching address
+func (h *Handler) SetHealth(address string, health models.Decimal) bool {
+	return h.UpdateAccount(address, func(acct *models.Account) {
+		acct.Health = models.Value{Value: health}
+	})
+}
+
+// FailNext makes the next n requests fail with the given http status. 5xx
+// responses carry an INTERNAL_ERROR error object
+func (h *Handler) FailNext(n, status int) {
+	h.mu.Lock()
+	for i := 0; i < n; i++ {
+		h.failures = append(h.failures, status)
+	}
+	h.mu.Unlock()
+}
+
+// Requests returns the number of requests served so far
+func (h *Handler) Requests() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.requests
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.requests++
+	var failure int
+	if len(h.failures) > 0 {
+		failure, h.failures = h.failures[0], h.failures[1:]
+	}
+	h.mu.Unlock()
+
+	if failure != 0 {
+		var body interface{}
+		if failure >= http.StatusInternalServerError {
+			body = errorBody(models.InternalError, "mock failure", nil)
+		}
+		writeJSON(w, failure, body)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "/account":
+		h.serveAccounts(w, r)
+	case "/ctoken":
+		h.serveCTokens(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) serveAccounts(w http.ResponseWriter, r *http.Request) {
+	req, fieldErrors := apiutil.ParseAccountRequest(r)
+	if len(fieldErrors) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorBody(models.InvalidRequest, "invalid request", fieldErrors))
+		return
+	}
+
+	h.mu.Lock()
+	resp := h.accounts
+	all := append([]models.Account(nil), h.accounts.Accounts...)
+	h.mu.Unlock()
+
+	var matched []models.Account
+	for _, acct := range all {
+		if len(req.Addresses) > 0 && !containsFold(req.Addresses, acct.Address) {
+			continue
+		}
+		// like the api, accounts without borrows have a health of 0 and are
+		// never below a max health
+		if req.MaxHealth != nil && (acct.TotalBorrowValueInEth.Value.IsZero() || acct.Health.Value.GreaterThan(req.MaxHealth.Value)) {
+			continue
+		}
+		if req.MinBorrowValueInEth != nil && acct.TotalBorrowValueInEth.Value.LessThan(req.MinBorrowValueInEth.Value) {
+			continue
+		}
+		matched = append(matched, acct)
+	}
+	summary, start, end, apiErr := apiutil.Paginate(len(matched), req.PageSize, req.PageNumber)
+	if apiErr != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": apiErr})
+		return
+	}
+	resp.Accounts = append([]models.Account{}, matched[start:end]...)
+	resp.PaginationSummary = summary
+	resp.Request = *req
+	resp.Request.PageNumber, resp.Request.PageSize = summary.PageNumber, summary.PageSize
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) serveCTokens(w http.ResponseWriter, r *http.Request) {
+	req, fieldErrors := apiutil.ParseCTokenRequest(r)
+	if len(fieldErrors) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorBody(models.InvalidRequest, "invalid request", fieldErrors))
+		return
+	}
+
+	h.mu.Lock()
+	resp := h.ctokens
+	resp.CToken = nil
+	for _, ctoken := range h.ctokens.CToken {
+		if len(req.Addresses) == 0 || containsFold(req.Addresses, ctoken.TokenAddress) {
+			resp.CToken = append(resp.CToken, ctoken)
+		}
+	}
+	h.mu.Unlock()
+	resp.Request.Addresses = req.Addresses
+	resp.Request.BlockNumber = req.BlockNumber
+	resp.Request.BlockTimestamp = req.BlockTimestamp
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func errorBody(code models.ErrorCode, message string, fieldErrors map[string]string) interface{} {
+	return map[string]interface{}{"error": &models.APIError{Code: code, Message: message, FieldErrors: fieldErrors}}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
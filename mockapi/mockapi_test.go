@@ -0,0 +1,121 @@
+package mockapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/musinit/go-defi/v2/client"
+	"github.com/musinit/go-defi/v2/mockapi"
+	"github.com/musinit/go-defi/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const account = "0xe18999d3f7e1a84e35bf9c15699b79e46eb44704"
+
+var fastRetry = client.WithRetryPolicy(client.RetryPolicy{
+	MaxRetries: 2,
+	MinBackoff: time.Millisecond,
+	MaxBackoff: time.Millisecond,
+})
+
+func Test_Accounts(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+	cl := client.NewClient(srv.URL, fastRetry)
+	ctx := context.Background()
+	total := len(srv.Accounts())
+
+	resp, err := cl.QueryAccounts(ctx, client.NewAccountRequest().PageSize(10).PageNumber(2))
+	require.NoError(t, err)
+	assert.Len(t, resp.Accounts, 10)
+	assert.Equal(t, models.PaginationSummary{
+		PageNumber:   2,
+		PageSize:     10,
+		TotalEntries: total,
+		TotalPages:   (total + 9) / 10,
+	}, resp.PaginationSummary)
+
+	all, err := cl.AllAccounts(ctx, client.NewAccountRequest().PageSize(4), 3)
+	require.NoError(t, err)
+	assert.Len(t, all, total)
+
+	resp, err = cl.GetAccountContext(ctx, "0xE18999D3F7E1A84E35BF9C15699B79E46EB44704")
+	require.NoError(t, err)
+	require.Len(t, resp.Accounts, 1)
+	assert.Equal(t, account, resp.Accounts[0].Address)
+
+	risky, err := cl.ScanLiquidatableAccounts(ctx, 2)
+	require.NoError(t, err)
+	assert.NotEmpty(t, risky)
+	for _, health := range risky {
+		assert.True(t, health.LessThan(models.NewDecimal(1)))
+	}
+
+	resp, err = cl.QueryAccounts(ctx, client.NewAccountRequest().MinBorrowValueInEth("15").MaxHealth("1.2").PageSize(100))
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Accounts)
+	for _, acct := range resp.Accounts {
+		assert.False(t, acct.Health.Value.GreaterThan(models.MustParseDecimal("1.2")))
+	}
+	assert.Equal(t, "1.2", resp.Request.MaxHealth.Value.String())
+
+	// accounts without borrows are not at risk
+	require.True(t, srv.UpdateAccount(account, func(acct *models.Account) {
+		acct.Health, acct.TotalBorrowValueInEth = models.Value{}, models.Value{}
+	}))
+	resp, err = cl.QueryAccounts(ctx, client.NewAccountRequest().Addresses(account).MaxHealth("1.2"))
+	require.NoError(t, err)
+	assert.Empty(t, resp.Accounts)
+}
+
+func Test_Errors(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+	cl := client.NewClient(srv.URL, fastRetry)
+	ctx := context.Background()
+
+	_, err := cl.GetAccountsContext(ctx, "10", "999")
+	assert.ErrorIs(t, err, models.ErrInvalidPageNumber)
+	_, err = cl.GetAccountsContext(ctx, "5000", "1")
+	assert.ErrorIs(t, err, models.ErrInvalidPageSize)
+
+	// temporary failures are retried
+	srv.FailNext(2, http.StatusServiceUnavailable)
+	_, err = cl.GetCTokensContext(ctx)
+	require.NoError(t, err)
+
+	srv.FailNext(3, http.StatusInternalServerError)
+	_, err = cl.GetCTokensContext(ctx)
+	assert.ErrorIs(t, err, models.ErrInternal)
+
+	// malformed requests are the caller's mistake, not a server fault
+	httpResp, err := http.Get(srv.URL + "/account?max_health[value]=low")
+	require.NoError(t, err)
+	defer httpResp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpResp.StatusCode)
+	var body models.AccountResponse
+	require.NoError(t, json.NewDecoder(httpResp.Body).Decode(&body))
+	assert.ErrorIs(t, body.Err(), models.ErrInvalidRequest)
+	assert.Contains(t, body.Error.FieldErrors, "max_health")
+}
+
+func Test_CTokens(t *testing.T) {
+	srv := mockapi.New()
+	defer srv.Close()
+	cl := client.NewClient(srv.URL)
+
+	resp, err := cl.GetCTokens(client.AtBlock(9000000))
+	require.NoError(t, err)
+	assert.Len(t, resp.CToken, 9)
+	assert.Equal(t, 9000000, resp.Request.BlockNumber)
+
+	resp, err = cl.GetCToken(client.CompoundDAI.String())
+	require.NoError(t, err)
+	require.Len(t, resp.CToken, 1)
+	assert.Equal(t, "cDAI", resp.CToken[0].Symbol)
+	assert.Equal(t, 2, srv.Requests())
+}
@@ -6,20 +6,27 @@ import "errors"
 // https://api.compound.finance/api/v2/account?addresses[]= call
 type AccountResponse struct {
 	Accounts             []Account         `json:"accounts"`
-	CloseFactor          float64           `json:"close_factor"`
-	Error                interface{}       `json:"error"`
-	LiquidationIncentive float64           `json:"liquidation_incentive"`
+	CloseFactor          Number            `json:"close_factor"`
+	Error                *APIError         `json:"error"`
+	LiquidationIncentive Number            `json:"liquidation_incentive"`
 	PaginationSummary    PaginationSummary `json:"pagination_summary"`
 	Request              Request           `json:"request"`
 }
 
+// Err returns the error reported by the api, or nil if the request succeeded
+func (r *AccountResponse) Err() error {
+	return apiErr(r.Error)
+}
+
+// Account is the supply and borrow position of an account, see
+// the Account message in pb/account.proto
 type Account struct {
-	Address                   string      `json:"address"`
-	BlockUpdated              interface{} `json:"block_updated"`
-	Health                    Value       `json:"health"`
-	Tokens                    []Token     `json:"tokens"`
-	TotalBorrowValueInEth     Value       `json:"total_borrow_value_in_eth"`
-	TotalCollateralValueInEth Value       `json:"total_collateral_value_in_eth"`
+	Address                   string         `json:"address"`
+	BlockUpdated              *int           `json:"block_updated"`
+	Health                    Value          `json:"health"`
+	Tokens                    []AccountToken `json:"tokens"`
+	TotalBorrowValueInEth     Value          `json:"total_borrow_value_in_eth"`
+	TotalCollateralValueInEth Value          `json:"total_collateral_value_in_eth"`
 }
 
 type PaginationSummary struct {
@@ -30,35 +37,60 @@ type PaginationSummary struct {
 }
 
 type Request struct {
-	Addresses           []string    `json:"addresses"`
-	BlockNumber         int         `json:"block_number"`
-	BlockTimestamp      int         `json:"block_timestamp"`
-	MaxHealth           interface{} `json:"max_health"`
-	MinBorrowValueInEth interface{} `json:"min_borrow_value_in_eth"`
-	PageNumber          int         `json:"page_number"`
-	PageSize            int         `json:"page_size"`
+	Addresses           []string `json:"addresses"`
+	BlockNumber         int      `json:"block_number"`
+	BlockTimestamp      int      `json:"block_timestamp"`
+	MaxHealth           *Value   `json:"max_health"`
+	MinBorrowValueInEth *Value   `json:"min_borrow_value_in_eth"`
+	PageNumber          int      `json:"page_number"`
+	PageSize            int      `json:"page_size"`
 }
 
+// Value is a Precise number returned by the api, ex. {"value": "1.05"}
 type Value struct {
-	Value string `json:"value"`
+	Value Decimal `json:"value"`
+}
+
+// AccountToken is an account's supply, borrow and interest in a single
+// market, see the AccountCToken message in pb/account.proto
+type AccountToken struct {
+	Address                       string `json:"address"`
+	BorrowBalanceUnderlying       Value  `json:"borrow_balance_underlying"`
+	LifetimeBorrowInterestAccrued Value  `json:"lifetime_borrow_interest_accrued"`
+	LifetimeSupplyInterestAccrued Value  `json:"lifetime_supply_interest_accrued"`
+	SupplyBalanceUnderlying       Value  `json:"supply_balance_underlying"`
+	Symbol                        string `json:"symbol"`
+}
+
+// Token is the previous name of AccountToken
+type Token = AccountToken
+
+// SupplyValueInEth returns the value in eth of the supplied underlying tokens
+func (t AccountToken) SupplyValueInEth(market CToken) Decimal {
+	return t.SupplyBalanceUnderlying.Value.Mul(market.UnderlyingPrice.Value)
 }
 
-// Token is an individual token
-type Token struct {
-	Address                       string      `json:"address"`
-	BorrowBalanceUnderlying       Value       `json:"borrow_balance_underlying"`
-	LifetimeBorrowInterestAccrued Value       `json:"lifetime_borrow_interest_accrued"`
-	LifetimeSupplyInterestAccrued Value       `json:"lifetime_supply_interest_accrued"`
-	SupplyBalanceUnderlying       Value       `json:"supply_balance_underlying"`
-	Symbol                        interface{} `json:"symbol"`
+// BorrowValueInEth returns the value in eth of the borrowed underlying tokens
+func (t AccountToken) BorrowValueInEth(market CToken) Decimal {
+	return t.BorrowBalanceUnderlying.Value.Mul(market.UnderlyingPrice.Value)
 }
 
-// GetTokenByAddress is used to retrieve a token by its address from an AccountResponse type
-func GetTokenByAddress(address string, resp *AccountResponse) (Token, error) {
+// CollateralValueInEth returns how much eth can be borrowed against the
+// supplied tokens, given the state of their market
+func (t AccountToken) CollateralValueInEth(market CToken) Decimal {
+	return t.SupplyValueInEth(market).Mul(market.CollateralFactor.Value)
+}
+
+// GetTokenByAddress is used to retrieve a token by its address from the first
+// account of an AccountResponse type
+func GetTokenByAddress(address string, resp *AccountResponse) (AccountToken, error) {
+	if len(resp.Accounts) == 0 {
+		return AccountToken{}, errors.New("no accounts found")
+	}
 	for _, token := range resp.Accounts[0].Tokens {
 		if token.Address == address {
 			return token, nil
 		}
 	}
-	return Token{}, errors.New("token not found")
+	return AccountToken{}, errors.New("token not found")
 }
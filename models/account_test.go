@@ -0,0 +1,21 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetTokenByAddress(t *testing.T) {
+	resp := &AccountResponse{Accounts: []Account{{Tokens: []AccountToken{{Address: "0xc1", Symbol: "cDAI"}}}}}
+	token, err := GetTokenByAddress("0xc1", resp)
+	require.NoError(t, err)
+	assert.Equal(t, "cDAI", token.Symbol)
+	_, err = GetTokenByAddress("0xc2", resp)
+	assert.Error(t, err)
+
+	// an empty page has no tokens
+	_, err = GetTokenByAddress("0xc1", &AccountResponse{})
+	assert.Error(t, err)
+}
@@ -1,50 +1,98 @@
 package models
 
+import "math/big"
+
+// DaysPerYear is the number of daily compounding periods used to derive APYs
+const DaysPerYear = 365
+
 // CTokenResponse is a response to a
 // https://api.compound.finance/api/v2/ctoken?addresses[]= call
 type CTokenResponse struct {
-	CToken []struct {
-		BorrowRate struct {
-			Value string `json:"value,omitempty"`
-		} `json:"borrow_rate,omitempty"`
-		Cash struct {
-			Value string `json:"value,omitempty"`
-		} `json:"cash,omitempty"`
-		CollateralFactor struct {
-			Value string `json:"value,omitempty"`
-		} `json:"collateral_factor,omitempty"`
-		ExchangeRate struct {
-			Value string `json:"value,omitempty"`
-		} `json:"exchange_rate,omitempty"`
-		InterestRateModelAddress string `json:"interest_rate_model_address,omitempty"`
-		Name                     string `json:"name,omitempty"`
-		NumberOfBorrowers        int    `json:"number_of_borrowers,omitempty"`
-		NumberOfSuppliers        int    `json:"number_of_suppliers,omitempty"`
-		Reserves                 struct {
-			Value string `json:"value,omitempty"`
-		} `json:"reserves,omitempty"`
-		SupplyRate struct {
-			Value string `json:"value,omitempty"`
-		} `json:"supply_rate,omitempty"`
-		Symbol       string `json:"symbol,omitempty"`
-		TokenAddress string `json:"token_address,omitempty"`
-		TotalBorrows struct {
-			Value string `json:"value,omitempty"`
-		} `json:"total_borrows,omitempty"`
-		TotalSupply struct {
-			Value string `json:"value,omitempty"`
-		} `json:"total_supply,omitempty"`
-		UnderlyingAddress string `json:"underlying_address,omitempty"`
-		UnderlyingName    string `json:"underlying_name,omitempty"`
-		UnderlyingPrice   struct {
-			Value string `json:"value,omitempty"`
-		} `json:"underlying_price,omitempty"`
-		UnderlyingSymbol string `json:"underlying_symbol,omitempty"`
-	} `json:"cToken,omitempty"`
-	Error   interface{} `json:"error,omitempty"`
-	Request struct {
-		Addresses      []string `json:"addresses,omitempty"`
-		BlockNumber    int      `json:"block_number,omitempty"`
-		BlockTimestamp int      `json:"block_timestamp,omitempty"`
-	} `json:"request,omitempty"`
+	CToken  []CToken      `json:"cToken,omitempty"`
+	Error   *APIError     `json:"error,omitempty"`
+	Request CTokenRequest `json:"request,omitempty"`
+}
+
+// Err returns the error reported by the api, or nil if the request succeeded
+func (r *CTokenResponse) Err() error {
+	return apiErr(r.Error)
+}
+
+// CTokenRequest is the request echoed in a CTokenResponse
+type CTokenRequest struct {
+	Addresses      []string `json:"addresses,omitempty"`
+	BlockNumber    int      `json:"block_number,omitempty"`
+	BlockTimestamp int      `json:"block_timestamp,omitempty"`
+}
+
+// CToken is the state of a single compound market, see the CToken message in pb/ctoken.proto
+type CToken struct {
+	BorrowRate               Value  `json:"borrow_rate,omitempty"`
+	Cash                     Value  `json:"cash,omitempty"`
+	CollateralFactor         Value  `json:"collateral_factor,omitempty"`
+	ExchangeRate             Value  `json:"exchange_rate,omitempty"`
+	InterestRateModelAddress string `json:"interest_rate_model_address,omitempty"`
+	Name                     string `json:"name,omitempty"`
+	NumberOfBorrowers        int    `json:"number_of_borrowers,omitempty"`
+	NumberOfSuppliers        int    `json:"number_of_suppliers,omitempty"`
+	Reserves                 Value  `json:"reserves,omitempty"`
+	SupplyRate               Value  `json:"supply_rate,omitempty"`
+	Symbol                   string `json:"symbol,omitempty"`
+	TokenAddress             string `json:"token_address,omitempty"`
+	TotalBorrows             Value  `json:"total_borrows,omitempty"`
+	TotalSupply              Value  `json:"total_supply,omitempty"`
+	UnderlyingAddress        string `json:"underlying_address,omitempty"`
+	UnderlyingName           string `json:"underlying_name,omitempty"`
+	UnderlyingPrice          Value  `json:"underlying_price,omitempty"`
+	UnderlyingSymbol         string `json:"underlying_symbol,omitempty"`
+}
+
+// Utilization returns the share of the market's supply that is borrowed,
+// total_borrows / (cash + total_borrows - reserves), or 0 for an empty market
+func (c CToken) Utilization() Decimal {
+	supplied := c.Cash.Value.Add(c.TotalBorrows.Value).Sub(c.Reserves.Value)
+	if supplied.Sign() <= 0 {
+		return Decimal{}
+	}
+	return c.TotalBorrows.Value.Quo(supplied)
+}
+
+// SupplyAPY returns the supply rate compounded daily, ex. 0.0211 for 2.11%
+func (c CToken) SupplyAPY() Decimal {
+	return apy(c.SupplyRate.Value)
+}
+
+// BorrowAPY returns the borrow rate compounded daily, ex. 0.0382 for 3.82%
+func (c CToken) BorrowAPY() Decimal {
+	return apy(c.BorrowRate.Value)
+}
+
+// UnderlyingValue returns the amount of underlying tokens a balance of cTokens is worth
+func (c CToken) UnderlyingValue(cTokens Decimal) Decimal {
+	return cTokens.Mul(c.ExchangeRate.Value)
+}
+
+// UnderlyingValueInEth returns the value in eth of a balance of cTokens
+func (c CToken) UnderlyingValueInEth(cTokens Decimal) Decimal {
+	return c.UnderlyingValue(cTokens).Mul(c.UnderlyingPrice.Value)
+}
+
+// CollateralValueInEth returns how much eth can be borrowed against a balance
+// of cTokens, its value in eth scaled by the market's collateral factor
+func (c CToken) CollateralValueInEth(cTokens Decimal) Decimal {
+	return c.UnderlyingValueInEth(cTokens).Mul(c.CollateralFactor.Value)
+}
+
+// apy compounds an annual rate daily, rounded to 18 decimal places
+func apy(rate Decimal) Decimal {
+	if rate.IsZero() {
+		return Decimal{}
+	}
+	days := NewDecimal(DaysPerYear)
+	daily := NewDecimal(1).Add(rate.Quo(days)).Rat()
+	compounded := new(big.Rat).SetFrac(
+		new(big.Int).Exp(daily.Num(), big.NewInt(DaysPerYear), nil),
+		new(big.Int).Exp(daily.Denom(), big.NewInt(DaysPerYear), nil),
+	)
+	return NewDecimalFromRat(compounded).Sub(NewDecimal(1)).Round(18)
 }
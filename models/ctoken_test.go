@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cDAIJSON = `{
+	"borrow_rate": {"value": "0.0382"},
+	"cash": {"value": "300"},
+	"collateral_factor": {"value": "0.75"},
+	"exchange_rate": {"value": "0.02"},
+	"number_of_borrowers": 8921,
+	"reserves": {"value": "50"},
+	"supply_rate": {"value": "0.0211"},
+	"symbol": "cDAI",
+	"token_address": "0x5d3a536e4d6dbd6114cc1ead35777bab948e3643",
+	"total_borrows": {"value": "750"},
+	"underlying_price": {"value": "0.0005"},
+	"underlying_symbol": "DAI"
+}`
+
+func Test_CToken_Metrics(t *testing.T) {
+	var market CToken
+	require.NoError(t, json.Unmarshal([]byte(cDAIJSON), &market))
+	assert.Equal(t, "cDAI", market.Symbol)
+	assert.Equal(t, 8921, market.NumberOfBorrowers)
+
+	// 750 / (300 + 750 - 50)
+	assert.Equal(t, "0.75", market.Utilization().String())
+	assert.Equal(t, "0", CToken{}.Utilization().String())
+
+	// (1 + 0.0211/365)^365 - 1
+	assert.Equal(t, "0.021323556", market.SupplyAPY().FloatString(9))
+	assert.Equal(t, "0.038936923", market.BorrowAPY().FloatString(9))
+	assert.True(t, CToken{}.SupplyAPY().IsZero())
+
+	cTokens := NewDecimal(5000)
+	assert.Equal(t, "100", market.UnderlyingValue(cTokens).String())
+	assert.Equal(t, "0.05", market.UnderlyingValueInEth(cTokens).String())
+	assert.Equal(t, "0.0375", market.CollateralValueInEth(cTokens).String())
+}
+
+func Test_AccountToken_Metrics(t *testing.T) {
+	var acct Account
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"address": "0xe18999d3f7e1a84e35bf9c15699b79e46eb44704",
+		"block_updated": null,
+		"tokens": [{
+			"address": "0x5d3a536e4d6dbd6114cc1ead35777bab948e3643",
+			"borrow_balance_underlying": {"value": "40"},
+			"supply_balance_underlying": {"value": "100"},
+			"symbol": "cDAI"
+		}]
+	}`), &acct))
+	assert.Nil(t, acct.BlockUpdated)
+	token := acct.Tokens[0]
+	assert.Equal(t, "cDAI", token.Symbol)
+
+	var market CToken
+	require.NoError(t, json.Unmarshal([]byte(cDAIJSON), &market))
+	assert.Equal(t, "0.05", token.SupplyValueInEth(market).String())
+	assert.Equal(t, "0.02", token.BorrowValueInEth(market).String())
+	assert.Equal(t, "0.0375", token.CollateralValueInEth(market).String())
+}
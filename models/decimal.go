@@ -0,0 +1,278 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// maxDecimalPlaces bounds the digits printed for numbers without a finite
+// decimal representation, ex. 1/3
+const maxDecimalPlaces = 36
+
+var (
+	bigTen  = big.NewInt(10)
+	bigTwo  = big.NewInt(2)
+	bigFive = big.NewInt(5)
+)
+
+// Decimal is an arbitrary precision decimal number backed by a big.Rat, used
+// for every Precise value returned by the api. The zero value is 0, and a
+// Decimal is immutable: arithmetic returns a new value
+type Decimal struct {
+	rat *big.Rat
+}
+
+// NewDecimal returns the Decimal equal to x
+func NewDecimal(x int64) Decimal {
+	return Decimal{rat: new(big.Rat).SetInt64(x)}
+}
+
+// NewDecimalFromRat returns the Decimal equal to x
+func NewDecimalFromRat(x *big.Rat) Decimal {
+	if x == nil {
+		return Decimal{}
+	}
+	return Decimal{rat: new(big.Rat).Set(x)}
+}
+
+// NewDecimalFromInt returns x scaled down by 10^decimals, ex. a token
+// amount in its smallest unit converted to whole tokens
+func NewDecimalFromInt(x *big.Int, decimals int) Decimal {
+	if x == nil {
+		return Decimal{}
+	}
+	return Decimal{rat: new(big.Rat).SetInt(x)}.Shift(-decimals)
+}
+
+// ParseDecimal parses a decimal string such as "1.05", "-3" or "1e-18"
+func ParseDecimal(s string) (Decimal, error) {
+	rat, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{rat: rat}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics on invalid input, for constants
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// r returns the underlying value, which must not be modified
+func (d Decimal) r() *big.Rat {
+	if d.rat == nil {
+		return new(big.Rat)
+	}
+	return d.rat
+}
+
+// Rat returns a copy of the value as a big.Rat
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).Set(d.r())
+}
+
+// Int returns the value truncated towards zero
+func (d Decimal) Int() *big.Int {
+	return new(big.Int).Quo(d.r().Num(), d.r().Denom())
+}
+
+// Float64 returns the nearest float64, and whether it is exact
+func (d Decimal) Float64() (float64, bool) {
+	return d.r().Float64()
+}
+
+// Add returns d + y
+func (d Decimal) Add(y Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Add(d.r(), y.r())}
+}
+
+// Sub returns d - y
+func (d Decimal) Sub(y Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Sub(d.r(), y.r())}
+}
+
+// Mul returns d * y
+func (d Decimal) Mul(y Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Mul(d.r(), y.r())}
+}
+
+// Quo returns d / y, it panics if y is zero
+func (d Decimal) Quo(y Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Quo(d.r(), y.r())}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{rat: new(big.Rat).Neg(d.r())}
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{rat: new(big.Rat).Abs(d.r())}
+}
+
+// Shift returns d * 10^n, a negative n divides
+func (d Decimal) Shift(n int) Decimal {
+	if n == 0 {
+		return d
+	}
+	exp := n
+	if exp < 0 {
+		exp = -exp
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(bigTen, big.NewInt(int64(exp)), nil))
+	if n < 0 {
+		return Decimal{rat: new(big.Rat).Quo(d.r(), scale)}
+	}
+	return Decimal{rat: new(big.Rat).Mul(d.r(), scale)}
+}
+
+// Cmp returns -1, 0 or 1 if d is less than, equal to or greater than y
+func (d Decimal) Cmp(y Decimal) int {
+	return d.r().Cmp(y.r())
+}
+
+// Equal reports whether d and y are the same number
+func (d Decimal) Equal(y Decimal) bool {
+	return d.Cmp(y) == 0
+}
+
+// LessThan reports whether d < y
+func (d Decimal) LessThan(y Decimal) bool {
+	return d.Cmp(y) < 0
+}
+
+// GreaterThan reports whether d > y
+func (d Decimal) GreaterThan(y Decimal) bool {
+	return d.Cmp(y) > 0
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.r().Sign()
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// FloatString returns d rounded to the given number of decimal places
+func (d Decimal) FloatString(places int) string {
+	return d.r().FloatString(places)
+}
+
+// Round returns d rounded to the given number of decimal places, halves
+// are rounded away from zero
+func (d Decimal) Round(places int) Decimal {
+	rounded, _ := new(big.Rat).SetString(d.r().FloatString(places))
+	return Decimal{rat: rounded}
+}
+
+// String returns the exact decimal representation of d without trailing
+// zeros. Numbers without a finite representation are rounded to 36 places
+func (d Decimal) String() string {
+	rat := d.r()
+	if rat.IsInt() {
+		return rat.Num().String()
+	}
+	places, exact := decimalPlaces(rat.Denom())
+	if !exact {
+		places = maxDecimalPlaces
+	}
+	s := rat.FloatString(places)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// decimalPlaces returns the number of decimal places needed to print a
+// fraction with the given denominator exactly, and false if it repeats
+func decimalPlaces(denom *big.Int) (int, bool) {
+	rest := new(big.Int).Set(denom)
+	mod := new(big.Int)
+	var twos, fives int
+	for {
+		q, m := new(big.Int).QuoRem(rest, bigTwo, mod)
+		if m.Sign() != 0 {
+			break
+		}
+		rest, twos = q, twos+1
+	}
+	for {
+		q, m := new(big.Int).QuoRem(rest, bigFive, mod)
+		if m.Sign() != 0 {
+			break
+		}
+		rest, fives = q, fives+1
+	}
+	if twos < fives {
+		twos = fives
+	}
+	return twos, rest.Cmp(big.NewInt(1)) == 0
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, empty text decodes as 0
+func (d *Decimal) UnmarshalText(text []byte) error {
+	if len(bytes.TrimSpace(text)) == 0 {
+		*d = Decimal{}
+		return nil
+	}
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes d as a json string, preserving its full precision
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts a json string, a json number or null, which decodes as 0
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return d.UnmarshalText([]byte(s))
+	}
+	return d.UnmarshalText(data)
+}
+
+// Number is a Decimal encoded as a json number instead of a string, for the
+// api values that are numbers on the wire, ex. the close factor
+type Number struct {
+	Decimal
+}
+
+// NewNumber returns d encoded as a json number
+func NewNumber(d Decimal) Number {
+	return Number{Decimal: d}
+}
+
+// MarshalJSON encodes n as a json number, preserving its full precision
+func (n Number) MarshalJSON() ([]byte, error) {
+	return []byte(n.String()), nil
+}
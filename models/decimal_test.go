@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// health is the example health value of pb/account.proto
+const health = "1.07264275673050348990755599431194797431802239523113293682619605751591901"
+
+func Test_Decimal_String(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{health, health},
+		{"0.5100157047140227313856015174794473200000000000000000000000000000", "0.51001570471402273138560151747944732"},
+		{"1.0", "1"},
+		{"-0.25", "-0.25"},
+		{"1e-18", "0.000000000000000001"},
+		{"1/3", "0.333333333333333333333333333333333333"},
+		{"231584178474632390847141970017375815706539969331281128078915168015826259279871", "231584178474632390847141970017375815706539969331281128078915168015826259279871"},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, d.String())
+	}
+	assert.Equal(t, "0", Decimal{}.String())
+	_, err := ParseDecimal("one")
+	assert.Error(t, err)
+}
+
+func Test_Decimal_Arithmetic(t *testing.T) {
+	a := MustParseDecimal("1.5")
+	b := MustParseDecimal("0.25")
+	assert.Equal(t, "1.75", a.Add(b).String())
+	assert.Equal(t, "1.25", a.Sub(b).String())
+	assert.Equal(t, "0.375", a.Mul(b).String())
+	assert.Equal(t, "6", a.Quo(b).String())
+	assert.Equal(t, "-1.5", a.Neg().String())
+	assert.Equal(t, "1.5", a.Neg().Abs().String())
+	assert.Equal(t, "150", a.Shift(2).String())
+	assert.Equal(t, "0.015", a.Shift(-2).String())
+	assert.Equal(t, "1.5", a.String(), "operands are not modified")
+
+	assert.Equal(t, "12.345678", NewDecimalFromInt(big.NewInt(12345678), 6).String())
+	assert.Equal(t, int64(12), NewDecimalFromInt(big.NewInt(12345678), 6).Int().Int64())
+	f, _ := b.Float64()
+	assert.Equal(t, 0.25, f)
+
+	precise := MustParseDecimal(health)
+	assert.True(t, precise.GreaterThan(NewDecimal(1)))
+	assert.True(t, precise.LessThan(MustParseDecimal("1.0726427567305034899075559943119479743180223952311329368261960575159191")))
+	assert.True(t, MustParseDecimal("1.00").Equal(NewDecimal(1)))
+	assert.True(t, Decimal{}.IsZero())
+	assert.Equal(t, -1, Decimal{}.Cmp(b))
+}
+
+func Test_Decimal_JSON(t *testing.T) {
+	var v struct {
+		Health Value   `json:"health"`
+		Rate   Decimal `json:"rate"`
+		Null   Decimal `json:"null"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"health": {"value": "`+health+`"}, "rate": 0.0175, "null": null}`), &v))
+	assert.Equal(t, health, v.Health.Value.String())
+	assert.Equal(t, "0.0175", v.Rate.String())
+	assert.True(t, v.Null.IsZero())
+
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"health": {"value": "`+health+`"}, "rate": "0.0175", "null": "0"}`, string(out))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"rate": "fast"}`), &v))
+}
+
+func Test_Number_JSON(t *testing.T) {
+	var resp AccountResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"accounts": [], "close_factor": 0.5, "liquidation_incentive": "1.08"}`), &resp))
+	assert.Equal(t, "0.5", resp.CloseFactor.String())
+	assert.True(t, resp.LiquidationIncentive.Equal(MustParseDecimal("1.08")))
+
+	// numbers on the wire are encoded back as numbers
+	out, err := json.Marshal(struct {
+		CloseFactor          Number     `json:"close_factor"`
+		LiquidationIncentive Number     `json:"liquidation_incentive"`
+		Rates                RateBucket `json:"rates"`
+	}{resp.CloseFactor, resp.LiquidationIncentive, RateBucket{Rate: NewNumber(MustParseDecimal("-0.0175"))}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"close_factor": 0.5, "liquidation_incentive": 1.08, "rates": {"block_number": 0, "block_timestamp": 0, "rate": -0.0175}}`, string(out))
+	assert.Contains(t, string(out), `"close_factor":0.5`)
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrorCode is an error code returned by the compound api,
+// as defined by the errorCodes enums in pb/account.proto and pb/ctoken.proto
+type ErrorCode uint32
+
+const (
+	// NoError indicates the request succeeded
+	NoError ErrorCode = 0
+	// InternalError indicates the api failed to serve the request
+	InternalError ErrorCode = 1
+	// InvalidPageNumber indicates the requested page number is out of range
+	InvalidPageNumber ErrorCode = 2
+	// InvalidPageSize indicates the requested page size is not allowed
+	InvalidPageSize ErrorCode = 3
+	// InvalidRequest indicates a malformed request, its field errors tell
+	// which fields. It is returned by the self hosted api server and the mock
+	// api, not by the compound api
+	InvalidRequest ErrorCode = 4
+)
+
+var errorCodeNames = map[ErrorCode]string{
+	NoError:           "NO_ERROR",
+	InternalError:     "INTERNAL_ERROR",
+	InvalidPageNumber: "INVALID_PAGE_NUMBER",
+	InvalidPageSize:   "INVALID_PAGE_SIZE",
+	InvalidRequest:    "INVALID_REQUEST",
+}
+
+func (c ErrorCode) String() string {
+	if name, ok := errorCodeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("ERROR_CODE_%d", uint32(c))
+}
+
+var (
+	// ErrInternal matches any APIError with the INTERNAL_ERROR code
+	ErrInternal = &APIError{Code: InternalError}
+	// ErrInvalidPageNumber matches any APIError with the INVALID_PAGE_NUMBER code
+	ErrInvalidPageNumber = &APIError{Code: InvalidPageNumber}
+	// ErrInvalidPageSize matches any APIError with the INVALID_PAGE_SIZE code
+	ErrInvalidPageSize = &APIError{Code: InvalidPageSize}
+	// ErrInvalidRequest matches any APIError with the INVALID_REQUEST code
+	ErrInvalidRequest = &APIError{Code: InvalidRequest}
+)
+
+// APIError is the error object returned by the compound api, see the
+// Error message in pb/common.proto. Use errors.Is with one of the ErrXxx
+// values to check for a particular code, or errors.As to inspect the message
+// and field errors
+type APIError struct {
+	Code        ErrorCode         `json:"error_code"`
+	Message     string            `json:"message,omitempty"`
+	FieldErrors map[string]string `json:"field_errors,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	msg := "compound api error " + e.Code.String()
+	if e.Message != "" {
+		msg = msg + ": " + e.Message
+	}
+	if len(e.FieldErrors) > 0 {
+		fields := make([]string, 0, len(e.FieldErrors))
+		for field, fieldErr := range e.FieldErrors {
+			fields = append(fields, field+": "+fieldErr)
+		}
+		sort.Strings(fields)
+		msg = msg + " (" + strings.Join(fields, ", ") + ")"
+	}
+	return msg
+}
+
+// Is reports whether target is an APIError with the same error code
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.Code == e.Code
+}
+
+// apiErr converts an optional error object into an error, treating
+// a missing object or a NO_ERROR code as success
+func apiErr(e *APIError) error {
+	if e == nil || e.Code == NoError {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,151 @@
+package models
+
+// ProposalsResponse is a response to a
+// https://api.compound.finance/api/v2/governance/proposals call
+type ProposalsResponse struct {
+	Error             *APIError         `json:"error,omitempty"`
+	Request           ProposalsRequest  `json:"request,omitempty"`
+	PaginationSummary PaginationSummary `json:"pagination_summary"`
+	Proposals         []Proposal        `json:"proposals"`
+}
+
+// Err returns the error reported by the api, or nil if the request succeeded
+func (r *ProposalsResponse) Err() error {
+	return apiErr(r.Error)
+}
+
+// ProposalsRequest is the request echoed in a ProposalsResponse
+type ProposalsRequest struct {
+	ProposalIDs []int  `json:"proposal_ids,omitempty"`
+	State       string `json:"state,omitempty"`
+	WithDetail  bool   `json:"with_detail,omitempty"`
+	PageNumber  int    `json:"page_number,omitempty"`
+	PageSize    int    `json:"page_size,omitempty"`
+}
+
+// Proposal is a governance proposal
+type Proposal struct {
+	ID           int                `json:"id"`
+	Title        string             `json:"title"`
+	Description  string             `json:"description"`
+	Targets      []string           `json:"targets,omitempty"`
+	Values       []string           `json:"values,omitempty"`
+	Signatures   []string           `json:"signatures,omitempty"`
+	Calldatas    []string           `json:"calldatas,omitempty"`
+	States       []ProposalState    `json:"states"`
+	Proposer     *GovernanceAccount `json:"proposer,omitempty"`
+	ForVotes     Decimal            `json:"for_votes"`
+	AgainstVotes Decimal            `json:"against_votes"`
+}
+
+// State returns the most recent state of the proposal, ex. "active", or
+// an empty string if the proposal has no recorded states
+func (p Proposal) State() string {
+	if len(p.States) == 0 {
+		return ""
+	}
+	return p.States[len(p.States)-1].State
+}
+
+// ProposalState is a single state transition of a proposal
+type ProposalState struct {
+	State     string `json:"state"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time,omitempty"`
+	TrxHash   string `json:"trx_hash,omitempty"`
+}
+
+// ProposalVoteReceiptsResponse is a response to a
+// https://api.compound.finance/api/v2/governance/proposal_vote_receipts call
+type ProposalVoteReceiptsResponse struct {
+	Error                *APIError                   `json:"error,omitempty"`
+	Request              ProposalVoteReceiptsRequest `json:"request,omitempty"`
+	PaginationSummary    PaginationSummary           `json:"pagination_summary"`
+	ProposalVoteReceipts []ProposalVoteReceipt       `json:"proposal_vote_receipts"`
+}
+
+// Err returns the error reported by the api, or nil if the request succeeded
+func (r *ProposalVoteReceiptsResponse) Err() error {
+	return apiErr(r.Error)
+}
+
+// ProposalVoteReceiptsRequest is the request echoed in a ProposalVoteReceiptsResponse
+type ProposalVoteReceiptsRequest struct {
+	ProposalID       int    `json:"proposal_id,omitempty"`
+	Account          string `json:"account,omitempty"`
+	Support          *bool  `json:"support,omitempty"`
+	WithProposalData bool   `json:"with_proposal_data,omitempty"`
+	PageNumber       int    `json:"page_number,omitempty"`
+	PageSize         int    `json:"page_size,omitempty"`
+}
+
+// ProposalVoteReceipt is a vote cast on a proposal
+type ProposalVoteReceipt struct {
+	ProposalID int                `json:"proposal_id"`
+	Support    bool               `json:"support"`
+	Votes      Decimal            `json:"votes"`
+	Voter      *GovernanceAccount `json:"voter,omitempty"`
+	Proposal   *Proposal          `json:"proposal,omitempty"`
+}
+
+// GovernanceAccountsResponse is a response to a
+// https://api.compound.finance/api/v2/governance/accounts call
+type GovernanceAccountsResponse struct {
+	Error             *APIError                 `json:"error,omitempty"`
+	Request           GovernanceAccountsRequest `json:"request,omitempty"`
+	PaginationSummary PaginationSummary         `json:"pagination_summary"`
+	Accounts          []GovernanceAccount       `json:"accounts"`
+}
+
+// Err returns the error reported by the api, or nil if the request succeeded
+func (r *GovernanceAccountsResponse) Err() error {
+	return apiErr(r.Error)
+}
+
+// GovernanceAccountsRequest is the request echoed in a GovernanceAccountsResponse
+type GovernanceAccountsRequest struct {
+	Addresses   []string `json:"addresses,omitempty"`
+	OrderBy     string   `json:"order_by,omitempty"`
+	WithHistory bool     `json:"with_history,omitempty"`
+	PageNumber  int      `json:"page_number,omitempty"`
+	PageSize    int      `json:"page_size,omitempty"`
+}
+
+// GovernanceAccount is an account's COMP holdings and voting power
+type GovernanceAccount struct {
+	Address          string             `json:"address"`
+	DisplayName      string             `json:"display_name,omitempty"`
+	ImageURL         string             `json:"image_url,omitempty"`
+	AccountURL       string             `json:"account_url,omitempty"`
+	Balance          Decimal            `json:"balance,omitempty"`
+	Votes            Decimal            `json:"votes,omitempty"`
+	VoteWeight       Decimal            `json:"vote_weight,omitempty"`
+	ProposalsCreated int                `json:"proposals_created,omitempty"`
+	Rank             int                `json:"rank,omitempty"`
+	TotalDelegates   int                `json:"total_delegates,omitempty"`
+	Delegate         *GovernanceAccount `json:"delegate,omitempty"`
+}
+
+// CompDistributionResponse is a response to a
+// https://api.compound.finance/api/v2/governance/comp call
+type CompDistributionResponse struct {
+	Error     *APIError    `json:"error,omitempty"`
+	CompRate  Decimal      `json:"comp_rate"`
+	DailyComp Decimal      `json:"daily_comp"`
+	Markets   []CompMarket `json:"markets"`
+}
+
+// Err returns the error reported by the api, or nil if the request succeeded
+func (r *CompDistributionResponse) Err() error {
+	return apiErr(r.Error)
+}
+
+// CompMarket is the COMP distribution of a single market
+type CompMarket struct {
+	Address        string  `json:"address"`
+	Symbol         string  `json:"symbol"`
+	CompAllocation Decimal `json:"comp_allocation"`
+	CompSpeed      Decimal `json:"comp_speed"`
+	CompSupplyAPY  Value   `json:"comp_supply_apy"`
+	CompBorrowAPY  Value   `json:"comp_borrow_apy"`
+}
@@ -0,0 +1,48 @@
+package models
+
+// MarketHistoryResponse is a response to a
+// https://api.compound.finance/api/v2/market_history/graph?asset= call
+type MarketHistoryResponse struct {
+	Error               *APIError            `json:"error,omitempty"`
+	Request             MarketHistoryRequest `json:"request,omitempty"`
+	SupplyRates         []RateBucket         `json:"supply_rates"`
+	BorrowRates         []RateBucket         `json:"borrow_rates"`
+	ExchangeRates       []RateBucket         `json:"exchange_rates"`
+	TotalSupplyHistory  []TotalBucket        `json:"total_supply_history"`
+	TotalBorrowsHistory []TotalBucket        `json:"total_borrows_history"`
+	PricesUSD           []PriceBucket        `json:"prices_usd"`
+}
+
+// Err returns the error reported by the api, or nil if the request succeeded
+func (r *MarketHistoryResponse) Err() error {
+	return apiErr(r.Error)
+}
+
+// MarketHistoryRequest is the request echoed in a MarketHistoryResponse
+type MarketHistoryRequest struct {
+	Asset             string `json:"asset,omitempty"`
+	MinBlockTimestamp int    `json:"min_block_timestamp,omitempty"`
+	MaxBlockTimestamp int    `json:"max_block_timestamp,omitempty"`
+	NumBuckets        int    `json:"num_buckets,omitempty"`
+}
+
+// RateBucket is an interest or exchange rate at a point in time
+type RateBucket struct {
+	BlockNumber    int    `json:"block_number"`
+	BlockTimestamp int    `json:"block_timestamp"`
+	Rate           Number `json:"rate"`
+}
+
+// TotalBucket is a total supply or borrow amount at a point in time
+type TotalBucket struct {
+	BlockNumber    int   `json:"block_number"`
+	BlockTimestamp int   `json:"block_timestamp"`
+	Total          Value `json:"total"`
+}
+
+// PriceBucket is the usd price of the underlying token at a point in time
+type PriceBucket struct {
+	BlockNumber    int   `json:"block_number"`
+	BlockTimestamp int   `json:"block_timestamp"`
+	Price          Value `json:"price"`
+}
@@ -0,0 +1,511 @@
+package API_Presidio
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// converts between the json models returned by the api and the protobuf messages.
+// Addresses are encoded as 20 bytes and decoded as lower case hex, the format used
+// by the api, and Precise values keep their full precision. The only lossy fields
+// are close_factor and liquidation_incentive, which the proto declares as float32,
+// and block_updated, where a zero value decodes as nil
+
+// NewAccountResponse returns the message holding r
+func NewAccountResponse(r *models.AccountResponse) (*AccountResponse, error) {
+	if r == nil {
+		return nil, nil
+	}
+	c := &converter{}
+	m := &AccountResponse{
+		Error:                NewError(r.Error),
+		Request:              c.accountRequest(r.Request),
+		PaginationSummary:    c.paginationSummary(r.PaginationSummary),
+		CloseFactor:          float32Of(r.CloseFactor.Decimal),
+		LiquidationIncentive: float32Of(r.LiquidationIncentive.Decimal),
+	}
+	for i, acct := range r.Accounts {
+		m.Accounts = append(m.Accounts, c.account(fmt.Sprintf("accounts[%d]", i), acct))
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return m, nil
+}
+
+// Model returns the json model of the message
+func (m *AccountResponse) Model() (*models.AccountResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+	c := &converter{}
+	r := &models.AccountResponse{
+		Error:                m.Error.Model(),
+		Request:              c.accountRequestModel(m.Request),
+		PaginationSummary:    m.PaginationSummary.Model(),
+		CloseFactor:          models.NewNumber(c.float32Decimal("close_factor", m.CloseFactor)),
+		LiquidationIncentive: models.NewNumber(c.float32Decimal("liquidation_incentive", m.LiquidationIncentive)),
+	}
+	for i, acct := range m.Accounts {
+		r.Accounts = append(r.Accounts, c.accountModel(fmt.Sprintf("accounts[%d]", i), acct))
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return r, nil
+}
+
+// NewAccount returns the message holding a
+func NewAccount(a models.Account) (*Account, error) {
+	c := &converter{}
+	m := c.account("account", a)
+	return m, c.err
+}
+
+// Model returns the json model of the message
+func (m *Account) Model() (models.Account, error) {
+	c := &converter{}
+	a := c.accountModel("account", m)
+	return a, c.err
+}
+
+// NewAccountCToken returns the message holding t
+func NewAccountCToken(t models.AccountToken) (*AccountCToken, error) {
+	c := &converter{}
+	m := c.accountToken("token", t)
+	return m, c.err
+}
+
+// Model returns the json model of the message
+func (m *AccountCToken) Model() (models.AccountToken, error) {
+	c := &converter{}
+	t := c.accountTokenModel("token", m)
+	return t, c.err
+}
+
+// NewAccountRequest returns the message holding r
+func NewAccountRequest(r models.Request) (*AccountRequest, error) {
+	c := &converter{}
+	m := c.accountRequest(r)
+	return m, c.err
+}
+
+// Model returns the json model of the message
+func (m *AccountRequest) Model() (models.Request, error) {
+	c := &converter{}
+	r := c.accountRequestModel(m)
+	return r, c.err
+}
+
+// NewCTokenResponse returns the message holding r
+func NewCTokenResponse(r *models.CTokenResponse) (*CTokenResponse, error) {
+	if r == nil {
+		return nil, nil
+	}
+	c := &converter{}
+	m := &CTokenResponse{
+		Error:   NewError(r.Error),
+		Request: c.ctokenRequest(r.Request),
+	}
+	for i, ctoken := range r.CToken {
+		m.CToken = append(m.CToken, c.ctoken(fmt.Sprintf("cToken[%d]", i), ctoken))
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return m, nil
+}
+
+// Model returns the json model of the message
+func (m *CTokenResponse) Model() (*models.CTokenResponse, error) {
+	if m == nil {
+		return nil, nil
+	}
+	c := &converter{}
+	r := &models.CTokenResponse{
+		Error:   m.Error.Model(),
+		Request: c.ctokenRequestModel(m.Request),
+	}
+	for i, ctoken := range m.CToken {
+		r.CToken = append(r.CToken, c.ctokenModel(fmt.Sprintf("cToken[%d]", i), ctoken))
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return r, nil
+}
+
+// NewCToken returns the message holding t
+func NewCToken(t models.CToken) (*CToken, error) {
+	c := &converter{}
+	m := c.ctoken("cToken", t)
+	return m, c.err
+}
+
+// Model returns the json model of the message
+func (m *CToken) Model() (models.CToken, error) {
+	c := &converter{}
+	t := c.ctokenModel("cToken", m)
+	return t, c.err
+}
+
+// NewCTokenRequest returns the message holding r
+func NewCTokenRequest(r models.CTokenRequest) (*CTokenRequest, error) {
+	c := &converter{}
+	m := c.ctokenRequest(r)
+	return m, c.err
+}
+
+// Model returns the json model of the message
+func (m *CTokenRequest) Model() (models.CTokenRequest, error) {
+	c := &converter{}
+	r := c.ctokenRequestModel(m)
+	return r, c.err
+}
+
+// NewError returns the message holding e, or nil if e is nil
+func NewError(e *models.APIError) *Error {
+	if e == nil {
+		return nil
+	}
+	return &Error{ErrorCode: uint32(e.Code), Message: e.Message, FieldErrors: copyFieldErrors(e.FieldErrors)}
+}
+
+// Model returns the api error held by the message, or nil if the message is nil
+func (m *Error) Model() *models.APIError {
+	if m == nil {
+		return nil
+	}
+	return &models.APIError{Code: models.ErrorCode(m.ErrorCode), Message: m.Message, FieldErrors: copyFieldErrors(m.FieldErrors)}
+}
+
+// NewPaginationSummary returns the message holding s
+func NewPaginationSummary(s models.PaginationSummary) (*PaginationSummary, error) {
+	c := &converter{}
+	m := c.paginationSummary(s)
+	return m, c.err
+}
+
+// Model returns the json model of the message, a nil message is the zero summary
+func (m *PaginationSummary) Model() models.PaginationSummary {
+	if m == nil {
+		return models.PaginationSummary{}
+	}
+	return models.PaginationSummary{
+		PageNumber:   int(m.PageNumber),
+		PageSize:     int(m.PageSize),
+		TotalEntries: int(m.TotalEntries),
+		TotalPages:   int(m.TotalPages),
+	}
+}
+
+// NewValue returns the Precise message holding v, or nil if v is nil
+func NewValue(v *models.Value) (*Precise, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return NewPrecise(v.Value)
+}
+
+// ValueOf returns the Value held by p, or nil if p is nil
+func ValueOf(p *Precise) (*models.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	d, err := p.Decimal()
+	if err != nil {
+		return nil, err
+	}
+	return &models.Value{Value: d}, nil
+}
+
+// converter records the first error of a conversion, so that messages with
+// many fields can be converted without checking every field
+type converter struct {
+	err error
+}
+
+func (c *converter) fail(name string, err error) {
+	if c.err == nil {
+		c.err = fmt.Errorf("%s: %w", name, err)
+	}
+}
+
+func (c *converter) account(name string, a models.Account) *Account {
+	m := &Account{
+		Address:                   c.address(name+".address", a.Address),
+		TotalCollateralValueInEth: c.precise(name+".total_collateral_value_in_eth", a.TotalCollateralValueInEth),
+		TotalBorrowValueInEth:     c.precise(name+".total_borrow_value_in_eth", a.TotalBorrowValueInEth),
+		Health:                    c.precise(name+".health", a.Health),
+	}
+	if a.BlockUpdated != nil {
+		if *a.BlockUpdated < math.MinInt32 || *a.BlockUpdated > math.MaxInt32 {
+			c.fail(name+".block_updated", fmt.Errorf("%d is out of range", *a.BlockUpdated))
+		}
+		m.BlockUpdated = int32(*a.BlockUpdated)
+	}
+	for i, token := range a.Tokens {
+		m.Tokens = append(m.Tokens, c.accountToken(fmt.Sprintf("%s.tokens[%d]", name, i), token))
+	}
+	return m
+}
+
+func (c *converter) accountModel(name string, m *Account) models.Account {
+	if m == nil {
+		return models.Account{}
+	}
+	a := models.Account{
+		Address:                   c.hex(name+".address", m.Address),
+		TotalCollateralValueInEth: c.value(name+".total_collateral_value_in_eth", m.TotalCollateralValueInEth),
+		TotalBorrowValueInEth:     c.value(name+".total_borrow_value_in_eth", m.TotalBorrowValueInEth),
+		Health:                    c.value(name+".health", m.Health),
+	}
+	if m.BlockUpdated != 0 {
+		block := int(m.BlockUpdated)
+		a.BlockUpdated = &block
+	}
+	for i, token := range m.Tokens {
+		a.Tokens = append(a.Tokens, c.accountTokenModel(fmt.Sprintf("%s.tokens[%d]", name, i), token))
+	}
+	return a
+}
+
+func (c *converter) accountToken(name string, t models.AccountToken) *AccountCToken {
+	return &AccountCToken{
+		Address:                       c.address(name+".address", t.Address),
+		Symbol:                        t.Symbol,
+		SupplyBalanceUnderlying:       c.precise(name+".supply_balance_underlying", t.SupplyBalanceUnderlying),
+		BorrowBalanceUnderlying:       c.precise(name+".borrow_balance_underlying", t.BorrowBalanceUnderlying),
+		LifetimeSupplyInterestAccrued: c.precise(name+".lifetime_supply_interest_accrued", t.LifetimeSupplyInterestAccrued),
+		LifetimeBorrowInterestAccrued: c.precise(name+".lifetime_borrow_interest_accrued", t.LifetimeBorrowInterestAccrued),
+	}
+}
+
+func (c *converter) accountTokenModel(name string, m *AccountCToken) models.AccountToken {
+	if m == nil {
+		return models.AccountToken{}
+	}
+	return models.AccountToken{
+		Address:                       c.hex(name+".address", m.Address),
+		Symbol:                        m.Symbol,
+		SupplyBalanceUnderlying:       c.value(name+".supply_balance_underlying", m.SupplyBalanceUnderlying),
+		BorrowBalanceUnderlying:       c.value(name+".borrow_balance_underlying", m.BorrowBalanceUnderlying),
+		LifetimeSupplyInterestAccrued: c.value(name+".lifetime_supply_interest_accrued", m.LifetimeSupplyInterestAccrued),
+		LifetimeBorrowInterestAccrued: c.value(name+".lifetime_borrow_interest_accrued", m.LifetimeBorrowInterestAccrued),
+	}
+}
+
+func (c *converter) accountRequest(r models.Request) *AccountRequest {
+	m := &AccountRequest{
+		Addresses:      c.addresses("request.addresses", r.Addresses),
+		BlockNumber:    c.uint32("request.block_number", r.BlockNumber),
+		BlockTimestamp: c.uint32("request.block_timestamp", r.BlockTimestamp),
+		PageSize:       c.uint32("request.page_size", r.PageSize),
+		PageNumber:     c.uint32("request.page_number", r.PageNumber),
+	}
+	if r.MaxHealth != nil {
+		m.MaxHealth = c.precise("request.max_health", *r.MaxHealth)
+	}
+	if r.MinBorrowValueInEth != nil {
+		m.MinBorrowValueInEth = c.precise("request.min_borrow_value_in_eth", *r.MinBorrowValueInEth)
+	}
+	return m
+}
+
+func (c *converter) accountRequestModel(m *AccountRequest) models.Request {
+	if m == nil {
+		return models.Request{}
+	}
+	r := models.Request{
+		Addresses:      c.hexes("request.addresses", m.Addresses),
+		BlockNumber:    int(m.BlockNumber),
+		BlockTimestamp: int(m.BlockTimestamp),
+		PageSize:       int(m.PageSize),
+		PageNumber:     int(m.PageNumber),
+	}
+	if m.MaxHealth != nil {
+		health := c.value("request.max_health", m.MaxHealth)
+		r.MaxHealth = &health
+	}
+	if m.MinBorrowValueInEth != nil {
+		borrow := c.value("request.min_borrow_value_in_eth", m.MinBorrowValueInEth)
+		r.MinBorrowValueInEth = &borrow
+	}
+	return r
+}
+
+func (c *converter) ctoken(name string, t models.CToken) *CToken {
+	return &CToken{
+		TokenAddress:             c.address(name+".token_address", t.TokenAddress),
+		TotalSupply:              c.precise(name+".total_supply", t.TotalSupply),
+		TotalBorrows:             c.precise(name+".total_borrows", t.TotalBorrows),
+		Reserves:                 c.precise(name+".reserves", t.Reserves),
+		Cash:                     c.precise(name+".cash", t.Cash),
+		ExchangeRate:             c.precise(name+".exchange_rate", t.ExchangeRate),
+		SupplyRate:               c.precise(name+".supply_rate", t.SupplyRate),
+		BorrowRate:               c.precise(name+".borrow_rate", t.BorrowRate),
+		CollateralFactor:         c.precise(name+".collateral_factor", t.CollateralFactor),
+		NumberOfSuppliers:        c.uint32(name+".number_of_suppliers", t.NumberOfSuppliers),
+		NumberOfBorrowers:        c.uint32(name+".number_of_borrowers", t.NumberOfBorrowers),
+		UnderlyingPrice:          c.precise(name+".underlying_price", t.UnderlyingPrice),
+		UnderlyingAddress:        c.address(name+".underlying_address", t.UnderlyingAddress),
+		Symbol:                   t.Symbol,
+		Name:                     t.Name,
+		UnderlyingSymbol:         t.UnderlyingSymbol,
+		UnderlyingName:           t.UnderlyingName,
+		InterestRateModelAddress: c.address(name+".interest_rate_model_address", t.InterestRateModelAddress),
+	}
+}
+
+func (c *converter) ctokenModel(name string, m *CToken) models.CToken {
+	if m == nil {
+		return models.CToken{}
+	}
+	return models.CToken{
+		TokenAddress:             c.hex(name+".token_address", m.TokenAddress),
+		TotalSupply:              c.value(name+".total_supply", m.TotalSupply),
+		TotalBorrows:             c.value(name+".total_borrows", m.TotalBorrows),
+		Reserves:                 c.value(name+".reserves", m.Reserves),
+		Cash:                     c.value(name+".cash", m.Cash),
+		ExchangeRate:             c.value(name+".exchange_rate", m.ExchangeRate),
+		SupplyRate:               c.value(name+".supply_rate", m.SupplyRate),
+		BorrowRate:               c.value(name+".borrow_rate", m.BorrowRate),
+		CollateralFactor:         c.value(name+".collateral_factor", m.CollateralFactor),
+		NumberOfSuppliers:        int(m.NumberOfSuppliers),
+		NumberOfBorrowers:        int(m.NumberOfBorrowers),
+		UnderlyingPrice:          c.value(name+".underlying_price", m.UnderlyingPrice),
+		UnderlyingAddress:        c.hex(name+".underlying_address", m.UnderlyingAddress),
+		Symbol:                   m.Symbol,
+		Name:                     m.Name,
+		UnderlyingSymbol:         m.UnderlyingSymbol,
+		UnderlyingName:           m.UnderlyingName,
+		InterestRateModelAddress: c.hex(name+".interest_rate_model_address", m.InterestRateModelAddress),
+	}
+}
+
+func (c *converter) ctokenRequest(r models.CTokenRequest) *CTokenRequest {
+	return &CTokenRequest{
+		Addresses:      c.addresses("request.addresses", r.Addresses),
+		BlockNumber:    c.uint32("request.block_number", r.BlockNumber),
+		BlockTimestamp: c.uint32("request.block_timestamp", r.BlockTimestamp),
+	}
+}
+
+func (c *converter) ctokenRequestModel(m *CTokenRequest) models.CTokenRequest {
+	if m == nil {
+		return models.CTokenRequest{}
+	}
+	return models.CTokenRequest{
+		Addresses:      c.hexes("request.addresses", m.Addresses),
+		BlockNumber:    int(m.BlockNumber),
+		BlockTimestamp: int(m.BlockTimestamp),
+	}
+}
+
+func (c *converter) paginationSummary(s models.PaginationSummary) *PaginationSummary {
+	return &PaginationSummary{
+		PageNumber:   c.uint32("pagination_summary.page_number", s.PageNumber),
+		PageSize:     c.uint32("pagination_summary.page_size", s.PageSize),
+		TotalEntries: c.uint32("pagination_summary.total_entries", s.TotalEntries),
+		TotalPages:   c.uint32("pagination_summary.total_pages", s.TotalPages),
+	}
+}
+
+func (c *converter) precise(name string, v models.Value) *Precise {
+	p, err := NewPrecise(v.Value)
+	if err != nil {
+		c.fail(name, err)
+	}
+	return p
+}
+
+func (c *converter) value(name string, p *Precise) models.Value {
+	d, err := p.Decimal()
+	if err != nil {
+		c.fail(name, err)
+	}
+	return models.Value{Value: d}
+}
+
+func (c *converter) uint32(name string, n int) uint32 {
+	if n < 0 || int64(n) > math.MaxUint32 {
+		c.fail(name, fmt.Errorf("%d is out of range", n))
+		return 0
+	}
+	return uint32(n)
+}
+
+// address decodes a hex address, the empty string is encoded as no bytes
+func (c *converter) address(name, address string) []byte {
+	if address == "" {
+		return nil
+	}
+	if !common.IsHexAddress(address) {
+		c.fail(name, fmt.Errorf("invalid address %q", address))
+		return nil
+	}
+	return common.HexToAddress(address).Bytes()
+}
+
+func (c *converter) addresses(name string, addresses []string) [][]byte {
+	var out [][]byte
+	for i, address := range addresses {
+		out = append(out, c.address(fmt.Sprintf("%s[%d]", name, i), address))
+	}
+	return out
+}
+
+// hex encodes an address as lower case hex, no bytes are encoded as the empty string
+func (c *converter) hex(name string, address []byte) string {
+	if len(address) == 0 {
+		return ""
+	}
+	if len(address) != common.AddressLength {
+		c.fail(name, fmt.Errorf("address must be %d bytes, got %d", common.AddressLength, len(address)))
+		return ""
+	}
+	return "0x" + hex.EncodeToString(address)
+}
+
+func (c *converter) hexes(name string, addresses [][]byte) []string {
+	var out []string
+	for i, address := range addresses {
+		out = append(out, c.hex(fmt.Sprintf("%s[%d]", name, i), address))
+	}
+	return out
+}
+
+// float32Decimal returns the shortest decimal that rounds to f, so that
+// values such as 1.08 survive the round trip through a float32
+func (c *converter) float32Decimal(name string, f float32) models.Decimal {
+	if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+		c.fail(name, fmt.Errorf("%v is not a number", f))
+		return models.Decimal{}
+	}
+	d, err := models.ParseDecimal(strconv.FormatFloat(float64(f), 'g', -1, 32))
+	if err != nil {
+		c.fail(name, err)
+	}
+	return d
+}
+
+func float32Of(d models.Decimal) float32 {
+	f, _ := d.Float64()
+	return float32(f)
+}
+
+func copyFieldErrors(fieldErrors map[string]string) map[string]string {
+	if fieldErrors == nil {
+		return nil
+	}
+	out := make(map[string]string, len(fieldErrors))
+	for key, value := range fieldErrors {
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package API_Presidio
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/musinit/go-defi/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFixture(t *testing.T, name string, v interface{}) {
+	data, err := os.ReadFile("../mockapi/fixtures/" + name)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, v))
+}
+
+func Test_AccountResponseRoundTrip(t *testing.T) {
+	var resp models.AccountResponse
+	readFixture(t, "account.json", &resp)
+	block := 15000000
+	resp.Accounts[0].BlockUpdated = &block
+	resp.Request = models.Request{
+		Addresses:           []string{resp.Accounts[0].Address},
+		BlockNumber:         15000000,
+		PageNumber:          1,
+		PageSize:            10,
+		MaxHealth:           &models.Value{Value: models.MustParseDecimal("1.2")},
+		MinBorrowValueInEth: &models.Value{Value: models.MustParseDecimal("0.000000000000000001")},
+	}
+	resp.PaginationSummary = models.PaginationSummary{PageNumber: 1, PageSize: 10, TotalEntries: 27, TotalPages: 3}
+
+	msg, err := NewAccountResponse(&resp)
+	require.NoError(t, err)
+	require.Len(t, msg.Accounts, len(resp.Accounts))
+	assert.Len(t, msg.Accounts[0].Address, 20)
+	assert.Equal(t, int32(15000000), msg.Accounts[0].BlockUpdated)
+
+	back, err := msg.Model()
+	require.NoError(t, err)
+	assertJSONEqual(t, &resp, back)
+}
+
+func Test_CTokenResponseRoundTrip(t *testing.T) {
+	var resp models.CTokenResponse
+	readFixture(t, "ctoken.json", &resp)
+	resp.Request = models.CTokenRequest{Addresses: []string{resp.CToken[0].TokenAddress}, BlockTimestamp: 1571875217}
+
+	msg, err := NewCTokenResponse(&resp)
+	require.NoError(t, err)
+	require.Len(t, msg.CToken, len(resp.CToken))
+	assert.Equal(t, resp.CToken[0].TotalSupply.Value.String(), msg.CToken[0].TotalSupply.Value)
+
+	back, err := msg.Model()
+	require.NoError(t, err)
+	assertJSONEqual(t, &resp, back)
+}
+
+func Test_ConvertErrors(t *testing.T) {
+	_, err := NewAccount(models.Account{Address: "not an address"})
+	assert.Error(t, err)
+
+	_, err = NewAccountCToken(models.AccountToken{
+		Address:                 "0x5d3a536e4d6dbd6114cc1ead35777bab948e3643",
+		SupplyBalanceUnderlying: models.Value{Value: models.NewDecimal(-1)},
+	})
+	assert.ErrorContains(t, err, "token.supply_balance_underlying")
+
+	_, err = NewPaginationSummary(models.PaginationSummary{PageNumber: -1})
+	assert.Error(t, err)
+
+	_, err = (&CToken{TokenAddress: []byte{1, 2, 3}}).Model()
+	assert.Error(t, err)
+
+	_, err = (&Account{Health: &Precise{Value: "abc"}}).Model()
+	assert.Error(t, err)
+}
+
+func Test_ConvertAddresses(t *testing.T) {
+	msg, err := NewCToken(models.CToken{TokenAddress: "0x5D3a536E4D6DbD6114cc1Ead35777bAB948E3643"})
+	require.NoError(t, err)
+	ctoken, err := msg.Model()
+	require.NoError(t, err)
+	assert.Equal(t, "0x5d3a536e4d6dbd6114cc1ead35777bab948e3643", ctoken.TokenAddress)
+	assert.Empty(t, ctoken.UnderlyingAddress)
+	assert.Nil(t, msg.UnderlyingAddress)
+}
+
+func Test_ConvertError(t *testing.T) {
+	apiErr := &models.APIError{Code: models.InvalidPageSize, Message: "bad", FieldErrors: map[string]string{"page_size": "too big"}}
+	assert.Equal(t, apiErr, NewError(apiErr).Model())
+	assert.Nil(t, NewError(nil))
+	assert.Nil(t, (*Error)(nil).Model())
+
+	v, err := ValueOf(nil)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+	p, err := NewValue(&models.Value{Value: models.MustParseDecimal("1.05")})
+	require.NoError(t, err)
+	v, err = ValueOf(p)
+	require.NoError(t, err)
+	assert.Equal(t, "1.05", v.Value.String())
+}
+
+// assertJSONEqual compares models through their json encoding, as decimals
+// holding the same number may differ in their internal representation
+func assertJSONEqual(t *testing.T, expected, actual interface{}) {
+	want, err := json.Marshal(expected)
+	require.NoError(t, err)
+	got, err := json.Marshal(actual)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(want), string(got))
+}
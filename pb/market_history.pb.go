@@ -0,0 +1,551 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: market_history.proto
+
+package API_Presidio
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/gogo/protobuf/proto"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type MarketHistoryResponseErrorCodes int32
+
+const (
+	MarketHistoryResponse_NO_ERROR       MarketHistoryResponseErrorCodes = 0
+	MarketHistoryResponse_INTERNAL_ERROR MarketHistoryResponseErrorCodes = 1
+)
+
+var MarketHistoryResponseErrorCodes_name = map[int32]string{
+	0: "NO_ERROR",
+	1: "INTERNAL_ERROR",
+}
+
+var MarketHistoryResponseErrorCodes_value = map[string]int32{
+	"NO_ERROR":       0,
+	"INTERNAL_ERROR": 1,
+}
+
+func (x MarketHistoryResponseErrorCodes) String() string {
+	return proto.EnumName(MarketHistoryResponseErrorCodes_name, int32(x))
+}
+
+func (MarketHistoryResponseErrorCodes) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c95b15a56cb15e9a, []int{4, 0}
+}
+
+// *
+// The request to the market history API specifies the cToken to retrieve
+// history for, the time range to cover and how many buckets to split
+// that range into. The following shows an example set of request
+// parameters in JSON:
+//
+//	<code>{
+//	  "asset": "0x6c8c6b02e7b2be14d4fa6022dfd6d75921d90e4e"
+//	  "min_block_timestamp": 1571875200
+//	  "max_block_timestamp": 1574467200
+//	  "num_buckets": 10
+//	}</code>
+type MarketHistoryRequest struct {
+	// The address of the cToken to retrieve history for, e.g.: "0x..."
+	Asset []byte `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	// The unix timestamp of the start of the time range.
+	MinBlockTimestamp uint32 `protobuf:"varint,2,opt,name=min_block_timestamp,json=minBlockTimestamp,proto3" json:"min_block_timestamp,omitempty"`
+	// The unix timestamp of the end of the time range.
+	MaxBlockTimestamp uint32 `protobuf:"varint,3,opt,name=max_block_timestamp,json=maxBlockTimestamp,proto3" json:"max_block_timestamp,omitempty"`
+	// The number of buckets the time range is split into, one data point is returned per bucket.
+	NumBuckets           uint32   `protobuf:"varint,4,opt,name=num_buckets,json=numBuckets,proto3" json:"num_buckets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MarketHistoryRequest) Reset()         { *m = MarketHistoryRequest{} }
+func (m *MarketHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*MarketHistoryRequest) ProtoMessage()    {}
+func (*MarketHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c95b15a56cb15e9a, []int{0}
+}
+func (m *MarketHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MarketHistoryRequest.Unmarshal(m, b)
+}
+func (m *MarketHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MarketHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *MarketHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MarketHistoryRequest.Merge(m, src)
+}
+func (m *MarketHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_MarketHistoryRequest.Size(m)
+}
+func (m *MarketHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MarketHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MarketHistoryRequest proto.InternalMessageInfo
+
+func (m *MarketHistoryRequest) GetAsset() []byte {
+	if m != nil {
+		return m.Asset
+	}
+	return nil
+}
+
+func (m *MarketHistoryRequest) GetMinBlockTimestamp() uint32 {
+	if m != nil {
+		return m.MinBlockTimestamp
+	}
+	return 0
+}
+
+func (m *MarketHistoryRequest) GetMaxBlockTimestamp() uint32 {
+	if m != nil {
+		return m.MaxBlockTimestamp
+	}
+	return 0
+}
+
+func (m *MarketHistoryRequest) GetNumBuckets() uint32 {
+	if m != nil {
+		return m.NumBuckets
+	}
+	return 0
+}
+
+// *
+// A rate at a point in time.
+//
+//	<code>{
+//	  "block_number": 8830740,
+//	  "block_timestamp": 1571875217,
+//	  "rate": 0.0532
+//	}</code>
+type RateBucket struct {
+	// The block number the rate was recorded at
+	BlockNumber uint32 `protobuf:"varint,1,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	// The unix timestamp of the block
+	BlockTimestamp uint32 `protobuf:"varint,2,opt,name=block_timestamp,json=blockTimestamp,proto3" json:"block_timestamp,omitempty"`
+	// The annualized rate, or the cToken / underlying exchange rate
+	Rate                 float64  `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RateBucket) Reset()         { *m = RateBucket{} }
+func (m *RateBucket) String() string { return proto.CompactTextString(m) }
+func (*RateBucket) ProtoMessage()    {}
+func (*RateBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c95b15a56cb15e9a, []int{1}
+}
+func (m *RateBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RateBucket.Unmarshal(m, b)
+}
+func (m *RateBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RateBucket.Marshal(b, m, deterministic)
+}
+func (m *RateBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RateBucket.Merge(m, src)
+}
+func (m *RateBucket) XXX_Size() int {
+	return xxx_messageInfo_RateBucket.Size(m)
+}
+func (m *RateBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_RateBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RateBucket proto.InternalMessageInfo
+
+func (m *RateBucket) GetBlockNumber() uint32 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *RateBucket) GetBlockTimestamp() uint32 {
+	if m != nil {
+		return m.BlockTimestamp
+	}
+	return 0
+}
+
+func (m *RateBucket) GetRate() float64 {
+	if m != nil {
+		return m.Rate
+	}
+	return 0
+}
+
+// *
+// A total amount of tokens at a point in time.
+//
+//	<code>{
+//	  "block_number": 8830740,
+//	  "block_timestamp": 1571875217,
+//	  "total": {"value": "24138541.28364129"}
+//	}</code>
+type TotalBucket struct {
+	// The block number the total was recorded at
+	BlockNumber uint32 `protobuf:"varint,1,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	// The unix timestamp of the block
+	BlockTimestamp uint32 `protobuf:"varint,2,opt,name=block_timestamp,json=blockTimestamp,proto3" json:"block_timestamp,omitempty"`
+	// The total amount of tokens
+	Total                *Precise `protobuf:"bytes,3,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TotalBucket) Reset()         { *m = TotalBucket{} }
+func (m *TotalBucket) String() string { return proto.CompactTextString(m) }
+func (*TotalBucket) ProtoMessage()    {}
+func (*TotalBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c95b15a56cb15e9a, []int{2}
+}
+func (m *TotalBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TotalBucket.Unmarshal(m, b)
+}
+func (m *TotalBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TotalBucket.Marshal(b, m, deterministic)
+}
+func (m *TotalBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TotalBucket.Merge(m, src)
+}
+func (m *TotalBucket) XXX_Size() int {
+	return xxx_messageInfo_TotalBucket.Size(m)
+}
+func (m *TotalBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_TotalBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TotalBucket proto.InternalMessageInfo
+
+func (m *TotalBucket) GetBlockNumber() uint32 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *TotalBucket) GetBlockTimestamp() uint32 {
+	if m != nil {
+		return m.BlockTimestamp
+	}
+	return 0
+}
+
+func (m *TotalBucket) GetTotal() *Precise {
+	if m != nil {
+		return m.Total
+	}
+	return nil
+}
+
+// *
+// The price of the underlying token at a point in time.
+//
+//	<code>{
+//	  "block_number": 8830740,
+//	  "block_timestamp": 1571875217,
+//	  "price": {"value": "0.99827"}
+//	}</code>
+type PriceBucket struct {
+	// The block number the price was recorded at
+	BlockNumber uint32 `protobuf:"varint,1,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	// The unix timestamp of the block
+	BlockTimestamp uint32 `protobuf:"varint,2,opt,name=block_timestamp,json=blockTimestamp,proto3" json:"block_timestamp,omitempty"`
+	// The price of the underlying token in usd
+	Price                *Precise `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PriceBucket) Reset()         { *m = PriceBucket{} }
+func (m *PriceBucket) String() string { return proto.CompactTextString(m) }
+func (*PriceBucket) ProtoMessage()    {}
+func (*PriceBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c95b15a56cb15e9a, []int{3}
+}
+func (m *PriceBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PriceBucket.Unmarshal(m, b)
+}
+func (m *PriceBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PriceBucket.Marshal(b, m, deterministic)
+}
+func (m *PriceBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PriceBucket.Merge(m, src)
+}
+func (m *PriceBucket) XXX_Size() int {
+	return xxx_messageInfo_PriceBucket.Size(m)
+}
+func (m *PriceBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_PriceBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PriceBucket proto.InternalMessageInfo
+
+func (m *PriceBucket) GetBlockNumber() uint32 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *PriceBucket) GetBlockTimestamp() uint32 {
+	if m != nil {
+		return m.BlockTimestamp
+	}
+	return 0
+}
+
+func (m *PriceBucket) GetPrice() *Precise {
+	if m != nil {
+		return m.Price
+	}
+	return nil
+}
+
+// The market history API returns bucketed historical data for a single cToken.
+type MarketHistoryResponse struct {
+	// If set and non-zero, indicates an error returning data.
+	// <pre>NO_ERROR = 0
+	// INTERNAL_ERROR = 1</pre>
+	Error *Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// The request parameters are echoed in the response.
+	Request *MarketHistoryRequest `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	// The floating supply interest rate of each bucket
+	SupplyRates []*RateBucket `protobuf:"bytes,3,rep,name=supply_rates,json=supplyRates,proto3" json:"supply_rates,omitempty"`
+	// The floating borrow interest rate of each bucket
+	BorrowRates []*RateBucket `protobuf:"bytes,4,rep,name=borrow_rates,json=borrowRates,proto3" json:"borrow_rates,omitempty"`
+	// The cToken / underlying exchange rate of each bucket
+	ExchangeRates []*RateBucket `protobuf:"bytes,5,rep,name=exchange_rates,json=exchangeRates,proto3" json:"exchange_rates,omitempty"`
+	// The number of cTokens in existence in each bucket
+	TotalSupplyHistory []*TotalBucket `protobuf:"bytes,6,rep,name=total_supply_history,json=totalSupplyHistory,proto3" json:"total_supply_history,omitempty"`
+	// The amount of underlying tokens borrowed in each bucket
+	TotalBorrowsHistory []*TotalBucket `protobuf:"bytes,7,rep,name=total_borrows_history,json=totalBorrowsHistory,proto3" json:"total_borrows_history,omitempty"`
+	// The usd price of the underlying token in each bucket
+	PricesUsd            []*PriceBucket `protobuf:"bytes,8,rep,name=prices_usd,json=pricesUsd,proto3" json:"prices_usd,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *MarketHistoryResponse) Reset()         { *m = MarketHistoryResponse{} }
+func (m *MarketHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*MarketHistoryResponse) ProtoMessage()    {}
+func (*MarketHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c95b15a56cb15e9a, []int{4}
+}
+func (m *MarketHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MarketHistoryResponse.Unmarshal(m, b)
+}
+func (m *MarketHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MarketHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *MarketHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MarketHistoryResponse.Merge(m, src)
+}
+func (m *MarketHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_MarketHistoryResponse.Size(m)
+}
+func (m *MarketHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MarketHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MarketHistoryResponse proto.InternalMessageInfo
+
+func (m *MarketHistoryResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *MarketHistoryResponse) GetRequest() *MarketHistoryRequest {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *MarketHistoryResponse) GetSupplyRates() []*RateBucket {
+	if m != nil {
+		return m.SupplyRates
+	}
+	return nil
+}
+
+func (m *MarketHistoryResponse) GetBorrowRates() []*RateBucket {
+	if m != nil {
+		return m.BorrowRates
+	}
+	return nil
+}
+
+func (m *MarketHistoryResponse) GetExchangeRates() []*RateBucket {
+	if m != nil {
+		return m.ExchangeRates
+	}
+	return nil
+}
+
+func (m *MarketHistoryResponse) GetTotalSupplyHistory() []*TotalBucket {
+	if m != nil {
+		return m.TotalSupplyHistory
+	}
+	return nil
+}
+
+func (m *MarketHistoryResponse) GetTotalBorrowsHistory() []*TotalBucket {
+	if m != nil {
+		return m.TotalBorrowsHistory
+	}
+	return nil
+}
+
+func (m *MarketHistoryResponse) GetPricesUsd() []*PriceBucket {
+	if m != nil {
+		return m.PricesUsd
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("API.Presidio.MarketHistoryResponseErrorCodes", MarketHistoryResponseErrorCodes_name, MarketHistoryResponseErrorCodes_value)
+	proto.RegisterType((*MarketHistoryRequest)(nil), "API.Presidio.MarketHistoryRequest")
+	proto.RegisterType((*RateBucket)(nil), "API.Presidio.RateBucket")
+	proto.RegisterType((*TotalBucket)(nil), "API.Presidio.TotalBucket")
+	proto.RegisterType((*PriceBucket)(nil), "API.Presidio.PriceBucket")
+	proto.RegisterType((*MarketHistoryResponse)(nil), "API.Presidio.MarketHistoryResponse")
+}
+
+func init() { proto.RegisterFile("market_history.proto", fileDescriptor_c95b15a56cb15e9a) }
+
+var fileDescriptor_c95b15a56cb15e9a = []byte{
+	// 561 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x94, 0xd1, 0x6e, 0xd3, 0x30,
+	0x14, 0x86, 0xc9, 0xda, 0x6e, 0xe3, 0x24, 0x2d, 0xcc, 0x6d, 0x45, 0xa8, 0x84, 0x28, 0xe1, 0x82,
+	0x22, 0xa4, 0x4e, 0x0a, 0x37, 0x48, 0x20, 0xa1, 0x15, 0x55, 0x62, 0x82, 0x75, 0x95, 0x57, 0xae,
+	0x23, 0x27, 0xb5, 0xd6, 0xa8, 0x8d, 0x1d, 0x6c, 0x07, 0xba, 0x5b, 0x2e, 0xe0, 0x01, 0x78, 0x0e,
+	0x1e, 0x82, 0x67, 0xe0, 0x15, 0x78, 0x10, 0x14, 0x3b, 0x85, 0xa6, 0x54, 0x94, 0x9b, 0xdd, 0xd9,
+	0xc7, 0xff, 0xff, 0xf9, 0x6f, 0xcf, 0x89, 0xa1, 0x95, 0x10, 0x31, 0xa7, 0x2a, 0x98, 0xc5, 0x52,
+	0x71, 0x71, 0xd5, 0x4f, 0x05, 0x57, 0x1c, 0x39, 0x27, 0xe3, 0xd3, 0xfe, 0x58, 0x50, 0x19, 0x4f,
+	0x63, 0xde, 0x39, 0x22, 0x8c, 0x71, 0x45, 0x54, 0xcc, 0x99, 0x34, 0x82, 0x8e, 0x13, 0xf1, 0x24,
+	0xe1, 0xcc, 0xec, 0xbc, 0x6f, 0x16, 0xb4, 0xce, 0x34, 0xe7, 0xb5, 0xc1, 0x60, 0xfa, 0x3e, 0xa3,
+	0x52, 0xa1, 0x16, 0xd4, 0x88, 0x94, 0x54, 0xb9, 0x56, 0xd7, 0xea, 0x39, 0xd8, 0x6c, 0x50, 0x1f,
+	0x9a, 0x49, 0xcc, 0x82, 0x70, 0xc1, 0xa3, 0x79, 0xa0, 0xe2, 0x84, 0x4a, 0x45, 0x92, 0xd4, 0xdd,
+	0xeb, 0x5a, 0xbd, 0x3a, 0x3e, 0x4a, 0x62, 0x36, 0xc8, 0x4f, 0x26, 0xab, 0x03, 0xad, 0x27, 0xcb,
+	0xbf, 0xf4, 0x95, 0x42, 0x4f, 0x96, 0x1b, 0xfa, 0xfb, 0x60, 0xb3, 0x2c, 0x09, 0xc2, 0x2c, 0x9a,
+	0x53, 0x25, 0xdd, 0xaa, 0xd6, 0x01, 0xcb, 0x92, 0x81, 0xa9, 0x78, 0x0b, 0x00, 0x4c, 0x14, 0x35,
+	0x5b, 0xf4, 0x00, 0x1c, 0x83, 0x66, 0x59, 0x12, 0x52, 0xa1, 0xb3, 0xd6, 0xb1, 0xad, 0x6b, 0x23,
+	0x5d, 0x42, 0x8f, 0xe0, 0xd6, 0xf6, 0xb4, 0x8d, 0xb0, 0x7c, 0x35, 0x82, 0xaa, 0x20, 0x8a, 0xea,
+	0x6c, 0x16, 0xd6, 0x6b, 0xef, 0xb3, 0x05, 0xf6, 0x84, 0x2b, 0xb2, 0xb8, 0x86, 0xfb, 0x9e, 0x40,
+	0x4d, 0xe5, 0x68, 0x7d, 0xa1, 0xed, 0xb7, 0xfb, 0xeb, 0x8d, 0xcb, 0x17, 0x51, 0x2c, 0x29, 0x36,
+	0x1a, 0x1d, 0x64, 0x2c, 0xe2, 0x88, 0x5e, 0x4f, 0x90, 0x34, 0x47, 0xef, 0x08, 0xa2, 0x35, 0xde,
+	0xf7, 0x2a, 0xb4, 0x37, 0xe6, 0x45, 0xa6, 0x9c, 0x49, 0x8a, 0x1e, 0x43, 0x8d, 0x0a, 0xc1, 0x4d,
+	0x16, 0xdb, 0x6f, 0x96, 0x31, 0xc3, 0xfc, 0x08, 0x1b, 0x05, 0x7a, 0x01, 0x07, 0xc2, 0x8c, 0x99,
+	0x8e, 0x64, 0xfb, 0x5e, 0x59, 0xbc, 0x6d, 0x20, 0xf1, 0xca, 0x82, 0x9e, 0x83, 0x23, 0xb3, 0x34,
+	0x5d, 0x5c, 0x05, 0x79, 0x8f, 0xa4, 0x5b, 0xe9, 0x56, 0x7a, 0xb6, 0xef, 0x96, 0x11, 0x7f, 0x86,
+	0x04, 0xdb, 0x46, 0x9d, 0x57, 0x64, 0x6e, 0x0e, 0xb9, 0x10, 0xfc, 0x63, 0x61, 0xae, 0xee, 0x32,
+	0x1b, 0xb5, 0x31, 0xbf, 0x84, 0x06, 0x5d, 0x46, 0x33, 0xc2, 0x2e, 0x69, 0x61, 0xaf, 0xed, 0xb0,
+	0xd7, 0x57, 0x7a, 0x03, 0x78, 0x03, 0x2d, 0xdd, 0xcf, 0xa0, 0xf8, 0x01, 0xc5, 0xa7, 0xeb, 0xee,
+	0x6b, 0xcc, 0xdd, 0x32, 0x66, 0x6d, 0xf0, 0x30, 0xd2, 0xb6, 0x0b, 0xed, 0x2a, 0xfe, 0x17, 0x74,
+	0x06, 0x6d, 0x03, 0x33, 0x11, 0xe5, 0x6f, 0xda, 0xc1, 0x2e, 0x5a, 0x53, 0xfb, 0x06, 0xc6, 0xb6,
+	0xc2, 0x3d, 0x03, 0xd0, 0x2d, 0x96, 0x41, 0x26, 0xa7, 0xee, 0xe1, 0x36, 0xc6, 0xda, 0x04, 0xe2,
+	0x9b, 0x46, 0xfc, 0x4e, 0x4e, 0xbd, 0x3e, 0x80, 0xee, 0xeb, 0x2b, 0x3e, 0xa5, 0x12, 0x39, 0x70,
+	0x38, 0x3a, 0x0f, 0x86, 0x18, 0x9f, 0xe3, 0xdb, 0x37, 0x10, 0x82, 0xc6, 0xe9, 0x68, 0x32, 0xc4,
+	0xa3, 0x93, 0xb7, 0x45, 0xcd, 0xf2, 0xbf, 0x6c, 0xbe, 0x39, 0x17, 0x54, 0x7c, 0x88, 0x23, 0x8a,
+	0x38, 0xd4, 0x2e, 0x05, 0x49, 0x67, 0xe8, 0x3f, 0xe6, 0xa1, 0xf3, 0xf0, 0x9f, 0x1a, 0x33, 0x94,
+	0xde, 0xbd, 0x4f, 0x3f, 0x7e, 0x7e, 0xdd, 0xbb, 0x83, 0xda, 0xc7, 0xe5, 0xc7, 0xf2, 0x58, 0xdf,
+	0x13, 0xee, 0xeb, 0x47, 0xf0, 0xe9, 0xaf, 0x01, 0x00, 0xcb, 0xbf, 0xed, 0x29, 0x4b, 0x05, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// MarketHistoryServiceClient is the client API for MarketHistoryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MarketHistoryServiceClient interface {
+	Graph(ctx context.Context, in *MarketHistoryRequest, opts ...grpc.CallOption) (*MarketHistoryResponse, error)
+}
+
+type marketHistoryServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMarketHistoryServiceClient(cc *grpc.ClientConn) MarketHistoryServiceClient {
+	return &marketHistoryServiceClient{cc}
+}
+
+func (c *marketHistoryServiceClient) Graph(ctx context.Context, in *MarketHistoryRequest, opts ...grpc.CallOption) (*MarketHistoryResponse, error) {
+	out := new(MarketHistoryResponse)
+	err := c.cc.Invoke(ctx, "/API.Presidio.MarketHistoryService/graph", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MarketHistoryServiceServer is the server API for MarketHistoryService service.
+type MarketHistoryServiceServer interface {
+	Graph(context.Context, *MarketHistoryRequest) (*MarketHistoryResponse, error)
+}
+
+func RegisterMarketHistoryServiceServer(s *grpc.Server, srv MarketHistoryServiceServer) {
+	s.RegisterService(&_MarketHistoryService_serviceDesc, srv)
+}
+
+func _MarketHistoryService_Graph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarketHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketHistoryServiceServer).Graph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API.Presidio.MarketHistoryService/Graph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketHistoryServiceServer).Graph(ctx, req.(*MarketHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _MarketHistoryService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "API.Presidio.MarketHistoryService",
+	HandlerType: (*MarketHistoryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "graph",
+			Handler:    _MarketHistoryService_Graph_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "market_history.proto",
+}
@@ -0,0 +1,43 @@
+package API_Presidio
+
+import (
+	"errors"
+
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// NewPrecise returns the Precise message holding d, which must not be negative
+func NewPrecise(d models.Decimal) (*Precise, error) {
+	if d.Sign() < 0 {
+		return nil, errors.New("precise values must not be negative")
+	}
+	return &Precise{Value: d.String()}, nil
+}
+
+// Decimal parses the value of the message, a nil message or an empty value is 0
+func (m *Precise) Decimal() (models.Decimal, error) {
+	if m == nil || m.Value == "" {
+		return models.Decimal{}, nil
+	}
+	d, err := models.ParseDecimal(m.Value)
+	if err != nil {
+		return models.Decimal{}, err
+	}
+	if d.Sign() < 0 {
+		return models.Decimal{}, errors.New("precise values must not be negative")
+	}
+	return d, nil
+}
+
+// NewSignedPrecise returns the SignedPrecise message holding d
+func NewSignedPrecise(d models.Decimal) *SignedPrecise {
+	return &SignedPrecise{Value: d.String()}
+}
+
+// Decimal parses the value of the message, a nil message or an empty value is 0
+func (m *SignedPrecise) Decimal() (models.Decimal, error) {
+	if m == nil || m.Value == "" {
+		return models.Decimal{}, nil
+	}
+	return models.ParseDecimal(m.Value)
+}
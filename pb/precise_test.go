@@ -0,0 +1,34 @@
+package API_Presidio
+
+import (
+	"testing"
+
+	"github.com/musinit/go-defi/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Precise(t *testing.T) {
+	value := "1.07264275673050348990755599431194797431802239523113293682619605751591901"
+	d, err := (&Precise{Value: value}).Decimal()
+	require.NoError(t, err)
+	assert.Equal(t, value, d.String())
+
+	p, err := NewPrecise(d)
+	require.NoError(t, err)
+	assert.Equal(t, value, p.Value)
+
+	_, err = NewPrecise(d.Neg())
+	assert.Error(t, err)
+	_, err = (&Precise{Value: "-1"}).Decimal()
+	assert.Error(t, err)
+
+	var missing *Precise
+	d, err = missing.Decimal()
+	require.NoError(t, err)
+	assert.True(t, d.IsZero())
+
+	signed, err := NewSignedPrecise(models.MustParseDecimal("-2.5")).Decimal()
+	require.NoError(t, err)
+	assert.Equal(t, "-2.5", signed.String())
+}
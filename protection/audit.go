@@ -0,0 +1,91 @@
+package protection
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// AuditEntry records a single transaction of a protection action, sent or,
+// in dry-run mode, planned
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Account string    `json:"account"`
+	// Action is the policy action, and Operation the transaction sent for
+	// it: repay, supply or redeem
+	Action    Action `json:"action"`
+	Operation string `json:"operation"`
+	Step      int    `json:"step"`
+	CToken    string `json:"ctoken"`
+	// Amount is in units of the underlying token
+	Amount         models.Decimal `json:"amount"`
+	ValueInEth     models.Decimal `json:"value_in_eth"`
+	HealthBefore   models.Decimal `json:"health_before"`
+	ExpectedHealth models.Decimal `json:"expected_health"`
+	DryRun         bool           `json:"dry_run"`
+	Error          string         `json:"error,omitempty"`
+}
+
+// AuditLog is used to record every protection transaction
+type AuditLog interface {
+	Record(entry AuditEntry) error
+}
+
+// FileAuditLog is an AuditLog appending entries as json lines to a file
+type FileAuditLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileAuditLog returns an audit log appending to path, the file is
+// created on the first entry if needed
+func NewFileAuditLog(path string) (*FileAuditLog, error) {
+	if path == "" {
+		return nil, errors.New("audit log path is empty")
+	}
+	return &FileAuditLog{path: path}, nil
+}
+
+// Record appends the entry to the file
+func (l *FileAuditLog) Record(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// MemoryAuditLog is an AuditLog keeping entries in memory
+type MemoryAuditLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+// Record keeps the entry
+func (l *MemoryAuditLog) Record(entry AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+// Entries returns the recorded entries
+func (l *MemoryAuditLog) Entries() []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]AuditEntry(nil), l.entries...)
+}
@@ -0,0 +1,71 @@
+package protection
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/musinit/go-defi/v2/client"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// Executor is used to send the transactions of a protection action. Amounts
+// are in units of the underlying token of the ctoken
+type Executor interface {
+	RepayBorrow(ctx context.Context, ctoken client.Address, amount models.Decimal) error
+	Supply(ctx context.Context, ctoken client.Address, amount models.Decimal) error
+	RedeemUnderlying(ctx context.Context, ctoken client.Address, amount models.Decimal) error
+}
+
+// ChainExecutor is an Executor sending transactions through a BClient. The
+// wallet of the client must hold the repaid and supplied tokens, and have
+// approved the erc20 markets to spend them. On the ether market the amounts
+// are sent as the value of the transactions
+type ChainExecutor struct {
+	bc *client.BClient
+}
+
+// NewChainExecutor returns an executor sending transactions through bc
+func NewChainExecutor(bc *client.BClient) *ChainExecutor {
+	return &ChainExecutor{bc: bc}
+}
+
+// RepayBorrow repays amount of the token borrowed from ctoken
+func (e *ChainExecutor) RepayBorrow(ctx context.Context, ctoken client.Address, amount models.Decimal) error {
+	raw, err := e.raw(ctx, ctoken, amount)
+	if err != nil {
+		return err
+	}
+	return e.bc.RepayBorrow(ctx, ctoken, raw)
+}
+
+// Supply supplies amount of the underlying token of ctoken
+func (e *ChainExecutor) Supply(ctx context.Context, ctoken client.Address, amount models.Decimal) error {
+	raw, err := e.raw(ctx, ctoken, amount)
+	if err != nil {
+		return err
+	}
+	return e.bc.Mint(ctx, ctoken, raw)
+}
+
+// RedeemUnderlying withdraws amount of the underlying token supplied to ctoken
+func (e *ChainExecutor) RedeemUnderlying(ctx context.Context, ctoken client.Address, amount models.Decimal) error {
+	raw, err := e.raw(ctx, ctoken, amount)
+	if err != nil {
+		return err
+	}
+	return e.bc.RedeemUnderlying(ctx, ctoken, raw)
+}
+
+// raw scales amount by the decimals of the underlying token, rounding down
+func (e *ChainExecutor) raw(ctx context.Context, ctoken client.Address, amount models.Decimal) (*big.Int, error) {
+	decimals, err := e.bc.UnderlyingDecimals(ctx, ctoken)
+	if err != nil {
+		return nil, err
+	}
+	raw := amount.Shift(int(decimals)).Int()
+	if raw.Sign() <= 0 {
+		return nil, errors.New("amount rounds to zero")
+	}
+	return raw, nil
+}
@@ -0,0 +1,447 @@
+// Package protection deleverages compound accounts nearing liquidation,
+// following per account policies
+package protection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/client"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// Action is how a policy restores the health of an account
+type Action string
+
+const (
+	// Repay repays borrows of the RepayToken from the wallet
+	Repay Action = "repay"
+	// Supply supplies more of the CollateralToken from the wallet
+	Supply Action = "supply"
+	// RedeemRepay redeems the CollateralToken and repays the RepayToken, in
+	// steps. It is meant for accounts borrowing the token they supply, or
+	// wallets converting the redeemed token between steps
+	RedeemRepay Action = "redeem-repay"
+)
+
+// DefaultMaxSteps is the number of steps of a policy without MaxSteps
+const DefaultMaxSteps = 5
+
+var (
+	// ErrNoPolicy is returned when checking an account without a policy
+	ErrNoPolicy = errors.New("no policy for account")
+	// ErrBudgetExhausted is returned when an account needs protecting but the
+	// policy's MaxSpend is used up
+	ErrBudgetExhausted = errors.New("max spend reached")
+)
+
+// Policy describes how to protect an account. Once its health falls to or
+// below TriggerHealth, the Action is taken until the health is back at
+// TargetHealth, or MaxSpend is used up
+type Policy struct {
+	Account       string
+	Action        Action
+	TriggerHealth models.Decimal
+	TargetHealth  models.Decimal
+	// RepayToken is the ctoken whose borrow is repaid, for Repay and RedeemRepay
+	RepayToken client.Address
+	// CollateralToken is the ctoken supplied by Supply, or redeemed by RedeemRepay
+	CollateralToken client.Address
+	// MaxSpend is the total value in eth the protector may repay or supply
+	// for the account over its lifetime
+	MaxSpend models.Decimal
+	// MaxStepValue caps the value in eth of a single step, 0 does not
+	MaxStepValue models.Decimal
+	// MaxSteps caps the steps of a single check, 0 uses DefaultMaxSteps
+	MaxSteps int
+}
+
+// Validate returns an error for an incomplete or inconsistent policy
+func (p Policy) Validate() error {
+	if !common.IsHexAddress(p.Account) {
+		return errors.New("invalid account address")
+	}
+	if p.TriggerHealth.Sign() <= 0 || p.TargetHealth.Cmp(p.TriggerHealth) <= 0 {
+		return errors.New("target health must be above a positive trigger health")
+	}
+	if p.MaxSpend.Sign() <= 0 {
+		return errors.New("max spend must be positive")
+	}
+	switch p.Action {
+	case Repay:
+		if p.RepayToken == "" {
+			return errors.New("repay token is required")
+		}
+	case Supply:
+		if p.CollateralToken == "" {
+			return errors.New("collateral token is required")
+		}
+	case RedeemRepay:
+		if p.RepayToken == "" || p.CollateralToken == "" {
+			return errors.New("repay and collateral tokens are required")
+		}
+	default:
+		return fmt.Errorf("unknown action %q", p.Action)
+	}
+	return nil
+}
+
+// Option is used to configure a Protector
+type Option func(*Protector)
+
+// WithDryRun plans and records the actions without sending any transaction
+func WithDryRun(dryRun bool) Option {
+	return func(p *Protector) {
+		p.dryRun = dryRun
+	}
+}
+
+// WithAuditLog records every action to log
+func WithAuditLog(log AuditLog) Option {
+	return func(p *Protector) {
+		p.audit = log
+	}
+}
+
+// Protector is used to take the actions of the policies of accounts nearing
+// liquidation. It is opt-in: only accounts with a policy are acted on
+type Protector struct {
+	accounts client.AccountSource
+	markets  client.MarketSource
+	exec     Executor
+	audit    AuditLog
+	dryRun   bool
+	now      func() time.Time
+
+	// run serializes checks, so that two events for an account never act twice
+	run      sync.Mutex
+	mu       sync.Mutex
+	policies map[string]Policy
+	spent    map[string]models.Decimal
+}
+
+// NewProtector returns a protector reading accounts and markets from the
+// sources, ex. a ChainSource for both, and sending transactions through exec
+func NewProtector(accounts client.AccountSource, markets client.MarketSource, exec Executor, opts ...Option) *Protector {
+	p := &Protector{
+		accounts: accounts,
+		markets:  markets,
+		exec:     exec,
+		audit:    &MemoryAuditLog{},
+		now:      time.Now,
+		policies: make(map[string]Policy),
+		spent:    make(map[string]models.Decimal),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// SetPolicy sets the policy of an account, replacing any previous one
+func (p *Protector) SetPolicy(policy Policy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.policies[strings.ToLower(policy.Account)] = policy
+	return nil
+}
+
+// RemovePolicy stops protecting an account
+func (p *Protector) RemovePolicy(account string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.policies, strings.ToLower(account))
+}
+
+// Spent returns the value in eth spent protecting an account
+func (p *Protector) Spent(account string) models.Decimal {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.spent[strings.ToLower(account)]
+}
+
+// Run checks the account of every warn or risk event read from events, until
+// the channel is closed or ctx is done. Errors are passed to onError, which
+// may be nil
+func (p *Protector) Run(ctx context.Context, events <-chan client.HealthEvent, onError func(error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Err != nil || (event.State != client.HealthWarn && event.State != client.HealthRisk) {
+				continue
+			}
+			p.mu.Lock()
+			_, ok = p.policies[strings.ToLower(event.Address)]
+			p.mu.Unlock()
+			if !ok {
+				continue
+			}
+			if _, err := p.Check(ctx, event.Address); err != nil && onError != nil {
+				onError(fmt.Errorf("account %s: %w", event.Address, err))
+			}
+		}
+	}
+}
+
+// position is the part of an account a policy acts on, values are in eth
+type position struct {
+	collateral models.Decimal
+	borrowed   models.Decimal
+	// supplied and owed are the underlying balances of the collateral and
+	// repay tokens
+	supplied models.Decimal
+	owed     models.Decimal
+}
+
+func (pos position) health() models.Decimal {
+	if pos.borrowed.Sign() <= 0 {
+		return models.Decimal{}
+	}
+	return pos.collateral.Quo(pos.borrowed).Round(18)
+}
+
+// Check takes the policy's action if the account's health is at or below its
+// trigger, and returns the entries of the transactions sent, or planned in
+// dry-run mode. In dry-run mode later steps are planned from the projected
+// position, otherwise the account is read again after each step
+func (p *Protector) Check(ctx context.Context, address string) ([]AuditEntry, error) {
+	p.run.Lock()
+	defer p.run.Unlock()
+	key := strings.ToLower(address)
+	p.mu.Lock()
+	policy, ok := p.policies[key]
+	p.mu.Unlock()
+	if !ok {
+		return nil, ErrNoPolicy
+	}
+	var repayMarket, collateralMarket *models.CToken
+	var err error
+	if policy.RepayToken != "" {
+		if repayMarket, err = p.markets.CToken(ctx, policy.RepayToken.String()); err != nil {
+			return nil, err
+		}
+	}
+	if policy.CollateralToken != "" {
+		if collateralMarket, err = p.markets.CToken(ctx, policy.CollateralToken.String()); err != nil {
+			return nil, err
+		}
+	}
+	// plans and amounts are derived by dividing by the prices
+	for _, m := range []*models.CToken{repayMarket, collateralMarket} {
+		if m != nil && m.UnderlyingPrice.Value.Sign() <= 0 {
+			return nil, fmt.Errorf("market %s has no price", m.TokenAddress)
+		}
+	}
+	pos, err := p.position(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+	if pos.borrowed.Sign() == 0 || pos.health().Cmp(policy.TriggerHealth) > 0 {
+		return nil, nil
+	}
+	maxSteps := policy.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = DefaultMaxSteps
+	}
+	// a dry-run tracks its spend locally, leaving the budget untouched
+	p.mu.Lock()
+	spent := p.spent[key]
+	p.mu.Unlock()
+	var entries []AuditEntry
+	for step := 1; step <= maxSteps && pos.health().Cmp(policy.TargetHealth) < 0; step++ {
+		value, next, err := p.plan(policy, pos, policy.MaxSpend.Sub(spent), repayMarket, collateralMarket)
+		if err != nil {
+			return entries, err
+		}
+		stepEntries, err := p.execute(ctx, policy, step, value, pos, next, repayMarket, collateralMarket)
+		entries = append(entries, stepEntries...)
+		if p.dryRun {
+			if err != nil {
+				return entries, err
+			}
+			spent = spent.Add(value)
+			pos = next
+			continue
+		}
+		// a step failing after some of its transactions were sent, ex. a
+		// repay failing after its redeem, still spent the step's value
+		if sentAny(stepEntries) {
+			spent = spent.Add(value)
+			p.mu.Lock()
+			p.spent[key] = spent
+			p.mu.Unlock()
+		}
+		if err != nil {
+			return entries, err
+		}
+		if pos, err = p.position(ctx, policy); err != nil {
+			return entries, err
+		}
+	}
+	return entries, nil
+}
+
+// position reads the account's position
+func (p *Protector) position(ctx context.Context, policy Policy) (position, error) {
+	acct, err := p.accounts.Account(ctx, policy.Account)
+	if err != nil {
+		return position{}, err
+	}
+	pos := position{collateral: acct.TotalCollateralValueInEth.Value, borrowed: acct.TotalBorrowValueInEth.Value}
+	for _, tkn := range acct.Tokens {
+		if policy.CollateralToken != "" && strings.EqualFold(tkn.Address, policy.CollateralToken.String()) {
+			pos.supplied = tkn.SupplyBalanceUnderlying.Value
+		}
+		if policy.RepayToken != "" && strings.EqualFold(tkn.Address, policy.RepayToken.String()) {
+			pos.owed = tkn.BorrowBalanceUnderlying.Value
+		}
+	}
+	return pos, nil
+}
+
+// plan returns the value in eth of the next step, and the position it is
+// expected to lead to. Collateral values are risk adjusted, so supplying or
+// redeeming x of collateral changes them by x times the collateral factor
+func (p *Protector) plan(policy Policy, pos position, budget models.Decimal, repayMarket, collateralMarket *models.CToken) (models.Decimal, position, error) {
+	target := policy.TargetHealth
+	// shortfall of collateral at the target health, t * b - c
+	missing := target.Mul(pos.borrowed).Sub(pos.collateral)
+	var value, limit models.Decimal
+	switch policy.Action {
+	case Repay:
+		// c / (b - x) = t
+		value = missing.Quo(target)
+		limit = pos.owed.Mul(repayMarket.UnderlyingPrice.Value)
+	case Supply:
+		// (c + cf x) / b = t
+		cf := collateralMarket.CollateralFactor.Value
+		if cf.Sign() <= 0 {
+			return models.Decimal{}, pos, errors.New("collateral token has no collateral factor")
+		}
+		value = missing.Quo(cf)
+	case RedeemRepay:
+		// (c - cf x) / (b - x) = t
+		cf := collateralMarket.CollateralFactor.Value
+		if target.Cmp(cf) <= 0 {
+			return models.Decimal{}, pos, errors.New("target health must be above the collateral factor to redeem and repay")
+		}
+		value = missing.Quo(target.Sub(cf))
+		limit = pos.owed.Mul(repayMarket.UnderlyingPrice.Value)
+		if supplied := pos.supplied.Mul(collateralMarket.UnderlyingPrice.Value); supplied.LessThan(limit) {
+			limit = supplied
+		}
+	}
+	if budget.Sign() <= 0 {
+		return models.Decimal{}, pos, ErrBudgetExhausted
+	}
+	value = minDecimal(value, budget)
+	if policy.MaxStepValue.Sign() > 0 {
+		value = minDecimal(value, policy.MaxStepValue)
+	}
+	if policy.Action != Supply {
+		value = minDecimal(value, limit)
+	}
+	value = floor(value)
+	if value.Sign() <= 0 {
+		return models.Decimal{}, pos, errors.New("nothing left to repay or redeem")
+	}
+	next := pos
+	switch policy.Action {
+	case Repay:
+		next.borrowed = pos.borrowed.Sub(value)
+		next.owed = pos.owed.Sub(value.Quo(repayMarket.UnderlyingPrice.Value))
+	case Supply:
+		next.collateral = pos.collateral.Add(value.Mul(collateralMarket.CollateralFactor.Value))
+		next.supplied = pos.supplied.Add(value.Quo(collateralMarket.UnderlyingPrice.Value))
+	case RedeemRepay:
+		next.collateral = pos.collateral.Sub(value.Mul(collateralMarket.CollateralFactor.Value))
+		next.borrowed = pos.borrowed.Sub(value)
+		next.supplied = pos.supplied.Sub(value.Quo(collateralMarket.UnderlyingPrice.Value))
+		next.owed = pos.owed.Sub(value.Quo(repayMarket.UnderlyingPrice.Value))
+	}
+	return value, next, nil
+}
+
+// execute sends, or in dry-run mode only records, the transactions of a step
+func (p *Protector) execute(ctx context.Context, policy Policy, step int, value models.Decimal, pos, next position, repayMarket, collateralMarket *models.CToken) ([]AuditEntry, error) {
+	type operation struct {
+		name   string
+		market *models.CToken
+		send   func(context.Context, client.Address, models.Decimal) error
+	}
+	var ops []operation
+	switch policy.Action {
+	case Repay:
+		ops = []operation{{"repay", repayMarket, p.exec.RepayBorrow}}
+	case Supply:
+		ops = []operation{{"supply", collateralMarket, p.exec.Supply}}
+	case RedeemRepay:
+		ops = []operation{{"redeem", collateralMarket, p.exec.RedeemUnderlying}, {"repay", repayMarket, p.exec.RepayBorrow}}
+	}
+	var entries []AuditEntry
+	for _, op := range ops {
+		entry := AuditEntry{
+			Time:           p.now(),
+			Account:        strings.ToLower(policy.Account),
+			Action:         policy.Action,
+			Operation:      op.name,
+			Step:           step,
+			CToken:         strings.ToLower(op.market.TokenAddress),
+			Amount:         floor(value.Quo(op.market.UnderlyingPrice.Value)),
+			ValueInEth:     value,
+			HealthBefore:   pos.health(),
+			ExpectedHealth: next.health(),
+			DryRun:         p.dryRun,
+		}
+		var err error
+		if !p.dryRun {
+			err = op.send(ctx, client.Address(op.market.TokenAddress), entry.Amount)
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		entries = append(entries, entry)
+		if auditErr := p.audit.Record(entry); auditErr != nil && err == nil {
+			err = fmt.Errorf("recording audit entry: %w", auditErr)
+		}
+		if err != nil {
+			return entries, err
+		}
+	}
+	return entries, nil
+}
+
+// sentAny reports whether any of the entries is a transaction that was sent
+func sentAny(entries []AuditEntry) bool {
+	for _, entry := range entries {
+		if !entry.DryRun && entry.Error == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func minDecimal(a, b models.Decimal) models.Decimal {
+	if b.LessThan(a) {
+		return b
+	}
+	return a
+}
+
+// floor rounds x down to 18 decimal places
+func floor(x models.Decimal) models.Decimal {
+	return models.NewDecimalFromInt(x.Shift(18).Int(), 18)
+}
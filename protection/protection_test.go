@@ -0,0 +1,345 @@
+package protection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/musinit/go-defi/v2/client"
+	"github.com/musinit/go-defi/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	owner = "0xe18999d3f7e1a84e35bf9c15699b79e46eb44704"
+	d     = models.MustParseDecimal
+)
+
+// book is a single account across an ether market priced at 1 with a
+// collateral factor of 0.75, a dai market priced at 0.0005 with a
+// collateral factor of 0.8, and a bat market without a price. It is the
+// AccountSource, MarketSource and Executor of the tests, so transactions
+// change what is read next
+type book struct {
+	mu       sync.Mutex
+	supplied map[client.Address]models.Decimal
+	borrowed map[client.Address]models.Decimal
+	sent     []string
+	err      error
+	// failOn fails only the given operation
+	failOn string
+}
+
+var markets = map[client.Address]*models.CToken{
+	client.CompoundETH: {
+		TokenAddress:     strings.ToLower(client.CompoundETH.String()),
+		UnderlyingPrice:  models.Value{Value: d("1")},
+		CollateralFactor: models.Value{Value: d("0.75")},
+	},
+	client.CompoundDAI: {
+		TokenAddress:     strings.ToLower(client.CompoundDAI.String()),
+		UnderlyingPrice:  models.Value{Value: d("0.0005")},
+		CollateralFactor: models.Value{Value: d("0.8")},
+	},
+	client.CompoundBAT: {
+		TokenAddress:     strings.ToLower(client.CompoundBAT.String()),
+		CollateralFactor: models.Value{Value: d("0.6")},
+	},
+}
+
+func newBook(supplied, borrowed map[client.Address]string) *book {
+	b := &book{supplied: map[client.Address]models.Decimal{}, borrowed: map[client.Address]models.Decimal{}}
+	for token, amount := range supplied {
+		b.supplied[token] = d(amount)
+	}
+	for token, amount := range borrowed {
+		b.borrowed[token] = d(amount)
+	}
+	return b
+}
+
+func market(address string) (client.Address, *models.CToken) {
+	for token, m := range markets {
+		if strings.EqualFold(token.String(), address) {
+			return token, m
+		}
+	}
+	return "", nil
+}
+
+func (b *book) Account(ctx context.Context, address string) (*models.Account, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	acct := &models.Account{Address: address}
+	var collateral, borrowed models.Decimal
+	for token, m := range markets {
+		price := m.UnderlyingPrice.Value
+		collateral = collateral.Add(b.supplied[token].Mul(price).Mul(m.CollateralFactor.Value))
+		borrowed = borrowed.Add(b.borrowed[token].Mul(price))
+		acct.Tokens = append(acct.Tokens, models.AccountToken{
+			Address:                 m.TokenAddress,
+			SupplyBalanceUnderlying: models.Value{Value: b.supplied[token]},
+			BorrowBalanceUnderlying: models.Value{Value: b.borrowed[token]},
+		})
+	}
+	acct.TotalCollateralValueInEth = models.Value{Value: collateral}
+	acct.TotalBorrowValueInEth = models.Value{Value: borrowed}
+	if borrowed.Sign() > 0 {
+		acct.Health = models.Value{Value: collateral.Quo(borrowed).Round(18)}
+	}
+	return acct, nil
+}
+
+func (b *book) AtRisk(ctx context.Context, maxHealth models.Decimal) ([]models.Account, error) {
+	return nil, nil
+}
+
+func (b *book) CToken(ctx context.Context, address string) (*models.CToken, error) {
+	if _, m := market(address); m != nil {
+		return m, nil
+	}
+	return nil, client.ErrMarketNotFound
+}
+
+func (b *book) apply(op string, ctoken client.Address, amount models.Decimal, balances map[client.Address]models.Decimal, sign int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return b.err
+	}
+	if op == b.failOn {
+		return errors.New(op + " reverted")
+	}
+	token, _ := market(ctoken.String())
+	if sign > 0 {
+		balances[token] = balances[token].Add(amount)
+	} else {
+		balances[token] = balances[token].Sub(amount)
+	}
+	b.sent = append(b.sent, op+" "+amount.String())
+	return nil
+}
+
+func (b *book) RepayBorrow(ctx context.Context, ctoken client.Address, amount models.Decimal) error {
+	return b.apply("repay", ctoken, amount, b.borrowed, -1)
+}
+
+func (b *book) Supply(ctx context.Context, ctoken client.Address, amount models.Decimal) error {
+	return b.apply("supply", ctoken, amount, b.supplied, 1)
+}
+
+func (b *book) RedeemUnderlying(ctx context.Context, ctoken client.Address, amount models.Decimal) error {
+	return b.apply("redeem", ctoken, amount, b.supplied, -1)
+}
+
+func (b *book) health(t *testing.T) string {
+	acct, err := b.Account(context.Background(), owner)
+	require.NoError(t, err)
+	return acct.Health.Value.String()
+}
+
+func Test_Policy_Validate(t *testing.T) {
+	valid := Policy{Account: owner, Action: Repay, RepayToken: client.CompoundDAI, TriggerHealth: d("1.1"), TargetHealth: d("1.5"), MaxSpend: d("1")}
+	require.NoError(t, valid.Validate())
+	for _, change := range []func(*Policy){
+		func(p *Policy) { p.Account = "me" },
+		func(p *Policy) { p.TargetHealth = d("1") },
+		func(p *Policy) { p.MaxSpend = models.Decimal{} },
+		func(p *Policy) { p.Action = Supply },
+		func(p *Policy) { p.Action = "sell" },
+	} {
+		policy := valid
+		change(&policy)
+		assert.Error(t, policy.Validate())
+	}
+}
+
+func Test_Protector_Repay(t *testing.T) {
+	// 0.75 of collateral against 0.7 of borrows
+	b := newBook(map[client.Address]string{client.CompoundETH: "1"}, map[client.Address]string{client.CompoundDAI: "1400"})
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := NewFileAuditLog(path)
+	require.NoError(t, err)
+	p := NewProtector(b, b, b, WithAuditLog(audit))
+	ctx := context.Background()
+
+	_, err = p.Check(ctx, owner)
+	assert.True(t, errors.Is(err, ErrNoPolicy))
+	require.NoError(t, p.SetPolicy(Policy{
+		Account:       owner,
+		Action:        Repay,
+		RepayToken:    client.CompoundDAI,
+		TriggerHealth: d("1.1"),
+		TargetHealth:  d("1.5"),
+		MaxSpend:      d("1"),
+	}))
+
+	entries, err := p.Check(ctx, owner)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	// (0.75 / 1.5) = 0.5 of borrows left, so 0.2 or 400 dai is repaid
+	assert.Equal(t, "0.2", entries[0].ValueInEth.String())
+	assert.Equal(t, "400", entries[0].Amount.String())
+	assert.Equal(t, "1.5", entries[0].ExpectedHealth.String())
+	assert.Equal(t, []string{"repay 400"}, b.sent)
+	assert.Equal(t, "1.5", b.health(t))
+	assert.Equal(t, "0.2", p.Spent(owner).String())
+
+	// above the trigger nothing happens
+	entries, err = p.Check(ctx, owner)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var logged AuditEntry
+	require.NoError(t, json.Unmarshal(data, &logged))
+	assert.Equal(t, "repay", logged.Operation)
+	assert.Equal(t, "400", logged.Amount.String())
+	assert.False(t, logged.DryRun)
+}
+
+func Test_Protector_Limits(t *testing.T) {
+	b := newBook(map[client.Address]string{client.CompoundETH: "1"}, map[client.Address]string{client.CompoundDAI: "1400"})
+	log := &MemoryAuditLog{}
+	p := NewProtector(b, b, b, WithAuditLog(log))
+	require.NoError(t, p.SetPolicy(Policy{
+		Account:       owner,
+		Action:        Repay,
+		RepayToken:    client.CompoundDAI,
+		TriggerHealth: d("1.1"),
+		TargetHealth:  d("1.5"),
+		MaxSpend:      d("0.1"),
+	}))
+	entries, err := p.Check(context.Background(), owner)
+	assert.True(t, errors.Is(err, ErrBudgetExhausted))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "200", entries[0].Amount.String())
+	assert.Equal(t, "0.1", p.Spent(owner).String())
+
+	b.err = errors.New("reverted")
+	require.NoError(t, p.SetPolicy(Policy{
+		Account:         owner,
+		Action:          Supply,
+		CollateralToken: client.CompoundETH,
+		TriggerHealth:   d("1.3"),
+		TargetHealth:    d("1.5"),
+		MaxSpend:        d("10"),
+	}))
+	entries, err = p.Check(context.Background(), owner)
+	require.Error(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "reverted", entries[0].Error)
+	assert.Len(t, log.Entries(), 2)
+}
+
+func Test_Protector_DryRun(t *testing.T) {
+	b := newBook(map[client.Address]string{client.CompoundETH: "1"}, map[client.Address]string{client.CompoundDAI: "1400"})
+	p := NewProtector(b, b, b, WithDryRun(true))
+	require.NoError(t, p.SetPolicy(Policy{
+		Account:         owner,
+		Action:          Supply,
+		CollateralToken: client.CompoundETH,
+		TriggerHealth:   d("1.1"),
+		TargetHealth:    d("1.5"),
+		MaxSpend:        d("10"),
+	}))
+	entries, err := p.Check(context.Background(), owner)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	// (0.75 + 0.75 x) / 0.7 = 1.5
+	assert.Equal(t, "0.4", entries[0].Amount.String())
+	assert.Equal(t, "1.5", entries[0].ExpectedHealth.String())
+	assert.True(t, entries[0].DryRun)
+	assert.Empty(t, b.sent)
+	assert.Equal(t, "0", p.Spent(owner).String())
+}
+
+func Test_Protector_RedeemRepay(t *testing.T) {
+	// 1.2 of collateral against 1.1 of borrows, all in dai
+	b := newBook(map[client.Address]string{client.CompoundDAI: "3000"}, map[client.Address]string{client.CompoundDAI: "2200"})
+	p := NewProtector(b, b, b)
+	require.NoError(t, p.SetPolicy(Policy{
+		Account:         owner,
+		Action:          RedeemRepay,
+		CollateralToken: client.CompoundDAI,
+		RepayToken:      client.CompoundDAI,
+		TriggerHealth:   d("1.1"),
+		TargetHealth:    d("1.2"),
+		MaxSpend:        d("1"),
+		MaxStepValue:    d("0.1"),
+	}))
+	events := make(chan client.HealthEvent, 2)
+	events <- client.HealthEvent{Address: owner, State: client.HealthSafe}
+	events <- client.HealthEvent{Address: owner, State: client.HealthWarn}
+	close(events)
+	p.Run(context.Background(), events, func(err error) { t.Error(err) })
+
+	// (1.2 - 0.8 x) / (1.1 - x) = 1.2 needs 0.3, in steps of 0.1
+	assert.Equal(t, []string{"redeem 200", "repay 200", "redeem 200", "repay 200", "redeem 200", "repay 200"}, b.sent)
+	assert.Equal(t, "1.2", b.health(t))
+	assert.Equal(t, "0.3", p.Spent(owner).String())
+
+	assert.Error(t, p.SetPolicy(Policy{
+		Account:         owner,
+		Action:          RedeemRepay,
+		CollateralToken: client.CompoundDAI,
+		RepayToken:      client.CompoundDAI,
+		TriggerHealth:   d("1.3"),
+		TargetHealth:    d("0.5"),
+		MaxSpend:        d("1"),
+	}))
+}
+
+func Test_Protector_Unpriced(t *testing.T) {
+	b := newBook(map[client.Address]string{client.CompoundETH: "1", client.CompoundBAT: "100"}, map[client.Address]string{client.CompoundDAI: "1400", client.CompoundBAT: "10"})
+	p := NewProtector(b, b, b)
+	for _, policy := range []Policy{
+		{Account: owner, Action: Repay, RepayToken: client.CompoundBAT, TriggerHealth: d("1.1"), TargetHealth: d("1.5"), MaxSpend: d("1")},
+		{Account: owner, Action: Supply, CollateralToken: client.CompoundBAT, TriggerHealth: d("1.1"), TargetHealth: d("1.5"), MaxSpend: d("1")},
+		{Account: owner, Action: RedeemRepay, CollateralToken: client.CompoundBAT, RepayToken: client.CompoundDAI, TriggerHealth: d("1.1"), TargetHealth: d("1.5"), MaxSpend: d("1")},
+	} {
+		require.NoError(t, p.SetPolicy(policy))
+		entries, err := p.Check(context.Background(), owner)
+		require.Error(t, err, policy.Action)
+		assert.Contains(t, err.Error(), "has no price")
+		assert.Empty(t, entries)
+	}
+	assert.Empty(t, b.sent)
+}
+
+func Test_Protector_PartialStep(t *testing.T) {
+	b := newBook(map[client.Address]string{client.CompoundDAI: "3000"}, map[client.Address]string{client.CompoundDAI: "2200"})
+	b.failOn = "repay"
+	log := &MemoryAuditLog{}
+	p := NewProtector(b, b, b, WithAuditLog(log))
+	require.NoError(t, p.SetPolicy(Policy{
+		Account:         owner,
+		Action:          RedeemRepay,
+		CollateralToken: client.CompoundDAI,
+		RepayToken:      client.CompoundDAI,
+		TriggerHealth:   d("1.1"),
+		TargetHealth:    d("1.2"),
+		MaxSpend:        d("0.1"),
+		MaxStepValue:    d("0.1"),
+	}))
+	entries, err := p.Check(context.Background(), owner)
+	require.Error(t, err)
+	require.Len(t, entries, 2)
+	assert.Empty(t, entries[0].Error)
+	assert.Equal(t, "repay reverted", entries[1].Error)
+	assert.Equal(t, []string{"redeem 200"}, b.sent)
+	// the redeem was sent, so its value counts against the budget
+	assert.Equal(t, "0.1", p.Spent(owner).String())
+
+	b.failOn = ""
+	_, err = p.Check(context.Background(), owner)
+	assert.True(t, errors.Is(err, ErrBudgetExhausted))
+	assert.Equal(t, []string{"redeem 200"}, b.sent)
+}
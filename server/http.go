@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/musinit/go-defi/v2/internal/apiutil"
+	"github.com/musinit/go-defi/v2/models"
+)
+
+// apiPrefix is the path prefix of the public api, requests are accepted with
+// or without it so that both https://host and https://host/api/v2 work as the
+// base url of a client
+const apiPrefix = "/api/v2"
+
+// ServeHTTP serves the REST shape of the services, as annotated in
+// pb/account.proto and pb/ctoken.proto: GET and POST on /account and /ctoken
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	switch strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, apiPrefix), "/") {
+	case "/account":
+		req, fieldErrors := apiutil.ParseAccountRequest(r)
+		if len(fieldErrors) > 0 {
+			writeResponse(w, &models.AccountResponse{Error: invalidRequest(fieldErrors)}, invalidRequest(fieldErrors))
+			return
+		}
+		resp := s.GetAccounts(r.Context(), *req)
+		writeResponse(w, resp, resp.Error)
+	case "/ctoken":
+		req, fieldErrors := apiutil.ParseCTokenRequest(r)
+		if len(fieldErrors) > 0 {
+			writeResponse(w, &models.CTokenResponse{Error: invalidRequest(fieldErrors)}, invalidRequest(fieldErrors))
+			return
+		}
+		resp := s.GetCTokens(r.Context(), *req)
+		writeResponse(w, resp, resp.Error)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// writeResponse writes a response body, with a status matching its api error
+func writeResponse(w http.ResponseWriter, body interface{}, apiErr *models.APIError) {
+	status := http.StatusOK
+	switch {
+	case apiErr == nil:
+	case apiErr.Code == models.InvalidPageNumber || apiErr.Code == models.InvalidPageSize || apiErr.Code == models.InvalidRequest:
+		status = http.StatusBadRequest
+	default:
+		status = http.StatusInternalServerError
+	}
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,226 @@
+// Package server implements the compound AccountService and CTokenService
+// from on chain reads, over both REST and gRPC, so that the client and the
+// command line tools can run against a self hosted endpoint
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/compound"
+	"github.com/musinit/go-defi/v2/internal/apiutil"
+	"github.com/musinit/go-defi/v2/models"
+	pb "github.com/musinit/go-defi/v2/pb"
+	"google.golang.org/grpc"
+)
+
+const (
+	// DefaultPageSize is the page size used when a request does not set one
+	DefaultPageSize = apiutil.DefaultPageSize
+	// MaxPageSize is the largest page size accepted
+	MaxPageSize = apiutil.MaxPageSize
+	// DefaultBlocksPerYear is used to annualize per block rates, assuming 12s blocks
+	DefaultBlocksPerYear = compound.DefaultBlocksPerYear
+	// DefaultConcurrency is the number of accounts or markets read in parallel
+	DefaultConcurrency = compound.DefaultConcurrency
+)
+
+// Option is used to configure a Server
+type Option func(*Server)
+
+// WithAccounts sets the accounts served by requests that do not filter on
+// addresses. Accounts can not be listed from the chain without an index, so
+// without this option such requests return no accounts
+func WithAccounts(addresses ...string) Option {
+	return func(s *Server) {
+		s.accounts = append(s.accounts, addresses...)
+	}
+}
+
+// WithBlocksPerYear sets the number of blocks used to annualize the per block
+// supply and borrow rates
+func WithBlocksPerYear(blocks int64) Option {
+	return func(s *Server) {
+		s.blocksPerYear = blocks
+	}
+}
+
+// WithConcurrency sets the number of accounts or markets read in parallel
+func WithConcurrency(n int) Option {
+	return func(s *Server) {
+		s.concurrency = n
+	}
+}
+
+// Server serves the AccountService and CTokenService from a compound.Backend,
+// through a compound.Reader. Fields without an on chain equivalent, ex.
+// lifetime interest accrued or the number of suppliers, are returned as 0, and
+// the tokens of an account only cover the markets it entered
+type Server struct {
+	reader        *compound.Reader
+	accounts      []string
+	blocksPerYear int64
+	concurrency   int
+}
+
+// New returns a server reading from backend
+func New(backend compound.Backend, opts ...Option) *Server {
+	s := &Server{
+		blocksPerYear: DefaultBlocksPerYear,
+		concurrency:   DefaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.concurrency <= 0 {
+		s.concurrency = DefaultConcurrency
+	}
+	s.reader = compound.NewReader(backend, compound.WithBlocksPerYear(s.blocksPerYear), compound.WithConcurrency(s.concurrency))
+	return s
+}
+
+// Register registers the AccountService and CTokenService on a grpc server
+func (s *Server) Register(g *grpc.Server) {
+	pb.RegisterAccountServiceServer(g, s)
+	pb.RegisterCTokenServiceServer(g, s)
+}
+
+// Account implements pb.AccountServiceServer
+func (s *Server) Account(ctx context.Context, req *pb.AccountRequest) (*pb.AccountResponse, error) {
+	request, err := req.Model()
+	if err != nil {
+		return pb.NewAccountResponse(invalidAccountRequest(err))
+	}
+	return pb.NewAccountResponse(s.GetAccounts(ctx, request))
+}
+
+// PostAccount implements pb.AccountServiceServer
+func (s *Server) PostAccount(ctx context.Context, req *pb.AccountRequest) (*pb.AccountResponse, error) {
+	return s.Account(ctx, req)
+}
+
+// Ctoken implements pb.CTokenServiceServer
+func (s *Server) Ctoken(ctx context.Context, req *pb.CTokenRequest) (*pb.CTokenResponse, error) {
+	request, err := req.Model()
+	if err != nil {
+		return pb.NewCTokenResponse(&models.CTokenResponse{Error: invalidRequest(map[string]string{"addresses": err.Error()})})
+	}
+	return pb.NewCTokenResponse(s.GetCTokens(ctx, request))
+}
+
+// PostCtoken implements pb.CTokenServiceServer
+func (s *Server) PostCtoken(ctx context.Context, req *pb.CTokenRequest) (*pb.CTokenResponse, error) {
+	return s.Ctoken(ctx, req)
+}
+
+// GetAccounts computes the response to an account request. Failures are
+// reported through the Error field of the response, like the compound api
+func (s *Server) GetAccounts(ctx context.Context, req models.Request) *models.AccountResponse {
+	resp := &models.AccountResponse{Request: req}
+	if fieldErrors := validateRequest(req.Addresses, req.BlockTimestamp); len(fieldErrors) > 0 {
+		resp.Error = invalidRequest(fieldErrors)
+		return resp
+	}
+	addresses := req.Addresses
+	if len(addresses) == 0 {
+		addresses = s.accounts
+	}
+	filtered := req.MaxHealth != nil || req.MinBorrowValueInEth != nil
+	if !filtered {
+		// without filters only the requested page has to be read
+		summary, start, end, apiErr := apiutil.Paginate(len(addresses), req.PageSize, req.PageNumber)
+		if apiErr != nil {
+			resp.Error = apiErr
+			return resp
+		}
+		resp.PaginationSummary, addresses = summary, addresses[start:end]
+	}
+
+	st, err := s.reader.State(ctx, uint64(req.BlockNumber), nil)
+	if err != nil {
+		resp.Error = internalError(err)
+		return resp
+	}
+	resp.CloseFactor = models.NewNumber(models.NewDecimalFromInt(st.Comptroller.CloseFactor, 18))
+	resp.LiquidationIncentive = models.NewNumber(models.NewDecimalFromInt(st.Comptroller.LiquidationIncentive, 18))
+
+	accounts := make([]models.Account, len(addresses))
+	err = apiutil.Parallel(ctx, len(addresses), s.concurrency, func(ctx context.Context, i int) error {
+		acct, err := s.reader.Account(ctx, st, common.HexToAddress(addresses[i]))
+		if err != nil {
+			return fmt.Errorf("account %s: %w", addresses[i], err)
+		}
+		accounts[i] = *acct
+		return nil
+	})
+	if err != nil {
+		resp.Error = internalError(err)
+		return resp
+	}
+
+	if filtered {
+		var matched []models.Account
+		for _, acct := range accounts {
+			if req.MaxHealth != nil && (acct.TotalBorrowValueInEth.Value.IsZero() || acct.Health.Value.GreaterThan(req.MaxHealth.Value)) {
+				continue
+			}
+			if req.MinBorrowValueInEth != nil && acct.TotalBorrowValueInEth.Value.LessThan(req.MinBorrowValueInEth.Value) {
+				continue
+			}
+			matched = append(matched, acct)
+		}
+		summary, start, end, apiErr := apiutil.Paginate(len(matched), req.PageSize, req.PageNumber)
+		if apiErr != nil {
+			resp.Error = apiErr
+			return resp
+		}
+		resp.PaginationSummary, accounts = summary, matched[start:end]
+	}
+	resp.Accounts = append([]models.Account{}, accounts...)
+	resp.Request.PageNumber = resp.PaginationSummary.PageNumber
+	resp.Request.PageSize = resp.PaginationSummary.PageSize
+	return resp
+}
+
+// GetCTokens computes the response to a ctoken request. Failures are
+// reported through the Error field of the response, like the compound api
+func (s *Server) GetCTokens(ctx context.Context, req models.CTokenRequest) *models.CTokenResponse {
+	resp := &models.CTokenResponse{Request: req}
+	if fieldErrors := validateRequest(req.Addresses, req.BlockTimestamp); len(fieldErrors) > 0 {
+		resp.Error = invalidRequest(fieldErrors)
+		return resp
+	}
+	st, err := s.reader.State(ctx, uint64(req.BlockNumber), req.Addresses)
+	if err != nil {
+		resp.Error = internalError(err)
+		return resp
+	}
+	resp.CToken = append([]models.CToken{}, st.CTokens...)
+	return resp
+}
+
+func validateRequest(addresses []string, blockTimestamp int) map[string]string {
+	fieldErrors := map[string]string{}
+	for _, address := range addresses {
+		if !common.IsHexAddress(address) {
+			fieldErrors["addresses"] = fmt.Sprintf("invalid address %q", address)
+		}
+	}
+	if blockTimestamp != 0 {
+		fieldErrors["block_timestamp"] = "not supported, use block_number"
+	}
+	return fieldErrors
+}
+
+func invalidAccountRequest(err error) *models.AccountResponse {
+	return &models.AccountResponse{Error: invalidRequest(map[string]string{"request": err.Error()})}
+}
+
+func invalidRequest(fieldErrors map[string]string) *models.APIError {
+	return &models.APIError{Code: models.InvalidRequest, Message: "invalid request", FieldErrors: fieldErrors}
+}
+
+func internalError(err error) *models.APIError {
+	return &models.APIError{Code: models.InternalError, Message: err.Error()}
+}
@@ -0,0 +1,226 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/musinit/go-defi/v2/client"
+	"github.com/musinit/go-defi/v2/compound"
+	"github.com/musinit/go-defi/v2/models"
+	pb "github.com/musinit/go-defi/v2/pb"
+	"github.com/musinit/go-defi/v2/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	cETH     = common.HexToAddress("0x4ddc2d193948926d02f9b1fe9e1daa0718270ed5")
+	cDAI     = common.HexToAddress("0x5d3a536e4d6dbd6114cc1ead35777bab948e3643")
+	dai      = common.HexToAddress("0x6b175474e89094c44da98b954eedeac495271d0f")
+	borrower = common.HexToAddress("0xe18999d3f7e1a84e35bf9c15699b79e46eb44704")
+	supplier = common.HexToAddress("0x1111111111111111111111111111111111111111")
+)
+
+// exp returns x * 10^n
+func exp(x int64, n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(x), new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil))
+}
+
+// fakeBackend is a deployment with an ether market priced at 2000 and a dai
+// market priced at 1. The borrower supplies 1 ether as collateral and borrows
+// 1000 dai, the supplier supplies dai without entering the market
+type fakeBackend struct {
+	err       error
+	blocks    []uint64
+	snapshots int32
+}
+
+func (f *fakeBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return 100, f.err
+}
+
+func (f *fakeBackend) Comptroller(ctx context.Context, block uint64) (*compound.ComptrollerState, error) {
+	f.blocks = append(f.blocks, block)
+	return &compound.ComptrollerState{
+		CloseFactor:          exp(5, 17),
+		LiquidationIncentive: exp(108, 16),
+		Markets:              []common.Address{cETH, cDAI},
+	}, f.err
+}
+
+func (f *fakeBackend) Market(ctx context.Context, block uint64, address common.Address) (*compound.Market, error) {
+	m := &compound.Market{
+		Address:            address,
+		Decimals:           8,
+		InterestRateModel:  common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		UnderlyingDecimals: 18,
+		TotalSupply:        exp(1000, 8),
+		TotalBorrows:       exp(10, 18),
+		Reserves:           exp(1, 18),
+		Cash:               exp(11, 18),
+		ExchangeRate:       exp(2, 26),
+		SupplyRatePerBlock: big.NewInt(1e9),
+		BorrowRatePerBlock: big.NewInt(2e9),
+		CollateralFactor:   exp(75, 16),
+	}
+	switch address {
+	case cETH:
+		m.Symbol, m.Name, m.UnderlyingSymbol, m.UnderlyingName = "cETH", "Compound Ether", "ETH", "Ether"
+		m.UnderlyingPrice = exp(2000, 18)
+	case cDAI:
+		m.Symbol, m.Name, m.UnderlyingSymbol, m.UnderlyingName = "cDAI", "Compound Dai", "DAI", "Dai Stablecoin"
+		m.Underlying = dai
+		m.UnderlyingPrice = exp(1, 18)
+	default:
+		return nil, errors.New("unknown market")
+	}
+	return m, nil
+}
+
+func (f *fakeBackend) AssetsIn(ctx context.Context, block uint64, account common.Address) ([]common.Address, error) {
+	if account == borrower {
+		return []common.Address{cETH, cDAI}, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeBackend) AccountSnapshot(ctx context.Context, block uint64, account, ctoken common.Address) (*compound.AccountSnapshot, error) {
+	atomic.AddInt32(&f.snapshots, 1)
+	snapshot := &compound.AccountSnapshot{CTokenBalance: new(big.Int), BorrowBalance: new(big.Int), ExchangeRate: exp(2, 26)}
+	switch {
+	case account == borrower && ctoken == cETH:
+		snapshot.CTokenBalance = exp(50, 8)
+	case account == borrower && ctoken == cDAI:
+		snapshot.BorrowBalance = exp(1000, 18)
+	case account == supplier && ctoken == cDAI:
+		snapshot.CTokenBalance = exp(5000, 8)
+	}
+	return snapshot, nil
+}
+
+func newServer(t *testing.T, backend compound.Backend) (*server.Server, *client.Client) {
+	srv := server.New(backend, server.WithAccounts(borrower.Hex(), supplier.Hex()), server.WithBlocksPerYear(1000))
+	api := httptest.NewServer(srv)
+	t.Cleanup(api.Close)
+	return srv, client.NewClient(api.URL)
+}
+
+func Test_Server_Accounts(t *testing.T) {
+	backend := &fakeBackend{}
+	_, cl := newServer(t, backend)
+	ctx := context.Background()
+
+	resp, err := cl.GetAccountContext(ctx, borrower.Hex())
+	require.NoError(t, err)
+	require.Len(t, resp.Accounts, 1)
+	acct := resp.Accounts[0]
+	assert.Equal(t, "0xe18999d3f7e1a84e35bf9c15699b79e46eb44704", acct.Address)
+	assert.Equal(t, "0.75", acct.TotalCollateralValueInEth.Value.String())
+	assert.Equal(t, "0.5", acct.TotalBorrowValueInEth.Value.String())
+	assert.Equal(t, "1.5", acct.Health.Value.String())
+	require.NotNil(t, acct.BlockUpdated)
+	assert.Equal(t, 100, *acct.BlockUpdated)
+	require.Len(t, acct.Tokens, 2)
+	assert.Equal(t, "cETH", acct.Tokens[0].Symbol)
+	assert.Equal(t, "1", acct.Tokens[0].SupplyBalanceUnderlying.Value.String())
+	assert.Equal(t, "1000", acct.Tokens[1].BorrowBalanceUnderlying.Value.String())
+	assert.Equal(t, "0.5", resp.CloseFactor.String())
+	assert.Equal(t, "1.08", resp.LiquidationIncentive.String())
+
+	health, err := cl.GetTotalCollateralValueInEthContext(ctx, borrower.Hex(), client.AtBlock(90))
+	require.NoError(t, err)
+	assert.Equal(t, "0.75", health.String())
+	assert.Equal(t, uint64(90), backend.blocks[len(backend.blocks)-1])
+
+	// only the markets an account entered are read, the supplier entered none
+	atomic.StoreInt32(&backend.snapshots, 0)
+	all, err := cl.QueryAccounts(ctx, client.NewAccountRequest())
+	require.NoError(t, err)
+	assert.Len(t, all.Accounts, 2)
+	assert.Equal(t, 2, all.PaginationSummary.TotalEntries)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&backend.snapshots))
+	assert.Empty(t, all.Accounts[1].Tokens)
+
+	risky, err := cl.QueryAccounts(ctx, client.NewAccountRequest().MaxHealth("2"))
+	require.NoError(t, err)
+	require.Len(t, risky.Accounts, 1)
+	assert.Equal(t, borrower.Hex(), common.HexToAddress(risky.Accounts[0].Address).Hex())
+
+	_, err = cl.QueryAccounts(ctx, client.NewAccountRequest().PageNumber(5))
+	assert.True(t, errors.Is(err, models.ErrInvalidPageNumber), err)
+	_, err = cl.QueryAccounts(ctx, client.NewAccountRequest().BlockTimestamp(1))
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest), err)
+}
+
+func Test_Server_CTokens(t *testing.T) {
+	_, cl := newServer(t, &fakeBackend{})
+	ctx := context.Background()
+
+	resp, err := cl.GetCTokensContext(ctx)
+	require.NoError(t, err)
+	require.Len(t, resp.CToken, 2)
+
+	resp, err = cl.GetCTokenContext(ctx, cDAI.Hex())
+	require.NoError(t, err)
+	require.Len(t, resp.CToken, 1)
+	ctoken := resp.CToken[0]
+	assert.Equal(t, "cDAI", ctoken.Symbol)
+	assert.Equal(t, "0x6b175474e89094c44da98b954eedeac495271d0f", ctoken.UnderlyingAddress)
+	assert.Equal(t, "0.02", ctoken.ExchangeRate.Value.String())
+	assert.Equal(t, "1000", ctoken.TotalSupply.Value.String())
+	assert.Equal(t, "10", ctoken.TotalBorrows.Value.String())
+	assert.Equal(t, "0.000001", ctoken.SupplyRate.Value.String())
+	assert.Equal(t, "0.000002", ctoken.BorrowRate.Value.String())
+	assert.Equal(t, "0.75", ctoken.CollateralFactor.Value.String())
+	assert.Equal(t, "0.0005", ctoken.UnderlyingPrice.Value.String())
+	assert.Equal(t, "0.5", ctoken.Utilization().String())
+}
+
+func Test_Server_Errors(t *testing.T) {
+	backend := &fakeBackend{err: errors.New("node down")}
+	srv, _ := newServer(t, backend)
+	ctx := context.Background()
+
+	resp := srv.GetAccounts(ctx, models.Request{Addresses: []string{borrower.Hex()}})
+	assert.True(t, errors.Is(resp.Err(), models.ErrInternal))
+	ctokens := srv.GetCTokens(ctx, models.CTokenRequest{Addresses: []string{"not an address"}})
+	assert.True(t, errors.Is(ctokens.Err(), models.ErrInvalidRequest))
+	assert.Contains(t, ctokens.Error.FieldErrors, "addresses")
+}
+
+func Test_Server_GRPC(t *testing.T) {
+	srv, _ := newServer(t, &fakeBackend{})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	g := grpc.NewServer()
+	srv.Register(g)
+	go g.Serve(listener)
+	defer g.Stop()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx := context.Background()
+
+	msg, err := pb.NewAccountServiceClient(conn).Account(ctx, &pb.AccountRequest{Addresses: [][]byte{borrower.Bytes()}})
+	require.NoError(t, err)
+	resp, err := msg.Model()
+	require.NoError(t, err)
+	require.Len(t, resp.Accounts, 1)
+	assert.Equal(t, "1.5", resp.Accounts[0].Health.Value.String())
+	assert.Equal(t, "1.08", resp.LiquidationIncentive.String())
+
+	ctokens, err := pb.NewCTokenServiceClient(conn).PostCtoken(ctx, &pb.CTokenRequest{Addresses: [][]byte{cETH.Bytes()}})
+	require.NoError(t, err)
+	require.Len(t, ctokens.CToken, 1)
+	assert.Equal(t, "cETH", ctokens.CToken[0].Symbol)
+	assert.Equal(t, "1", ctokens.CToken[0].UnderlyingPrice.Value)
+}